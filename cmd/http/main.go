@@ -2,29 +2,71 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"regexp"
+	"sort"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/raflynagachi/go-rest-api-starter/config"
 	hn "github.com/raflynagachi/go-rest-api-starter/internal/handler"
 	"github.com/raflynagachi/go-rest-api-starter/internal/handler/router"
 	"github.com/raflynagachi/go-rest-api-starter/internal/repository/postgres"
 	uc "github.com/raflynagachi/go-rest-api-starter/internal/usecase"
+	"github.com/raflynagachi/go-rest-api-starter/migrations"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/database"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/database/migrate"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/health"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/encoder"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/response"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
 )
 
 func main() {
-	appLogger := logger.NewLogger(logger.WithEnv(config.Env))
+	cfg, err := config.LoadConfig(os.Args[1:])
+	if err != nil {
+		config.PrintError(os.Stderr, err)
+		os.Exit(1)
+	}
+	// levels and patterns are checked by config.Validate already, the new* helpers below do not check them again
 
-	cfg, err := config.LoadConfig()
+	sinks, closeSinks, err := newLogSinks(cfg)
 	if err != nil {
-		appLogger.Error("failed to load config", logger.ErrAttr(err))
-		return
+		fmt.Fprintf(os.Stderr, "failed to open log sinks: %v\n", err)
+		os.Exit(1)
+	}
+	defer closeSinks()
+
+	levels := logger.NewLevels(newLogLevel(cfg))
+	logOpts := []logger.Option{
+		logger.WithEnv(config.Env),
+		logger.WithLevels(levels),
+		logger.WithRedaction(newRedactOptions(cfg)),
+		logger.WithSinks(sinks...),
+	}
+	if cfg.Log.Sampling.Enabled {
+		logOpts = append(logOpts, logger.WithSampling(newSampleOptions(cfg, metrics.Default)))
+	}
+	appLogger := logger.NewLogger(logOpts...)
+	defer levels.WatchSignals(appLogger)()
+
+	if cfg.App.ProblemDetails {
+		response.SetErrorFormat(response.ErrorFormatProblem)
+	}
+	if cfg.App.MaxBodyBytes > 0 {
+		encoder.SetMaxBodyBytes(cfg.App.MaxBodyBytes)
 	}
 
+	tracer := newTracer(cfg, appLogger)
+	tracing.SetDefault(tracer)
+
 	db, err := database.ConnectDB(cfg.Databases[config.ServiceName])
 	if err != nil {
 		appLogger.Error("failed to connect database: ", logger.ErrAttr(err))
@@ -32,11 +74,25 @@ func main() {
 	}
 	defer db.Close()
 
-	repo := postgres.New(db, appLogger)
-	usecase := uc.New(cfg, appLogger, repo)
-	handler := hn.New(usecase, appLogger)
+	if cfg.App.AutoMigrate {
+		m, err := migrate.New(db, migrations.FS, appLogger)
+		if err != nil {
+			appLogger.Error("failed to load migrations", logger.ErrAttr(err))
+			return
+		}
+		if err := m.Up(context.Background()); err != nil {
+			appLogger.Error("failed to run migrations", logger.ErrAttr(err))
+			return
+		}
+	}
+
+	repo := postgres.New(db, logger.Named(appLogger, "repository"))
+	usecase := uc.New(cfg, logger.Named(appLogger, "usecase"), repo)
+	handler := hn.New(usecase, logger.Named(appLogger, "handler"))
+
+	metrics.Default.MustRegister(metrics.NewDBStatsCollector(db, config.ServiceName))
 
-	r := router.New(cfg, appLogger, handler)
+	r := router.New(cfg, appLogger, handler, newHealthRegistry(cfg, db), metrics.Default)
 
 	serverErr := make(chan error, 1)
 	go func() {
@@ -57,8 +113,8 @@ func main() {
 		appLogger.Info("received signal: ", logger.StringAttr("signal", sig.String()))
 	}
 
-	// the context is used as timeout to finish currently handling request
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// the context is used as timeout to drain and to finish currently handling request
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Health.ShutdownDelay.Std()+5*time.Second)
 	defer cancel()
 
 	if err := r.Shutdown(ctx); err != nil {
@@ -66,4 +122,133 @@ func main() {
 	} else {
 		appLogger.Info("server exiting")
 	}
+
+	if err := tracer.Shutdown(ctx); err != nil {
+		appLogger.Error("failed to flush spans", logger.ErrAttr(err))
+	}
+}
+
+// newTracer creates the tracer exporting to the configured backend, spans are not exported by default
+func newTracer(cfg *config.Config, log *logger.Logger) *tracing.Tracer {
+	var exporter tracing.Exporter
+	switch cfg.Tracing.Exporter {
+	case config.TracingExporterStdout:
+		exporter = tracing.NewStdoutExporter(os.Stdout)
+	case config.TracingExporterOTLP:
+		exporter = tracing.NewOTLPExporter(cfg.Tracing.Endpoint)
+	}
+
+	return tracing.NewTracer(cfg.App.Name, exporter, log,
+		tracing.WithBatchSize(cfg.Tracing.BatchSize),
+		tracing.WithFlushInterval(cfg.Tracing.FlushInterval.Std()),
+	)
+}
+
+// newLogLevel returns the configured initial log level, info when unset
+func newLogLevel(cfg *config.Config) logger.Level {
+	if cfg.Log.Level == "" {
+		return logger.LevelInfo
+	}
+	level, _ := logger.ParseLevel(cfg.Log.Level)
+	return level
+}
+
+// newLogSinks opens the configured log sinks in name order, closeSinks closes their files
+func newLogSinks(cfg *config.Config) (sinks []logger.Sink, closeSinks func(), err error) {
+	names := make([]string, 0, len(cfg.Log.Sinks))
+	for name := range cfg.Log.Sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := []*logger.RotatingFile{}
+	closeSinks = func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}
+
+	for _, name := range names {
+		sinkCfg := cfg.Log.Sinks[name]
+		sink := logger.Sink{Format: sinkCfg.Format}
+
+		if sinkCfg.Level != "" {
+			sink.Level, _ = logger.ParseLevel(sinkCfg.Level)
+		}
+
+		switch sinkCfg.Output {
+		case config.LogOutputStdout:
+			sink.Writer = os.Stdout
+		case config.LogOutputStderr:
+			sink.Writer = os.Stderr
+		default:
+			file, err := logger.NewRotatingFile(sinkCfg.Output, logger.RotateOptions{
+				MaxSize:    int64(sinkCfg.Rotation.MaxSizeMB) << 20,
+				MaxAge:     sinkCfg.Rotation.MaxAge.Std(),
+				MaxBackups: sinkCfg.Rotation.MaxBackups,
+				Compress:   sinkCfg.Rotation.Compress,
+			})
+			if err != nil {
+				closeSinks()
+				return nil, nil, fmt.Errorf("sink %s: %w", name, err)
+			}
+			files = append(files, file)
+			sink.Writer = file
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, closeSinks, nil
+}
+
+// newSampleOptions creates the log.sampling rules, dropped records are counted in log_records_dropped_total
+func newSampleOptions(cfg *config.Config, registry *metrics.Registry) logger.SampleOptions {
+	sampling := cfg.Log.Sampling
+	opts := logger.SampleOptions{
+		Interval: sampling.Interval.Std(),
+		Default:  logger.SampleRule{First: sampling.First, Thereafter: sampling.Thereafter},
+		Levels:   map[logger.Level]logger.SampleRule{},
+		Exempt:   sampling.Exempt,
+	}
+	for name, rule := range sampling.Levels {
+		level, _ := logger.ParseLevel(name)
+		opts.Levels[level] = logger.SampleRule{First: rule.First, Thereafter: rule.Thereafter}
+	}
+
+	dropped := metrics.NewCounterVec("log_records_dropped_total", "Log records dropped by sampling.", "level")
+	registry.MustRegister(dropped)
+	opts.OnDrop = func(level logger.Level) {
+		dropped.WithLabelValues(level.String()).Inc()
+	}
+	return opts
+}
+
+// newRedactOptions extends the default redaction with the configured keys, paths and patterns
+func newRedactOptions(cfg *config.Config) logger.RedactOptions {
+	redact := cfg.Log.Redact
+	patterns := make([]*regexp.Regexp, 0, len(redact.Patterns))
+	for _, pattern := range redact.Patterns {
+		patterns = append(patterns, regexp.MustCompile(pattern))
+	}
+
+	return logger.DefaultRedactOptions.Merge(logger.RedactOptions{
+		Keys:     redact.Keys,
+		Paths:    redact.Paths,
+		Patterns: patterns,
+	})
+}
+
+// newHealthRegistry registers the database check and the optional checks enabled in config
+func newHealthRegistry(cfg *config.Config, db *sqlx.DB) *health.Registry {
+	registry := health.NewRegistry(cfg.Health.Timeout.Std(), cfg.Health.CacheTTL.Std())
+	registry.Register("database", health.PingChecker(db))
+
+	if cfg.Health.Redis {
+		addr := net.JoinHostPort(cfg.Redis.Host, strconv.Itoa(cfg.Redis.Port))
+		registry.Register("redis", health.TCPChecker(addr))
+	}
+	if cfg.Health.DiskPath != "" {
+		registry.Register("disk", health.DiskChecker(cfg.Health.DiskPath, cfg.Health.DiskMinFreeBytes))
+	}
+
+	return registry
 }
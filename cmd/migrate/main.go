@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/raflynagachi/go-rest-api-starter/config"
+	"github.com/raflynagachi/go-rest-api-starter/migrations"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/database"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/database/migrate"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+)
+
+const usage = `Usage: migrate <command> [arg] [config flags]
+
+Commands:
+  up            apply every pending migration
+  down [n]      revert the last n migrations (default 1)
+  goto <v>      migrate up or down to version v, 0 reverts every migration
+  force <v>     set version v and clear the dirty flag without running migrations
+  status        list migrations and whether they are applied
+
+Config flags follow the command and override the config like for the server,
+e.g. migrate up -databases.go-rest-api-starter.host=localhost
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(flag.CommandLine.Output(), usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	appLogger := logger.NewLogger(logger.WithEnv(config.Env))
+
+	cmdArgs, configArgs := splitArgs(flag.Args())
+	cfg, err := config.LoadConfig(configArgs)
+	if err != nil {
+		config.PrintError(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	db, err := database.ConnectDB(cfg.Databases[config.ServiceName])
+	if err != nil {
+		appLogger.Error("failed to connect database", logger.ErrAttr(err))
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	m, err := migrate.New(db, migrations.FS, appLogger)
+	if err != nil {
+		appLogger.Error("failed to load migrations", logger.ErrAttr(err))
+		os.Exit(1)
+	}
+
+	if err := run(context.Background(), m, cmdArgs); err != nil {
+		appLogger.Error("migration failed", logger.ErrAttr(err))
+		os.Exit(1)
+	}
+}
+
+// splitArgs separates the command and its optional positional argument from the config flags following them
+func splitArgs(args []string) (cmdArgs, configArgs []string) {
+	n := 1
+	if len(args) > 1 && !strings.HasPrefix(args[1], "-") {
+		n = 2
+	}
+	return args[:n], args[n:]
+}
+
+func run(ctx context.Context, m *migrate.Migrator, args []string) error {
+	switch args[0] {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+			steps = n
+		}
+		return m.Down(ctx, steps)
+	case "goto", "force":
+		if len(args) < 2 {
+			return fmt.Errorf("%s requires a version", args[0])
+		}
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if args[0] == "goto" {
+			return m.Goto(ctx, version)
+		}
+		return m.Force(ctx, version)
+	case "status":
+		return printStatus(ctx, m)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func printStatus(ctx context.Context, m *migrate.Migrator) error {
+	version, dirty, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("version: %d (dirty: %t)\n", version, dirty)
+	for _, s := range statuses {
+		state := "pending"
+		if s.Applied {
+			state = "applied"
+		}
+		fmt.Printf("%-8s %d_%s\n", state, s.Version, s.Name)
+	}
+	return nil
+}
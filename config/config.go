@@ -3,16 +3,24 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
+	"reflect"
 
 	"github.com/pkg/errors"
 )
 
 type Config struct {
-	App       App                  `json:"app"`
-	Databases map[string]*Database `json:"databases"`
-	JwtKey    string               `json:"jwt_key"`
-	Redis     Redis                `json:"redis"`
+	App       App                  `json:"app" env:"APP"`
+	Databases map[string]*Database `json:"databases" env:"DATABASES" validate:"dive,required"`
+	// JwtKey verifies bearer tokens, a PEM encoded RSA public key selects RS256, any other value is a HS256 secret
+	JwtKey string `json:"jwt_key" env:"JWT_KEY"`
+	// CursorKey signs the list cursors, a random key is used when empty so cursors do not survive restarts
+	CursorKey string  `json:"cursor_key" env:"CURSOR_KEY"`
+	Redis     Redis   `json:"redis" env:"REDIS"`
+	Health    Health  `json:"health" env:"HEALTH"`
+	Tracing   Tracing `json:"tracing" env:"TRACING"`
+	Log       Log     `json:"log" env:"LOG"`
 }
 
 var (
@@ -28,30 +36,71 @@ func init() {
 	}
 }
 
-// LoadConfig load configuration based on env
-func LoadConfig() (*Config, error) {
+// LoadConfig load configuration based on env, each layer overrides the previous one:
+//  1. `default` struct tags
+//  2. env/{service}.{env}.json, optional, an explicit zero in it is kept
+//  3. environment variables named after the `env` tags, e.g. APP_DATABASES_GO_REST_API_STARTER_PASSWORD
+//  4. command-line flags named after the json path, e.g. -databases.go-rest-api-starter.password
+func LoadConfig(args []string) (*Config, error) {
 	path := fmt.Sprintf("env/%s.%s.json", ServiceName, Env)
 
-	config, err := readJsonConfig(path)
-	if err != nil {
+	config := &Config{}
+	if err := applyDefaults(config); err != nil {
+		return nil, errors.Wrap(err, "LoadConfig.applyDefaults")
+	}
+
+	err := readJsonConfig(path, config)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return nil, errors.Wrap(err, "LoadConfig.readJsonConfig")
 	}
 
+	if err := applyEnv(config); err != nil {
+		return nil, errors.Wrap(err, "LoadConfig.applyEnv")
+	}
+	if err := applyFlags(config, args); err != nil {
+		return nil, errors.Wrap(err, "LoadConfig.applyFlags")
+	}
+	if err := Validate(config); err != nil {
+		return nil, errors.Wrap(err, "LoadConfig.Validate")
+	}
+
 	return config, nil
 }
 
-// ReadJsonConfig mapping the json file to Config struct
-func ReadJsonConfig(path string) (*Config, error) {
+// ReadJsonConfig mapping the json file onto config, the fields missing from the file are left as they are
+func ReadJsonConfig(path string, config *Config) error {
 	file, err := os.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	return json.Unmarshal(file, config)
+}
 
-	config := &Config{}
-	err = json.Unmarshal(file, config)
-	if err != nil {
-		return nil, err
+// UnmarshalJSON decodes a database entry over its `default` struct tags,
+// as encoding/json replaces map entries instead of decoding into them
+func (d *Database) UnmarshalJSON(data []byte) error {
+	type database Database // drops the method, keeps the tags
+	value := database{}
+	if err := setDefaults(reflect.ValueOf(&value).Elem()); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
 	}
+	*d = Database(value)
+	return nil
+}
 
-	return config, nil
+// UnmarshalJSON decodes a sink entry over its `default` struct tags, see Database.UnmarshalJSON
+func (s *LogSink) UnmarshalJSON(data []byte) error {
+	type logSink LogSink
+	value := logSink{}
+	if err := setDefaults(reflect.ValueOf(&value).Elem()); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = LogSink(value)
+	return nil
 }
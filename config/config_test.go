@@ -1,10 +1,12 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/raflynagachi/go-rest-api-starter/pkg/fs"
 )
@@ -101,27 +103,57 @@ func TestReadJsonConfig(t *testing.T) {
 			filePath := tt.setupFile()
 			defer os.Remove(filePath)
 
-			got, err := ReadJsonConfig(filePath)
+			got := &Config{}
+			err := ReadJsonConfig(filePath, got)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ReadJsonConfig() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("ReadJsonConfig() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+// jsonConfig stubs readJsonConfig with a file holding data
+func jsonConfig(data string) func(path string, config *Config) error {
+	return func(path string, config *Config) error {
+		return json.Unmarshal([]byte(data), config)
+	}
+}
+
 func TestLoadConfig(t *testing.T) {
 	tmpReadJsonConfig := readJsonConfig
 	defer func() {
 		readJsonConfig = tmpReadJsonConfig
 	}()
 
+	defaultDatabases := map[string]*Database{
+		ServiceName: {
+			Name:            "db",
+			User:            "user",
+			Host:            "localhost",
+			Port:            5432,
+			MaxOpenConns:    5,
+			MaxIdleConns:    5,
+			SSLMode:         "disable",
+			ApplicationName: ServiceName,
+		},
+	}
+	defaultRedis := Redis{Host: "localhost", Port: 6379}
+	defaultHealth := Health{Timeout: Duration(2 * time.Second), CacheTTL: Duration(time.Second), ShutdownDelay: Duration(5 * time.Second)}
+	defaultTracing := Tracing{Exporter: TracingExporterNone, BatchSize: 512, FlushInterval: Duration(5 * time.Second)}
+	defaultLog := Log{
+		Level:     "info",
+		AccessLog: AccessLog{MaxBodyBytes: 2048, SkipPaths: []string{"/healthz", "/readyz", "/metrics"}},
+		Sampling:  LogSampling{Interval: Duration(time.Second), First: 100, Thereafter: 100},
+	}
+
 	tests := []struct {
 		name    string
 		env     string
+		args    []string
 		setup   func()
 		want    *Config
 		wantErr bool
@@ -130,35 +162,82 @@ func TestLoadConfig(t *testing.T) {
 			name: "success with production env",
 			env:  Production,
 			setup: func() {
-				readJsonConfig = func(path string) (*Config, error) {
-					return &Config{App: App{Name: "my-service"}}, nil
-				}
+				readJsonConfig = jsonConfig(`{"app": {"name": "my-service"}, "databases": {"go-rest-api-starter": {"name": "db", "user": "user"}}}`)
 			},
-			want:    &Config{App: App{Name: "my-service"}},
+			want:    &Config{App: App{Name: "my-service", Port: 8080, MaxBodyBytes: 1 << 20}, Databases: defaultDatabases, Redis: defaultRedis, Health: defaultHealth, Tracing: defaultTracing, Log: defaultLog},
 			wantErr: false,
 		},
 		{
 			name: "success with no env",
 			env:  "",
 			setup: func() {
-				readJsonConfig = func(path string) (*Config, error) {
-					return &Config{App: App{Name: "my-service"}}, nil
+				readJsonConfig = jsonConfig(`{"app": {"name": "my-service"}, "databases": {"go-rest-api-starter": {"name": "db", "user": "user"}}}`)
+			},
+			want:    &Config{App: App{Name: "my-service", Port: 8080, MaxBodyBytes: 1 << 20}, Databases: defaultDatabases, Redis: defaultRedis, Health: defaultHealth, Tracing: defaultTracing, Log: defaultLog},
+			wantErr: false,
+		},
+		{
+			name: "success with missing file uses defaults",
+			env:  Production,
+			setup: func() {
+				readJsonConfig = func(path string, config *Config) error {
+					return os.ErrNotExist
 				}
+				t.Setenv("APP_DATABASES_GO_REST_API_STARTER_NAME", "db")
+				t.Setenv("APP_DATABASES_GO_REST_API_STARTER_USER", "user")
+			},
+			want:    &Config{App: App{Name: ServiceName, Port: 8080, MaxBodyBytes: 1 << 20}, Databases: defaultDatabases, Redis: defaultRedis, Health: defaultHealth, Tracing: defaultTracing, Log: defaultLog},
+			wantErr: false,
+		},
+		{
+			name: "success with flags",
+			env:  Production,
+			args: []string{"-app.port=9090", "-app.auto_migrate", "-jwt_key", "flag-secret"},
+			setup: func() {
+				readJsonConfig = jsonConfig(`{"app": {"name": "my-service"}, "databases": {"go-rest-api-starter": {"name": "db", "user": "user"}}}`)
+			},
+			want: &Config{
+				App:       App{Name: "my-service", Port: 9090, AutoMigrate: true, MaxBodyBytes: 1 << 20},
+				Databases: defaultDatabases,
+				JwtKey:    "flag-secret",
+				Redis:     defaultRedis,
+				Health:    defaultHealth,
+				Tracing:   defaultTracing,
+				Log:       defaultLog,
 			},
-			want:    &Config{App: App{Name: "my-service"}},
 			wantErr: false,
 		},
 		{
 			name: "failed due to read JSON config",
 			env:  Production,
 			setup: func() {
-				readJsonConfig = func(path string) (*Config, error) {
-					return nil, errors.New("some error")
+				readJsonConfig = func(path string, config *Config) error {
+					return errors.New("some error")
 				}
 			},
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "failed due to invalid config",
+			env:  Production,
+			args: []string{"-app.port=0"},
+			setup: func() {
+				readJsonConfig = jsonConfig(`{}`)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "failed due to unknown flag",
+			env:  Production,
+			args: []string{"-unknown=1"},
+			setup: func() {
+				readJsonConfig = jsonConfig(`{}`)
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -166,7 +245,7 @@ func TestLoadConfig(t *testing.T) {
 
 			tt.setup()
 
-			got, err := LoadConfig()
+			got, err := LoadConfig(tt.args)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -7,4 +7,16 @@ const (
 	Development = "development"
 	Staging     = "staging"
 	Production  = "production"
+	Test        = "test"
+)
+
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterOTLP   = "otlp"
+)
+
+const (
+	LogOutputStdout = "stdout"
+	LogOutputStderr = "stderr"
 )
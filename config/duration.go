@@ -0,0 +1,26 @@
+package config
+
+import (
+	"time"
+)
+
+// Duration is a time.Duration written as "30s" or "5m" in JSON, environment variables and flags
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Std returns d as a time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
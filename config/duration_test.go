@@ -0,0 +1,27 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuration_JSON(t *testing.T) {
+	var got struct {
+		Timeout Duration `json:"timeout"`
+	}
+
+	err := json.Unmarshal([]byte(`{"timeout":"1m30s"}`), &got)
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, got.Timeout.Std())
+
+	b, err := json.Marshal(got)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"timeout":"1m30s"}`, string(b))
+
+	err = json.Unmarshal([]byte(`{"timeout":"90"}`), &got)
+	assert.Error(t, err)
+}
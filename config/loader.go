@@ -0,0 +1,237 @@
+package config
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EnvPrefix prefixes every environment variable override, e.g. APP_JWT_KEY
+const EnvPrefix = "APP"
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// field is a configurable leaf of Config
+type field struct {
+	value        reflect.Value
+	envName      string // e.g. APP_DATABASES_GO_REST_API_STARTER_PASSWORD
+	flagName     string // e.g. databases.go-rest-api-starter.password
+	defaultValue string
+	hasDefault   bool
+}
+
+// applyDefaults sets the `default` tags of cfg and of its service database, before the JSON file is read
+func applyDefaults(cfg *Config) error {
+	if cfg.Databases == nil {
+		cfg.Databases = make(map[string]*Database)
+	}
+	if cfg.Databases[ServiceName] == nil {
+		cfg.Databases[ServiceName] = &Database{}
+	}
+	return setDefaults(reflect.ValueOf(cfg).Elem())
+}
+
+// setDefaults sets the `default` tag on every empty field of the struct v
+func setDefaults(v reflect.Value) error {
+	return walkFields(v, nil, nil, func(f field) error {
+		if !f.hasDefault || !f.value.IsZero() {
+			return nil
+		}
+		if err := setValue(f.value, f.defaultValue); err != nil {
+			return errors.Wrapf(err, "default %s", f.flagName)
+		}
+		return nil
+	})
+}
+
+// applyEnv overrides fields with the environment variables named after their `env` tags
+func applyEnv(cfg *Config) error {
+	return walkFields(reflect.ValueOf(cfg).Elem(), []string{EnvPrefix}, nil, func(f field) error {
+		val, ok := os.LookupEnv(f.envName)
+		if !ok {
+			return nil
+		}
+		if err := setValue(f.value, val); err != nil {
+			return errors.Wrapf(err, "env %s", f.envName)
+		}
+		return nil
+	})
+}
+
+// applyFlags overrides fields with command-line flags named after their json path, e.g. -app.port=8080
+func applyFlags(cfg *Config, args []string) error {
+	flagSet := flag.NewFlagSet(ServiceName, flag.ContinueOnError)
+
+	err := walkFields(reflect.ValueOf(cfg).Elem(), []string{EnvPrefix}, nil, func(f field) error {
+		value := f.value
+		usage := fmt.Sprintf("overrides env %s", f.envName)
+		if value.Kind() == reflect.Bool {
+			flagSet.BoolFunc(f.flagName, usage, func(s string) error { return setValue(value, s) })
+			return nil
+		}
+		flagSet.Func(f.flagName, usage, func(s string) error { return setValue(value, s) })
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flagSet.Parse(args)
+}
+
+// walkFields calls fn for every scalar field of v, following nested structs and maps of struct pointers
+func walkFields(v reflect.Value, envPath, flagPath []string, fn func(f field) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+		if jsonName == "" {
+			jsonName = sf.Name
+		}
+		envName := sf.Tag.Get("env")
+		if envName == "" {
+			envName = strings.ToUpper(jsonName)
+		}
+
+		fieldEnvPath := append(envPath[:len(envPath):len(envPath)], envName)
+		fieldFlagPath := append(flagPath[:len(flagPath):len(flagPath)], jsonName)
+		fv := v.Field(i)
+
+		switch {
+		case fv.Kind() == reflect.Struct:
+			if err := walkFields(fv, fieldEnvPath, fieldFlagPath, fn); err != nil {
+				return err
+			}
+		case fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.Struct:
+			if fv.IsNil() {
+				continue
+			}
+			if err := walkFields(fv.Elem(), fieldEnvPath, fieldFlagPath, fn); err != nil {
+				return err
+			}
+		case fv.Kind() == reflect.Map:
+			if err := walkMap(fv, fieldEnvPath, fieldFlagPath, fn); err != nil {
+				return err
+			}
+		default:
+			defaultValue, hasDefault := sf.Tag.Lookup("default")
+			err := fn(field{
+				value:        fv,
+				envName:      strings.Join(fieldEnvPath, "_"),
+				flagName:     strings.Join(fieldFlagPath, "."),
+				defaultValue: defaultValue,
+				hasDefault:   hasDefault,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walkMap follows the existing entries of a map[string]*struct, the key is part of the env and flag names
+func walkMap(v reflect.Value, envPath, flagPath []string, fn func(f field) error) error {
+	if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.Pointer {
+		return nil
+	}
+
+	keys := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		elem := v.MapIndex(reflect.ValueOf(key))
+		if elem.IsNil() || elem.Elem().Kind() != reflect.Struct {
+			continue
+		}
+
+		envKey := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+		err := walkFields(elem.Elem(),
+			append(envPath[:len(envPath):len(envPath)], envKey),
+			append(flagPath[:len(flagPath):len(flagPath)], key),
+			fn,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setValue parses s into v according to its kind
+func setValue(v reflect.Value, s string) error {
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(s))
+		}
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %s", v.Type())
+		}
+		parts := []string{}
+		if s != "" {
+			parts = strings.Split(s, ",")
+		}
+		v.Set(reflect.ValueOf(parts).Convert(v.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
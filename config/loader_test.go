@@ -0,0 +1,191 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_Precedence(t *testing.T) {
+	tmpReadJsonConfig := readJsonConfig
+	defer func() {
+		readJsonConfig = tmpReadJsonConfig
+	}()
+	readJsonConfig = jsonConfig(`{
+		"app": {"port": 1000},
+		"databases": {
+			"go-rest-api-starter": {"name": "json-db", "user": "json-user", "password": "json-password"},
+			"replica": {"name": "json-db", "user": "replica-user", "host": "replica.local"}
+		},
+		"jwt_key": "json-secret"
+	}`)
+
+	t.Setenv("APP_APP_PORT", "2000")
+	t.Setenv("APP_DATABASES_GO_REST_API_STARTER_PASSWORD", "env-password")
+	t.Setenv("APP_DATABASES_GO_REST_API_STARTER_USER", "env-user")
+	t.Setenv("APP_DATABASES_REPLICA_PORT", "6543")
+	t.Setenv("APP_DATABASES_REPLICA_STATEMENT_TIMEOUT", "5s")
+	t.Setenv("APP_JWT_KEY", "env-secret")
+
+	cfg, err := LoadConfig([]string{
+		"-app.port=3000",
+		"-databases.go-rest-api-starter.user=flag-user",
+	})
+	require.NoError(t, err)
+
+	// flags > env > JSON > defaults
+	assert.Equal(t, 3000, cfg.App.Port)
+	assert.Equal(t, ServiceName, cfg.App.Name)
+	assert.Equal(t, "env-secret", cfg.JwtKey)
+	assert.Equal(t, &Database{
+		Name:            "json-db",
+		User:            "flag-user",
+		Password:        "env-password",
+		Host:            "localhost",
+		Port:            5432,
+		MaxOpenConns:    5,
+		MaxIdleConns:    5,
+		SSLMode:         "disable",
+		ApplicationName: ServiceName,
+	}, cfg.Databases[ServiceName])
+	assert.Equal(t, "replica.local", cfg.Databases["replica"].Host)
+	assert.Equal(t, 6543, cfg.Databases["replica"].Port)
+	assert.Equal(t, 5*time.Second, cfg.Databases["replica"].StatementTimeout.Std())
+}
+
+func TestLoadConfig_InvalidEnv(t *testing.T) {
+	tmpReadJsonConfig := readJsonConfig
+	defer func() {
+		readJsonConfig = tmpReadJsonConfig
+	}()
+	readJsonConfig = jsonConfig(`{}`)
+
+	t.Setenv("APP_APP_PORT", "not-a-number")
+
+	_, err := LoadConfig(nil)
+	assert.ErrorContains(t, err, "APP_APP_PORT")
+}
+
+func TestLoadConfig_MissingDatabase(t *testing.T) {
+	tmpReadJsonConfig := readJsonConfig
+	defer func() {
+		readJsonConfig = tmpReadJsonConfig
+	}()
+	readJsonConfig = jsonConfig(`{}`)
+
+	_, err := LoadConfig(nil)
+
+	var valErr *ValidationError
+	if assert.ErrorAs(t, err, &valErr) {
+		assert.Equal(t, []string{"databases.go-rest-api-starter is required"}, valErr.Problems)
+	}
+}
+
+func TestLoadConfig_ExplicitZero(t *testing.T) {
+	tmpReadJsonConfig := readJsonConfig
+	defer func() {
+		readJsonConfig = tmpReadJsonConfig
+	}()
+	readJsonConfig = jsonConfig(`{
+		"databases": {"go-rest-api-starter": {"name": "db", "user": "user", "max_idle_conns": 0}},
+		"health": {"cache_ttl": "0s", "shutdown_delay": "0s"},
+		"log": {"sinks": {"file": {"output": "app.log", "rotation": {"max_backups": 0}}}}
+	}`)
+
+	cfg, err := LoadConfig(nil)
+	require.NoError(t, err)
+
+	// zero set in the file is kept, the fields missing from it get their default
+	db := cfg.Databases[ServiceName]
+	assert.Equal(t, 0, db.MaxIdleConns)
+	assert.Equal(t, 5, db.MaxOpenConns)
+	assert.Equal(t, "localhost", db.Host)
+	assert.Zero(t, cfg.Health.CacheTTL)
+	assert.Zero(t, cfg.Health.ShutdownDelay)
+	assert.Equal(t, Duration(2*time.Second), cfg.Health.Timeout)
+	sink := cfg.Log.Sinks["file"]
+	assert.Equal(t, 0, sink.Rotation.MaxBackups)
+	assert.Equal(t, 100, sink.Rotation.MaxSizeMB)
+	assert.Equal(t, "text", sink.Format)
+}
+
+func TestWalkFields(t *testing.T) {
+	cfg := &Config{Databases: map[string]*Database{"b-db": {}, "a.db": {}, "nil": nil}}
+
+	envNames := []string{}
+	flagNames := []string{}
+	err := walkFields(reflectValue(cfg), []string{EnvPrefix}, nil, func(f field) error {
+		envNames = append(envNames, f.envName)
+		flagNames = append(flagNames, f.flagName)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, envNames, "APP_APP_PORT")
+	assert.Contains(t, envNames, "APP_DATABASES_A_DB_HOST")
+	assert.Contains(t, envNames, "APP_DATABASES_B_DB_PASSWORD")
+	assert.Contains(t, envNames, "APP_JWT_KEY")
+	assert.Contains(t, envNames, "APP_REDIS_HOST")
+	assert.Contains(t, flagNames, "databases.b-db.password")
+	assert.Contains(t, flagNames, "app.auto_migrate")
+	assert.NotContains(t, envNames, "APP_DATABASES_NIL_HOST")
+}
+
+func TestSetValue(t *testing.T) {
+	type values struct {
+		String   string
+		Bool     bool
+		Int      int
+		Uint     uint16
+		Float    float64
+		Duration time.Duration
+		Text     Duration
+		Strings  []string
+		Map      map[string]string
+	}
+
+	tests := []struct {
+		name    string
+		field   string
+		input   string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "string", field: "String", input: "value", want: "value"},
+		{name: "bool", field: "Bool", input: "true", want: true},
+		{name: "int", field: "Int", input: "-12", want: -12},
+		{name: "uint", field: "Uint", input: "12", want: uint16(12)},
+		{name: "float", field: "Float", input: "1.5", want: 1.5},
+		{name: "duration", field: "Duration", input: "1m30s", want: 90 * time.Second},
+		{name: "text unmarshaler", field: "Text", input: "2s", want: Duration(2 * time.Second)},
+		{name: "invalid text unmarshaler", field: "Text", input: "2", wantErr: true},
+		{name: "strings", field: "Strings", input: "a,b", want: []string{"a", "b"}},
+		{name: "invalid bool", field: "Bool", input: "yes please", wantErr: true},
+		{name: "invalid int", field: "Int", input: "1.5", wantErr: true},
+		{name: "uint overflow", field: "Uint", input: "70000", wantErr: true},
+		{name: "invalid duration", field: "Duration", input: "1", wantErr: true},
+		{name: "unsupported type", field: "Map", input: "a=b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &values{}
+			fv := reflectValue(v).FieldByName(tt.field)
+
+			err := setValue(fv, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, fv.Interface())
+			}
+		})
+	}
+}
+
+func reflectValue(ptr interface{}) reflect.Value {
+	return reflect.ValueOf(ptr).Elem()
+}
@@ -1,19 +1,128 @@
 package config
 
 type App struct {
-	Name string `json:"name"`
-	Port int    `json:"port"`
+	Name string `json:"name" env:"NAME" default:"go-rest-api-starter" validate:"required"`
+	Port int    `json:"port" env:"PORT" default:"8080" validate:"min=1,max=65535"`
+	// ProblemDetails answers every error as RFC 7807 application/problem+json, otherwise only clients asking for it with Accept get it
+	ProblemDetails bool `json:"problem_details" env:"PROBLEM_DETAILS"`
+	AutoMigrate    bool `json:"auto_migrate" env:"AUTO_MIGRATE"`
+	// MaxBodyBytes limits JSON request bodies, larger ones are answered with 413, zero keeps the encoder default
+	MaxBodyBytes int64 `json:"max_body_bytes" env:"MAX_BODY_BYTES" default:"1048576" validate:"min=0"`
 }
 type Database struct {
-	Name     string `json:"name"`
-	User     string `json:"user"`
-	Password string `json:"password"`
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
+	Name     string `json:"name" env:"NAME" validate:"required"`
+	User     string `json:"user" env:"USER" validate:"required"`
+	Password string `json:"password" env:"PASSWORD"`
+	Host     string `json:"host" env:"HOST" default:"localhost" validate:"required"`
+	Port     int    `json:"port" env:"PORT" default:"5432" validate:"min=1,max=65535"`
+
+	// connection pool, zero lifetime and idle time keep connections forever
+	MaxOpenConns    int      `json:"max_open_conns" env:"MAX_OPEN_CONNS" default:"5" validate:"min=0"`
+	MaxIdleConns    int      `json:"max_idle_conns" env:"MAX_IDLE_CONNS" default:"5" validate:"min=0"`
+	ConnMaxLifetime Duration `json:"conn_max_lifetime" env:"CONN_MAX_LIFETIME" validate:"min=0"`
+	ConnMaxIdleTime Duration `json:"conn_max_idle_time" env:"CONN_MAX_IDLE_TIME" validate:"min=0"`
+
+	// TLS, the certificate paths are read by the driver
+	SSLMode     string `json:"sslmode" env:"SSLMODE" default:"disable" validate:"omitempty,oneof=disable allow prefer require verify-ca verify-full"`
+	SSLRootCert string `json:"sslrootcert" env:"SSLROOTCERT"`
+	SSLCert     string `json:"sslcert" env:"SSLCERT" validate:"required_with=SSLKey"`
+	SSLKey      string `json:"sslkey" env:"SSLKEY" validate:"required_with=SSLCert"`
+
+	// session, zero timeouts mean no timeout
+	ConnectTimeout   Duration `json:"connect_timeout" env:"CONNECT_TIMEOUT" validate:"min=0"`
+	ApplicationName  string   `json:"application_name" env:"APPLICATION_NAME" default:"go-rest-api-starter"`
+	StatementTimeout Duration `json:"statement_timeout" env:"STATEMENT_TIMEOUT" validate:"min=0"`
+	SearchPath       string   `json:"search_path" env:"SEARCH_PATH"`
 }
 
 type Redis struct {
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	Password string `json:"password"`
+	Host     string `json:"host" env:"HOST" default:"localhost"`
+	Port     int    `json:"port" env:"PORT" default:"6379" validate:"min=1,max=65535"`
+	Password string `json:"password" env:"PASSWORD"`
+}
+
+type Health struct {
+	Timeout  Duration `json:"timeout" env:"TIMEOUT" default:"2s" validate:"min=0"`
+	CacheTTL Duration `json:"cache_ttl" env:"CACHE_TTL" default:"1s" validate:"min=0"`
+	// ShutdownDelay keeps serving with a failing readiness before the server stops, so load balancers can notice it
+	ShutdownDelay Duration `json:"shutdown_delay" env:"SHUTDOWN_DELAY" default:"5s" validate:"min=0"`
+
+	// optional checks
+	Redis            bool   `json:"redis" env:"REDIS"`
+	DiskPath         string `json:"disk_path" env:"DISK_PATH"`
+	DiskMinFreeBytes uint64 `json:"disk_min_free_bytes" env:"DISK_MIN_FREE_BYTES"`
+}
+
+type Tracing struct {
+	// none, stdout or otlp
+	Exporter      string   `json:"exporter" env:"EXPORTER" default:"none" validate:"omitempty,oneof=none stdout otlp"`
+	Endpoint      string   `json:"endpoint" env:"ENDPOINT" validate:"required_if=Exporter otlp,omitempty,url"`
+	BatchSize     int      `json:"batch_size" env:"BATCH_SIZE" default:"512" validate:"min=0"`
+	FlushInterval Duration `json:"flush_interval" env:"FLUSH_INTERVAL" default:"5s" validate:"min=0"`
+}
+
+type Log struct {
+	// level is the initial level, it can be changed at runtime through PUT /admin/log-level or SIGUSR1 and SIGUSR2
+	Level string `json:"level" env:"LEVEL" default:"info" validate:"omitempty,oneof=debug info warn error"`
+	// sinks replace the output of the environment, e.g. pretty to stdout and json to a rotated file
+	Sinks     map[string]*LogSink `json:"sinks" env:"SINKS" validate:"dive,required"`
+	AccessLog AccessLog           `json:"access_log" env:"ACCESS_LOG"`
+	Sampling  LogSampling         `json:"sampling" env:"SAMPLING"`
+	Redact    Redact              `json:"redact" env:"REDACT"`
+}
+
+type LogSink struct {
+	// output is stdout, stderr or a file path
+	Output string `json:"output" env:"OUTPUT" validate:"required"`
+	Format string `json:"format" env:"FORMAT" default:"text" validate:"oneof=pretty text json"`
+	// level writes the records at or above it whatever the runtime level is, empty follows the runtime level
+	Level    string   `json:"level" env:"LEVEL" validate:"omitempty,oneof=debug info warn error"`
+	Rotation Rotation `json:"rotation" env:"ROTATION"`
+}
+
+// Rotation applies to file outputs, zero values disable the limit
+type Rotation struct {
+	MaxSizeMB  int      `json:"max_size_mb" env:"MAX_SIZE_MB" default:"100" validate:"min=0"`
+	MaxAge     Duration `json:"max_age" env:"MAX_AGE" validate:"min=0"`
+	MaxBackups int      `json:"max_backups" env:"MAX_BACKUPS" default:"7" validate:"min=0"`
+	Compress   bool     `json:"compress" env:"COMPRESS"`
+}
+
+// LogSampling drops repeated records with the same level and message per interval, errors are always written.
+// Each interval with drops ends with a warning and log_records_dropped_total counts the drops by level.
+type LogSampling struct {
+	Enabled    bool     `json:"enabled" env:"ENABLED"`
+	Interval   Duration `json:"interval" env:"INTERVAL" default:"1s" validate:"min=0"`
+	First      int      `json:"first" env:"FIRST" default:"100" validate:"min=0"`
+	Thereafter int      `json:"thereafter" env:"THEREAFTER" default:"100" validate:"min=0"`
+
+	// levels replace first and thereafter for debug, info or warn records
+	Levels map[string]*SampleRule `json:"levels" env:"LEVELS" validate:"dive,keys,oneof=debug info warn,endkeys,required"`
+	// exempt opts loggers out of sampling by name, e.g. "access" for the access log, none by default
+	Exempt []string `json:"exempt" env:"EXEMPT"`
+}
+
+type SampleRule struct {
+	First      int `json:"first" env:"FIRST" validate:"min=0"`
+	Thereafter int `json:"thereafter" env:"THEREAFTER" validate:"min=0"`
+}
+
+// Redact extends the built-in redaction of passwords, tokens and emails
+type Redact struct {
+	Keys []string `json:"keys" env:"KEYS"`
+	// paths are dotted through groups and JSON bodies, e.g. request_body.user.phone
+	Paths    []string `json:"paths" env:"PATHS"`
+	Patterns []string `json:"patterns" env:"PATTERNS"`
+}
+
+type AccessLog struct {
+	// bodies are logged up to max_body_bytes each
+	RequestBody  bool `json:"request_body" env:"REQUEST_BODY"`
+	ResponseBody bool `json:"response_body" env:"RESPONSE_BODY"`
+	MaxBodyBytes int  `json:"max_body_bytes" env:"MAX_BODY_BYTES" default:"2048" validate:"min=0"`
+
+	// trust_proxy reads the client IP from X-Forwarded-For, only enable it behind a proxy
+	TrustProxy bool `json:"trust_proxy" env:"TRUST_PROXY"`
+	// skip_paths are logged only when they fail, the probes and /metrics by default
+	SkipPaths []string `json:"skip_paths" env:"SKIP_PATHS" default:"/healthz,/readyz,/metrics"`
 }
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	appValidator "github.com/raflynagachi/go-rest-api-starter/pkg/validator"
+)
+
+var (
+	knownEnvs = []string{Development, Staging, Production, Test}
+)
+
+// ValidationError lists every configuration problem found by Validate
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid config: %s", strings.Join(e.Problems, "; "))
+}
+
+// Report formats the problems one per line for the command line
+func (e *ValidationError) Report() string {
+	var b strings.Builder
+	b.WriteString("invalid config:\n")
+	for _, problem := range e.Problems {
+		fmt.Fprintf(&b, "  - %s\n", problem)
+	}
+	return b.String()
+}
+
+// PrintError writes the error returned by LoadConfig to w, listing every validation problem at once
+func PrintError(w io.Writer, err error) {
+	var valErr *ValidationError
+	if errors.As(err, &valErr) {
+		fmt.Fprint(w, valErr.Report())
+		return
+	}
+	fmt.Fprintf(w, "failed to load config: %v\n", err)
+}
+
+// Validate checks the `validate` struct tags, the environment name and the service database
+func Validate(cfg *Config) error {
+	problems := []string{}
+
+	if !isKnownEnv(Env) {
+		problems = append(problems, fmt.Sprintf("env %q is unknown, set %s to one of %s", Env, EnvKey, strings.Join(knownEnvs, ", ")))
+	}
+
+	// applyDefaults always creates the service entry, so an entry without name and user is unconfigured.
+	// It is reported once, without the errors of its fields.
+	serviceDB := fmt.Sprintf("databases[%s]", ServiceName)
+	missingDB := false
+	if db := cfg.Databases[ServiceName]; db == nil || (db.Name == "" && db.User == "") {
+		problems = append(problems, fmt.Sprintf("databases.%s is required", ServiceName))
+		missingDB = true
+	}
+
+	if err := appValidator.Validate(cfg); err != nil {
+		valErrs, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return err
+		}
+		for _, fieldErr := range valErrs {
+			path := fieldPath(fieldErr)
+			if missingDB && (path == serviceDB || strings.HasPrefix(path, serviceDB+".")) {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("%s: %s", path, fieldErr.Translate(appValidator.GetTranslator())))
+		}
+	}
+
+	for i, pattern := range cfg.Log.Redact.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("log.redact.patterns[%d]: %s", i, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// fieldPath returns the field namespace without the root struct name, e.g. "databases[go-rest-api-starter].port"
+func fieldPath(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+func isKnownEnv(env string) bool {
+	for _, knownEnv := range knownEnvs {
+		if env == knownEnv {
+			return true
+		}
+	}
+	return false
+}
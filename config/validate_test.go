@@ -0,0 +1,216 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	validConfig := func() *Config {
+		return &Config{
+			App: App{Name: ServiceName, Port: 8080},
+			Databases: map[string]*Database{
+				ServiceName: {Name: "db", User: "user", Host: "localhost", Port: 5432},
+			},
+			Redis: Redis{Host: "localhost", Port: 6379},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		env          string
+		config       func() *Config
+		wantProblems []string
+	}{
+		{
+			name:   "success with valid config",
+			env:    Development,
+			config: validConfig,
+		},
+		{
+			name: "failed due to every problem at once",
+			env:  "prod",
+			config: func() *Config {
+				cfg := validConfig()
+				cfg.App.Port = 0
+				cfg.Databases[ServiceName].User = ""
+				cfg.Databases[ServiceName].Port = 70000
+				cfg.Redis.Port = -1
+				return cfg
+			},
+			wantProblems: []string{
+				`env "prod" is unknown, set env to one of development, staging, production, test`,
+				"app.port: port must be 1 or greater",
+				"databases[go-rest-api-starter].user: user is a required field",
+				"databases[go-rest-api-starter].port: port must be 65,535 or less",
+				"redis.port: port must be 1 or greater",
+			},
+		},
+		{
+			name: "failed due to invalid database options",
+			env:  Production,
+			config: func() *Config {
+				cfg := validConfig()
+				cfg.Databases[ServiceName].SSLMode = "on"
+				cfg.Databases[ServiceName].SSLCert = "/etc/ssl/client.pem"
+				cfg.Databases[ServiceName].MaxOpenConns = -1
+				return cfg
+			},
+			wantProblems: []string{
+				"databases[go-rest-api-starter].max_open_conns: max_open_conns must be 0 or greater",
+				"databases[go-rest-api-starter].sslmode: sslmode must be one of [disable allow prefer require verify-ca verify-full]",
+				"databases[go-rest-api-starter].sslkey: sslkey is a required field",
+			},
+		},
+		{
+			name: "failed due to invalid tracing options",
+			env:  Production,
+			config: func() *Config {
+				cfg := validConfig()
+				cfg.Tracing.Exporter = TracingExporterOTLP
+				cfg.Tracing.BatchSize = -1
+				return cfg
+			},
+			wantProblems: []string{
+				"tracing.endpoint: endpoint is a required field",
+				"tracing.batch_size: batch_size must be 0 or greater",
+			},
+		},
+		{
+			name: "failed due to invalid log sink",
+			env:  Production,
+			config: func() *Config {
+				cfg := validConfig()
+				cfg.Log.Sinks = map[string]*LogSink{"file": {Format: "xml", Rotation: Rotation{MaxBackups: -1}}}
+				return cfg
+			},
+			wantProblems: []string{
+				"log.sinks[file].output: output is a required field",
+				"log.sinks[file].format: format must be one of [pretty text json]",
+				"log.sinks[file].rotation.max_backups: max_backups must be 0 or greater",
+			},
+		},
+		{
+			name: "failed due to invalid log sampling levels",
+			env:  Production,
+			config: func() *Config {
+				cfg := validConfig()
+				cfg.Log.Sampling.Levels = map[string]*SampleRule{"error": {First: 1}}
+				return cfg
+			},
+			wantProblems: []string{"log.sampling.levels[error]: levels[error] must be one of [debug info warn]"},
+		},
+		{
+			name: "failed due to invalid log sampling rule",
+			env:  Production,
+			config: func() *Config {
+				cfg := validConfig()
+				cfg.Log.Sampling.Levels = map[string]*SampleRule{"debug": {Thereafter: -1}}
+				return cfg
+			},
+			wantProblems: []string{"log.sampling.levels[debug].thereafter: thereafter must be 0 or greater"},
+		},
+		{
+			name: "failed due to invalid redact pattern",
+			env:  Production,
+			config: func() *Config {
+				cfg := validConfig()
+				cfg.Log.Redact.Patterns = []string{`\d{4}-\d{4}`, `[a-z`}
+				return cfg
+			},
+			wantProblems: []string{"log.redact.patterns[1]: error parsing regexp: missing closing ]: `[a-z`"},
+		},
+		{
+			name: "failed due to missing service database",
+			env:  Production,
+			config: func() *Config {
+				cfg := validConfig()
+				cfg.Databases = map[string]*Database{"other": {Name: "db", User: "user", Host: "localhost", Port: 5432}}
+				return cfg
+			},
+			wantProblems: []string{"databases.go-rest-api-starter is required"},
+		},
+		{
+			name: "failed due to nil database",
+			env:  Production,
+			config: func() *Config {
+				cfg := validConfig()
+				cfg.Databases[ServiceName] = nil
+				return cfg
+			},
+			wantProblems: []string{"databases.go-rest-api-starter is required"},
+		},
+		{
+			name: "failed due to unconfigured database",
+			env:  Production,
+			config: func() *Config {
+				cfg := validConfig()
+				cfg.Databases[ServiceName] = &Database{Host: "localhost", Port: 5432}
+				return cfg
+			},
+			wantProblems: []string{"databases.go-rest-api-starter is required"},
+		},
+		{
+			name: "failed due to missing database user",
+			env:  Production,
+			config: func() *Config {
+				cfg := validConfig()
+				cfg.Databases[ServiceName].User = ""
+				return cfg
+			},
+			wantProblems: []string{"databases[go-rest-api-starter].user: user is a required field"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpEnv := Env
+			defer func() {
+				Env = tmpEnv
+			}()
+			Env = tt.env
+
+			err := Validate(tt.config())
+			if tt.wantProblems == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			valErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			assert.Equal(t, tt.wantProblems, valErr.Problems)
+		})
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "validation error lists every problem",
+			err:  errors.Wrap(&ValidationError{Problems: []string{"app.name is required", "env \"qa\" is unknown"}}, "LoadConfig"),
+			want: "invalid config:\n  - app.name is required\n  - env \"qa\" is unknown\n",
+		},
+		{
+			name: "other error",
+			err:  errors.New("unexpected end of JSON input"),
+			want: "failed to load config: unexpected end of JSON input\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			PrintError(&buf, tt.err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -1,8 +1,24 @@
 package request
 
+import "time"
+
 type Pagination struct {
 	Page  int `json:"page"`
 	Limit int `json:"limit"`
+
+	// Cursor is the next_cursor or prev_cursor of a previous page, it replaces Page
+	Cursor string `json:"cursor"`
+	// SkipCount leaves out the total, saving a count query
+	SkipCount bool `json:"skip_count"`
+}
+
+// Keyset positions a page after a row of the (created_at, id) ordering, or before it when Backward
+type Keyset struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+	Backward  bool      `json:"backward,omitempty"`
+	// Filter is the FilterHash of the listing the cursor belongs to
+	Filter string `json:"filter"`
 }
 
 func (p *Pagination) Validate() {
@@ -1,9 +1,104 @@
 package request
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+const (
+	EmailMatchExact    = "exact"
+	EmailMatchPrefix   = "prefix"
+	EmailMatchContains = "contains"
+)
 
 type UserFilter struct {
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	Email string `json:"email"`
+	// EmailMatch is how Email is matched, contains when empty
+	EmailMatch string  `json:"email_match" validate:"omitempty,oneof=exact prefix contains"`
+	IDs        []int64 `json:"id[in]" validate:"max=100"`
+	CreatedBy  string  `json:"created_by"`
+
+	// CreatedAt is kept for compatibility, it is the same as CreatedAtGte
+	CreatedAt    time.Time `json:"created_at"`
+	CreatedAtGte time.Time `json:"created_at[gte]"`
+	CreatedAtGt  time.Time `json:"created_at[gt]"`
+	CreatedAtLte time.Time `json:"created_at[lte]"`
+	CreatedAtLt  time.Time `json:"created_at[lt]"`
+	UpdatedAtGte time.Time `json:"updated_at[gte]"`
+	UpdatedAtGt  time.Time `json:"updated_at[gt]"`
+	UpdatedAtLte time.Time `json:"updated_at[lte]"`
+	UpdatedAtLt  time.Time `json:"updated_at[lt]"`
+
+	IncludeDeleted bool `json:"include_deleted"`
+
+	// Sort is a comma separated list of UserSortFields, prefixed with - for descending
+	Sort string `json:"sort"`
 	Pagination
+
+	// Sorts is parsed from Sort by the usecase
+	Sorts []SortField `json:"-"`
+	// Keyset is decoded from Pagination.Cursor by the usecase
+	Keyset *Keyset `json:"-"`
+}
+
+type UserDetailFilter struct {
+	IncludeDeleted bool `json:"include_deleted"`
+}
+
+// userFilterKey is the normalized form of the conditions of a UserFilter
+type userFilterKey struct {
+	Email          string    `json:"email"`
+	EmailMatch     string    `json:"email_match"`
+	IDs            []int64   `json:"ids"`
+	CreatedBy      string    `json:"created_by"`
+	CreatedAtGte   time.Time `json:"created_at_gte"`
+	CreatedAtGt    time.Time `json:"created_at_gt"`
+	CreatedAtLte   time.Time `json:"created_at_lte"`
+	CreatedAtLt    time.Time `json:"created_at_lt"`
+	UpdatedAtGte   time.Time `json:"updated_at_gte"`
+	UpdatedAtGt    time.Time `json:"updated_at_gt"`
+	UpdatedAtLte   time.Time `json:"updated_at_lte"`
+	UpdatedAtLt    time.Time `json:"updated_at_lt"`
+	IncludeDeleted bool      `json:"include_deleted"`
+}
+
+// FilterHash identifies the rows selected by the filter, filters selecting the same rows
+// the same way share it whatever their order of IDs, time zones or pagination
+func (f UserFilter) FilterHash() string {
+	key := userFilterKey{
+		Email:          f.Email,
+		CreatedBy:      f.CreatedBy,
+		CreatedAtGte:   f.CreatedAtGte.UTC(),
+		CreatedAtGt:    f.CreatedAtGt.UTC(),
+		CreatedAtLte:   f.CreatedAtLte.UTC(),
+		CreatedAtLt:    f.CreatedAtLt.UTC(),
+		UpdatedAtGte:   f.UpdatedAtGte.UTC(),
+		UpdatedAtGt:    f.UpdatedAtGt.UTC(),
+		UpdatedAtLte:   f.UpdatedAtLte.UTC(),
+		UpdatedAtLt:    f.UpdatedAtLt.UTC(),
+		IncludeDeleted: f.IncludeDeleted,
+	}
+	if f.Email != "" {
+		key.EmailMatch = f.EmailMatch
+		if key.EmailMatch == "" {
+			key.EmailMatch = EmailMatchContains
+		}
+	}
+	if len(f.IDs) > 0 {
+		key.IDs = slices.Clone(f.IDs)
+		slices.Sort(key.IDs)
+		key.IDs = slices.Compact(key.IDs)
+	}
+	// both bounds apply, the later one selects the rows
+	if f.CreatedAt.After(f.CreatedAtGte) {
+		key.CreatedAtGte = f.CreatedAt.UTC()
+	}
+
+	// the key only has strings, int64, times and bools, it always marshals
+	data, _ := json.Marshal(key)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
 }
@@ -0,0 +1,72 @@
+package request
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserFilter_FilterHash(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		name     string
+		a        UserFilter
+		b        UserFilter
+		wantSame bool
+	}{
+		{
+			name:     "same without pagination and sort",
+			a:        UserFilter{Email: "user", Pagination: Pagination{Page: 1, Limit: 10}},
+			b:        UserFilter{Email: "user", Pagination: Pagination{Limit: 20, Cursor: "token"}},
+			wantSame: true,
+		},
+		{
+			name:     "same with IDs in another order",
+			a:        UserFilter{IDs: []int64{3, 1, 2}},
+			b:        UserFilter{IDs: []int64{1, 2, 3, 3}},
+			wantSame: true,
+		},
+		{
+			name:     "same with the default email match",
+			a:        UserFilter{Email: "user"},
+			b:        UserFilter{Email: "user", EmailMatch: EmailMatchContains},
+			wantSame: true,
+		},
+		{
+			name:     "same time in another zone",
+			a:        UserFilter{UpdatedAtLt: createdAt},
+			b:        UserFilter{UpdatedAtLt: createdAt.In(jakarta)},
+			wantSame: true,
+		},
+		{
+			name:     "same with created_at as created_at[gte]",
+			a:        UserFilter{CreatedAt: createdAt},
+			b:        UserFilter{CreatedAtGte: createdAt},
+			wantSame: true,
+		},
+		{
+			name: "different email match",
+			a:    UserFilter{Email: "user"},
+			b:    UserFilter{Email: "user", EmailMatch: EmailMatchExact},
+		},
+		{
+			name: "different include deleted",
+			a:    UserFilter{},
+			b:    UserFilter{IncludeDeleted: true},
+		},
+		{
+			name: "different IDs",
+			a:    UserFilter{IDs: []int64{1}},
+			b:    UserFilter{IDs: []int64{2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantSame, tt.a.FilterHash() == tt.b.FilterHash())
+		})
+	}
+}
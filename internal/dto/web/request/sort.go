@@ -0,0 +1,46 @@
+package request
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidSort = errors.New("invalid sort")
+
+// UserSortFields are the fields users can be sorted by
+var UserSortFields = []string{"id", "email", "created_at", "updated_at"}
+
+// SortField orders a listing by one field
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses a sort like -created_at,email, only allowing the given fields
+func ParseSort(sort string, allowed []string) ([]SortField, error) {
+	if sort == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(sort, ",")
+	fields := make([]SortField, 0, len(parts))
+	seen := make(map[string]bool, len(parts))
+	for _, part := range parts {
+		field := SortField{Field: strings.TrimSpace(part)}
+		if name, ok := strings.CutPrefix(field.Field, "-"); ok {
+			field.Field, field.Desc = name, true
+		}
+
+		if !slices.Contains(allowed, field.Field) {
+			return nil, errors.Wrapf(ErrInvalidSort, "unknown field %q", field.Field)
+		}
+		if seen[field.Field] {
+			return nil, errors.Wrapf(ErrInvalidSort, "duplicate field %q", field.Field)
+		}
+		seen[field.Field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
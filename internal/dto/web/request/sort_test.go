@@ -0,0 +1,56 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    string
+		want    []SortField
+		wantErr error
+	}{
+		{
+			name: "success empty sort",
+			sort: "",
+			want: nil,
+		},
+		{
+			name: "success multiple fields",
+			sort: "-created_at, email",
+			want: []SortField{{Field: "created_at", Desc: true}, {Field: "email"}},
+		},
+		{
+			name:    "failed due to unknown field",
+			sort:    "password",
+			wantErr: ErrInvalidSort,
+		},
+		{
+			name:    "failed due to injected field",
+			sort:    "email;DROP TABLE users",
+			wantErr: ErrInvalidSort,
+		},
+		{
+			name:    "failed due to duplicate field",
+			sort:    "email,-email",
+			wantErr: ErrInvalidSort,
+		},
+		{
+			name:    "failed due to empty field",
+			sort:    "email,",
+			wantErr: ErrInvalidSort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSort(tt.sort, UserSortFields)
+			assert.Equal(t, tt.wantErr, errors.Cause(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
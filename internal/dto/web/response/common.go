@@ -11,11 +11,14 @@ type ListResponse struct {
 	PaginationResponse `json:"pagination"`
 }
 
+// PaginationResponse has no page for cursor requests and null totals when the count is skipped
 type PaginationResponse struct {
-	Page      int   `json:"page"`
-	TotalPage int64 `json:"total_page"`
-	Limit     int   `json:"limit"`
-	Total     int64 `json:"total"`
+	Page       int      `json:"page,omitempty"`
+	TotalPage  null.Int `json:"total_page"`
+	Limit      int      `json:"limit"`
+	Total      null.Int `json:"total"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	PrevCursor string   `json:"prev_cursor,omitempty"`
 }
 
 type CreatedResponse struct {
@@ -27,3 +30,8 @@ type UpdatedResponse struct {
 	UpdatedAt null.Time   `json:"updated_at"`
 	UpdatedBy null.String `json:"updated_by"`
 }
+
+type DeletedResponse struct {
+	DeletedAt null.Time   `json:"deleted_at"`
+	DeletedBy null.String `json:"deleted_by"`
+}
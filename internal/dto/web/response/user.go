@@ -5,4 +5,5 @@ type UserResponse struct {
 	Email string `json:"email"`
 	CreatedResponse
 	UpdatedResponse
+	DeletedResponse
 }
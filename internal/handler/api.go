@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"path"
 	"strconv"
 
 	"github.com/julienschmidt/httprouter"
@@ -35,8 +36,15 @@ func (h *APIHandlerImpl) GetUserByID(w http.ResponseWriter, r *http.Request, ps
 		return
 	}
 
+	filter := req.UserDetailFilter{}
+	err = populateStructFromQueryParams(r, &filter)
+	if err != nil {
+		response.WriteFromError(w, r, response.WrapErrBadRequest(err), h.appLogger)
+		return
+	}
+
 	ctx := r.Context()
-	resp, err := h.usecase.GetUserByID(ctx, int64(id))
+	resp, err := h.usecase.GetUserByID(ctx, int64(id), filter)
 	if err != nil {
 		response.WriteFromError(w, r, err, h.appLogger)
 		return
@@ -53,13 +61,14 @@ func (h *APIHandlerImpl) CreateUser(w http.ResponseWriter, r *http.Request, _ ht
 		return
 	}
 
-	err = h.usecase.CreateUser(r.Context(), req)
+	user, err := h.usecase.CreateUser(r.Context(), req)
 	if err != nil {
 		response.WriteFromError(w, r, err, h.appLogger)
 		return
 	}
 
-	response.WriteOKResponse(w, r, "create User success", h.appLogger)
+	location := path.Join(r.URL.Path, strconv.FormatInt(user.ID, 10))
+	response.WriteCreatedResponse(w, r, location, user, h.appLogger)
 }
 
 func (h *APIHandlerImpl) UpdateUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -76,11 +85,43 @@ func (h *APIHandlerImpl) UpdateUser(w http.ResponseWriter, r *http.Request, ps h
 		return
 	}
 
-	err = h.usecase.UpdateUser(r.Context(), int64(id), req)
+	user, err := h.usecase.UpdateUser(r.Context(), int64(id), req)
+	if err != nil {
+		response.WriteFromError(w, r, err, h.appLogger)
+		return
+	}
+
+	response.WriteOKResponse(w, r, user, h.appLogger)
+}
+
+func (h *APIHandlerImpl) DeleteUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		response.WriteFromError(w, r, response.WrapErrBadRequest(err), h.appLogger)
+		return
+	}
+
+	err = h.usecase.DeleteUser(r.Context(), int64(id))
+	if err != nil {
+		response.WriteFromError(w, r, err, h.appLogger)
+		return
+	}
+
+	response.WriteNoContent(w)
+}
+
+func (h *APIHandlerImpl) RestoreUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, err := strconv.Atoi(ps.ByName("id"))
+	if err != nil {
+		response.WriteFromError(w, r, response.WrapErrBadRequest(err), h.appLogger)
+		return
+	}
+
+	err = h.usecase.RestoreUser(r.Context(), int64(id))
 	if err != nil {
 		response.WriteFromError(w, r, err, h.appLogger)
 		return
 	}
 
-	response.WriteOKResponse(w, r, "update User success", h.appLogger)
+	response.WriteNoContent(w)
 }
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,6 +13,7 @@ import (
 	randomutil "github.com/raflynagachi/go-rest-api-starter/internal/util/random"
 	"github.com/raflynagachi/go-rest-api-starter/internal/util/testutil"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/http/response"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestAPIHandlerImpl_GetUser(t *testing.T) {
@@ -61,7 +61,7 @@ func TestAPIHandlerImpl_GetUser(t *testing.T) {
 					t.Fatalf("fail to create request: %v", err)
 				}
 
-				mockUc.On("GetUser", context.Background(), req.UserFilter{}).
+				mockUc.On("GetUser", mock.Anything, req.UserFilter{}).
 					Once().Return(mockResp, nil)
 
 				return args{request: request}
@@ -96,7 +96,7 @@ func TestAPIHandlerImpl_GetUser(t *testing.T) {
 					t.Fatalf("fail to create request: %v", err)
 				}
 
-				mockUc.On("GetUser", context.Background(), req.UserFilter{}).
+				mockUc.On("GetUser", mock.Anything, req.UserFilter{}).
 					Once().Return(nil, response.WrapErrInternalServer(testutil.MockErr))
 
 				return args{request: request}
@@ -108,7 +108,7 @@ func TestAPIHandlerImpl_GetUser(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tArgs := tt.args(t)
 			resp := httptest.NewRecorder()
-			mockHandler.Router.ServeHTTP(resp, tArgs.request)
+			mockHandler.Handler().ServeHTTP(resp, tArgs.request)
 			res := resp.Result()
 
 			if res.StatusCode != tt.wantCode {
@@ -147,15 +147,15 @@ func TestAPIHandlerImpl_GetUserByID(t *testing.T) {
 			name: "success get user by ID",
 			args: func(t *testing.T) args {
 				path := fmt.Sprintf("/users/%d", mockUser.ID)
-				req, err := http.NewRequest(http.MethodGet, path, http.NoBody)
+				request, err := http.NewRequest(http.MethodGet, path, http.NoBody)
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
 
-				mockUc.On("GetUserByID", context.Background(), mockUser.ID).
+				mockUc.On("GetUserByID", mock.Anything, mockUser.ID, req.UserDetailFilter{}).
 					Once().Return(mockResp, nil)
 
-				return args{request: req}
+				return args{request: request}
 			},
 			wantCode: http.StatusOK,
 		},
@@ -163,11 +163,39 @@ func TestAPIHandlerImpl_GetUserByID(t *testing.T) {
 			name: "failed due to invalid query param",
 			args: func(t *testing.T) args {
 				path := fmt.Sprintf("/users/%s", "invalidID")
-				req, err := http.NewRequest(http.MethodGet, path, http.NoBody)
+				request, err := http.NewRequest(http.MethodGet, path, http.NoBody)
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
-				return args{request: req}
+				return args{request: request}
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "success get deleted user by ID",
+			args: func(t *testing.T) args {
+				path := fmt.Sprintf("/users/%d?include_deleted=true", mockUser.ID)
+				request, err := http.NewRequest(http.MethodGet, path, http.NoBody)
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+
+				mockUc.On("GetUserByID", mock.Anything, mockUser.ID, req.UserDetailFilter{IncludeDeleted: true}).
+					Once().Return(mockResp, nil)
+
+				return args{request: request}
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "failed due to invalid include deleted param",
+			args: func(t *testing.T) args {
+				path := fmt.Sprintf("/users/%d?include_deleted=invalid", mockUser.ID)
+				request, err := http.NewRequest(http.MethodGet, path, http.NoBody)
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+				return args{request: request}
 			},
 			wantCode: http.StatusBadRequest,
 		},
@@ -175,15 +203,15 @@ func TestAPIHandlerImpl_GetUserByID(t *testing.T) {
 			name: "failed due to user not found",
 			args: func(t *testing.T) args {
 				path := fmt.Sprintf("/users/%d", mockUser.ID)
-				req, err := http.NewRequest(http.MethodGet, path, http.NoBody)
+				request, err := http.NewRequest(http.MethodGet, path, http.NoBody)
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
 
-				mockUc.On("GetUserByID", context.Background(), mockUser.ID).
+				mockUc.On("GetUserByID", mock.Anything, mockUser.ID, req.UserDetailFilter{}).
 					Once().Return(nil, response.WrapErrNotFound(testutil.MockErr))
 
-				return args{request: req}
+				return args{request: request}
 			},
 			wantCode: http.StatusNotFound,
 		},
@@ -191,15 +219,15 @@ func TestAPIHandlerImpl_GetUserByID(t *testing.T) {
 			name: "failed due to internal server error",
 			args: func(t *testing.T) args {
 				path := fmt.Sprintf("/users/%d", mockUser.ID)
-				req, err := http.NewRequest(http.MethodGet, path, http.NoBody)
+				request, err := http.NewRequest(http.MethodGet, path, http.NoBody)
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
 
-				mockUc.On("GetUserByID", context.Background(), mockUser.ID).
+				mockUc.On("GetUserByID", mock.Anything, mockUser.ID, req.UserDetailFilter{}).
 					Once().Return(nil, response.WrapErrInternalServer(testutil.MockErr))
 
-				return args{request: req}
+				return args{request: request}
 			},
 			wantCode: http.StatusInternalServerError,
 		},
@@ -208,7 +236,7 @@ func TestAPIHandlerImpl_GetUserByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tArgs := tt.args(t)
 			resp := httptest.NewRecorder()
-			mockHandler.Router.ServeHTTP(resp, tArgs.request)
+			mockHandler.Handler().ServeHTTP(resp, tArgs.request)
 			res := resp.Result()
 
 			if res.StatusCode != tt.wantCode {
@@ -225,15 +253,17 @@ func TestAPIHandlerImpl_CreateUser(t *testing.T) {
 	mockReq := &req.CreateUpdateUserReq{
 		Email: mockUser.Email,
 	}
+	mockUserResp := &resp.UserResponse{ID: mockUser.ID, Email: mockUser.Email}
 
 	type args struct {
 		request *http.Request
 	}
 
 	tests := []struct {
-		name     string
-		args     func(t *testing.T) args
-		wantCode int
+		name         string
+		args         func(t *testing.T) args
+		wantCode     int
+		wantLocation string
 	}{
 		{
 			name: "success create user",
@@ -249,13 +279,43 @@ func TestAPIHandlerImpl_CreateUser(t *testing.T) {
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+mockToken)
 
-				mockUc.On("CreateUser", req.Context(), reqModel).
-					Once().Return(nil)
+				mockUc.On("CreateUser", mock.Anything, reqModel).
+					Once().Return(mockUserResp, nil)
 
 				return args{request: req}
 			},
-			wantCode: http.StatusOK,
+			wantCode:     http.StatusCreated,
+			wantLocation: fmt.Sprintf("/users/%d", mockUser.ID),
+		},
+		{
+			name: "failed due to missing token",
+			args: func(t *testing.T) args {
+				req, err := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer([]byte("{}")))
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+				req.Header.Set("Content-Type", "application/json")
+
+				return args{request: req}
+			},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "failed due to unsupported media type",
+			args: func(t *testing.T) args {
+				req, err := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer([]byte("{}")))
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+				req.Header.Set("Content-Type", "text/plain")
+				req.Header.Set("Authorization", "Bearer "+mockToken)
+
+				return args{request: req}
+			},
+			wantCode: http.StatusUnsupportedMediaType,
 		},
 		{
 			name: "failed due to json encode error",
@@ -264,6 +324,8 @@ func TestAPIHandlerImpl_CreateUser(t *testing.T) {
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+mockToken)
 
 				return args{request: req}
 			},
@@ -283,9 +345,11 @@ func TestAPIHandlerImpl_CreateUser(t *testing.T) {
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+mockToken)
 
-				mockUc.On("CreateUser", req.Context(), reqModel).
-					Once().Return(response.WrapErrBadRequest(testutil.MockErr))
+				mockUc.On("CreateUser", mock.Anything, reqModel).
+					Once().Return(nil, response.WrapErrBadRequest(testutil.MockErr))
 
 				return args{request: req}
 			},
@@ -305,9 +369,11 @@ func TestAPIHandlerImpl_CreateUser(t *testing.T) {
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+mockToken)
 
-				mockUc.On("CreateUser", req.Context(), reqModel).
-					Once().Return(response.WrapErrInternalServer(testutil.MockErr))
+				mockUc.On("CreateUser", mock.Anything, reqModel).
+					Once().Return(nil, response.WrapErrInternalServer(testutil.MockErr))
 
 				return args{request: req}
 			},
@@ -318,13 +384,16 @@ func TestAPIHandlerImpl_CreateUser(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tArgs := tt.args(t)
 			resp := httptest.NewRecorder()
-			mockHandler.Router.ServeHTTP(resp, tArgs.request)
+			mockHandler.Handler().ServeHTTP(resp, tArgs.request)
 			res := resp.Result()
 
 			if res.StatusCode != tt.wantCode {
 				t.Errorf("APIHandler.CreateUser() code = %v, wantCode %v", res.StatusCode, tt.wantCode)
 				return
 			}
+			if location := res.Header.Get("Location"); location != tt.wantLocation {
+				t.Errorf("APIHandler.CreateUser() Location = %v, wantLocation %v", location, tt.wantLocation)
+			}
 		})
 	}
 }
@@ -335,6 +404,7 @@ func TestAPIHandlerImpl_UpdateUser(t *testing.T) {
 	mockReq := &req.CreateUpdateUserReq{
 		Email: mockUser.Email,
 	}
+	mockUserResp := &resp.UserResponse{ID: mockUser.ID, Email: mockUser.Email}
 
 	type args struct {
 		request *http.Request
@@ -360,14 +430,31 @@ func TestAPIHandlerImpl_UpdateUser(t *testing.T) {
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+mockToken)
 
-				mockUc.On("UpdateUser", req.Context(), mockUser.ID, reqModel).
-					Once().Return(nil)
+				mockUc.On("UpdateUser", mock.Anything, mockUser.ID, reqModel).
+					Once().Return(mockUserResp, nil)
 
 				return args{request: req}
 			},
 			wantCode: http.StatusOK,
 		},
+		{
+			name: "failed_errorUnauthorized",
+			args: func(t *testing.T) args {
+				path := fmt.Sprintf("/users/%d", mockUser.ID)
+				req, err := http.NewRequest(http.MethodPut, path, bytes.NewBuffer([]byte("{}")))
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer invalid")
+
+				return args{request: req}
+			},
+			wantCode: http.StatusUnauthorized,
+		},
 		{
 			name: "failed_errorInvalidIDParam",
 			args: func(t *testing.T) args {
@@ -376,6 +463,8 @@ func TestAPIHandlerImpl_UpdateUser(t *testing.T) {
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+mockToken)
 
 				return args{request: req}
 			},
@@ -389,6 +478,8 @@ func TestAPIHandlerImpl_UpdateUser(t *testing.T) {
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+mockToken)
 
 				return args{request: req}
 			},
@@ -409,9 +500,11 @@ func TestAPIHandlerImpl_UpdateUser(t *testing.T) {
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+mockToken)
 
-				mockUc.On("UpdateUser", req.Context(), mockUser.ID, reqModel).
-					Once().Return(response.WrapErrBadRequest(testutil.MockErr))
+				mockUc.On("UpdateUser", mock.Anything, mockUser.ID, reqModel).
+					Once().Return(nil, response.WrapErrBadRequest(testutil.MockErr))
 
 				return args{request: req}
 			},
@@ -432,9 +525,11 @@ func TestAPIHandlerImpl_UpdateUser(t *testing.T) {
 				if err != nil {
 					t.Fatalf("fail to create request: %v", err)
 				}
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+mockToken)
 
-				mockUc.On("UpdateUser", req.Context(), mockUser.ID, reqModel).
-					Once().Return(response.WrapErrInternalServer(testutil.MockErr))
+				mockUc.On("UpdateUser", mock.Anything, mockUser.ID, reqModel).
+					Once().Return(nil, response.WrapErrInternalServer(testutil.MockErr))
 
 				return args{request: req}
 			},
@@ -445,7 +540,7 @@ func TestAPIHandlerImpl_UpdateUser(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tArgs := tt.args(t)
 			resp := httptest.NewRecorder()
-			mockHandler.Router.ServeHTTP(resp, tArgs.request)
+			mockHandler.Handler().ServeHTTP(resp, tArgs.request)
 			res := resp.Result()
 
 			if res.StatusCode != tt.wantCode {
@@ -455,3 +550,177 @@ func TestAPIHandlerImpl_UpdateUser(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIHandlerImpl_DeleteUser(t *testing.T) {
+	mockUser := randomutil.RandomUser()
+
+	type args struct {
+		request *http.Request
+	}
+
+	tests := []struct {
+		name     string
+		args     func(t *testing.T) args
+		wantCode int
+	}{
+		{
+			name: "success delete user",
+			args: func(t *testing.T) args {
+				path := fmt.Sprintf("/users/%d", mockUser.ID)
+				request, err := http.NewRequest(http.MethodDelete, path, http.NoBody)
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+				request.Header.Set("Authorization", "Bearer "+mockToken)
+
+				mockUc.On("DeleteUser", mock.Anything, mockUser.ID).Once().Return(nil)
+
+				return args{request: request}
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name: "failed due to missing token",
+			args: func(t *testing.T) args {
+				path := fmt.Sprintf("/users/%d", mockUser.ID)
+				request, err := http.NewRequest(http.MethodDelete, path, http.NoBody)
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+
+				return args{request: request}
+			},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "failed due to invalid ID param",
+			args: func(t *testing.T) args {
+				request, err := http.NewRequest(http.MethodDelete, "/users/invalid", http.NoBody)
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+				request.Header.Set("Authorization", "Bearer "+mockToken)
+
+				return args{request: request}
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "failed due to user not found",
+			args: func(t *testing.T) args {
+				path := fmt.Sprintf("/users/%d", mockUser.ID)
+				request, err := http.NewRequest(http.MethodDelete, path, http.NoBody)
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+				request.Header.Set("Authorization", "Bearer "+mockToken)
+
+				mockUc.On("DeleteUser", mock.Anything, mockUser.ID).
+					Once().Return(response.WrapErrNotFound(testutil.MockErr))
+
+				return args{request: request}
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tArgs := tt.args(t)
+			resp := httptest.NewRecorder()
+			mockHandler.Handler().ServeHTTP(resp, tArgs.request)
+			res := resp.Result()
+
+			if res.StatusCode != tt.wantCode {
+				t.Errorf("APIHandler.DeleteUser() code = %v, wantCode %v", res.StatusCode, tt.wantCode)
+				return
+			}
+		})
+	}
+}
+
+func TestAPIHandlerImpl_RestoreUser(t *testing.T) {
+	mockUser := randomutil.RandomUser()
+
+	type args struct {
+		request *http.Request
+	}
+
+	tests := []struct {
+		name     string
+		args     func(t *testing.T) args
+		wantCode int
+	}{
+		{
+			name: "success restore user",
+			args: func(t *testing.T) args {
+				path := fmt.Sprintf("/users/%d/restore", mockUser.ID)
+				request, err := http.NewRequest(http.MethodPost, path, http.NoBody)
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+				request.Header.Set("Authorization", "Bearer "+mockToken)
+
+				mockUc.On("RestoreUser", mock.Anything, mockUser.ID).Once().Return(nil)
+
+				return args{request: request}
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name: "failed due to missing token",
+			args: func(t *testing.T) args {
+				path := fmt.Sprintf("/users/%d/restore", mockUser.ID)
+				request, err := http.NewRequest(http.MethodPost, path, http.NoBody)
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+
+				return args{request: request}
+			},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "failed due to invalid ID param",
+			args: func(t *testing.T) args {
+				request, err := http.NewRequest(http.MethodPost, "/users/invalid/restore", http.NoBody)
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+				request.Header.Set("Authorization", "Bearer "+mockToken)
+
+				return args{request: request}
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "failed due to deleted user not found",
+			args: func(t *testing.T) args {
+				path := fmt.Sprintf("/users/%d/restore", mockUser.ID)
+				request, err := http.NewRequest(http.MethodPost, path, http.NoBody)
+				if err != nil {
+					t.Fatalf("fail to create request: %v", err)
+				}
+				request.Header.Set("Authorization", "Bearer "+mockToken)
+
+				mockUc.On("RestoreUser", mock.Anything, mockUser.ID).
+					Once().Return(response.WrapErrNotFound(testutil.MockErr))
+
+				return args{request: request}
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tArgs := tt.args(t)
+			resp := httptest.NewRecorder()
+			mockHandler.Handler().ServeHTTP(resp, tArgs.request)
+			res := resp.Result()
+
+			if res.StatusCode != tt.wantCode {
+				t.Errorf("APIHandler.RestoreUser() code = %v, wantCode %v", res.StatusCode, tt.wantCode)
+				return
+			}
+		})
+	}
+}
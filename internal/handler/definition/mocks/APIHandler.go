@@ -19,6 +19,11 @@ func (_m *APIHandler) CreateUser(w http.ResponseWriter, r *http.Request, ps http
 	_m.Called(w, r, ps)
 }
 
+// DeleteUser provides a mock function with given fields: w, r, ps
+func (_m *APIHandler) DeleteUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	_m.Called(w, r, ps)
+}
+
 // GetUser provides a mock function with given fields: w, r, ps
 func (_m *APIHandler) GetUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	_m.Called(w, r, ps)
@@ -29,6 +34,11 @@ func (_m *APIHandler) GetUserByID(w http.ResponseWriter, r *http.Request, ps htt
 	_m.Called(w, r, ps)
 }
 
+// RestoreUser provides a mock function with given fields: w, r, ps
+func (_m *APIHandler) RestoreUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	_m.Called(w, r, ps)
+}
+
 // UpdateUser provides a mock function with given fields: w, r, ps
 func (_m *APIHandler) UpdateUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	_m.Called(w, r, ps)
@@ -11,16 +11,28 @@ import (
 	"github.com/raflynagachi/go-rest-api-starter/internal/handler/router"
 	uc "github.com/raflynagachi/go-rest-api-starter/internal/usecase/definition"
 	"github.com/raflynagachi/go-rest-api-starter/internal/usecase/definition/mocks"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/health"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/jwt"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
 )
 
 var (
-	cfg         = &config.Config{}
+	cfg         = &config.Config{JwtKey: "secret"}
+	mockToken   = mustSignToken(&jwt.Claims{Email: "SYSTEM"})
 	mockUc      = new(mocks.APIUsecase)
 	mockLogger  = logger.NewLogger()
-	mockHandler = router.New(cfg, mockLogger, New(mockUc, mockLogger))
+	mockHandler = router.New(cfg, mockLogger, New(mockUc, mockLogger), health.NewRegistry(0, 0), metrics.NewRegistry())
 )
 
+func mustSignToken(claims *jwt.Claims) string {
+	token, err := jwt.SignHS256(claims, cfg.JwtKey)
+	if err != nil {
+		log.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
 func TestMain(m *testing.M) {
 	log.SetOutput(io.Discard)
 	m.Run()
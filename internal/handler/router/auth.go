@@ -0,0 +1,77 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/response"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/jwt"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+
+	// RoleAdmin is the token role required by the admin endpoints
+	RoleAdmin = "admin"
+)
+
+var (
+	ErrMissingToken      = errors.New("missing bearer token")
+	ErrAuthNotConfigured = errors.New("authentication is not configured")
+	ErrMissingRole       = errors.New("token does not grant the required role")
+)
+
+// newAuthenticate creates a middleware that verifies the bearer token
+// and stores the authenticated claims in the request context.
+// A nil verifier rejects every request.
+func newAuthenticate(verifier *jwt.Verifier, log *logger.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := verifyRequest(verifier, r)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+				response.WriteFromError(w, r, response.WrapErrUnauthorized(err), log)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(jwt.NewContext(r.Context(), claims)))
+		})
+	}
+}
+
+func verifyRequest(verifier *jwt.Verifier, r *http.Request) (*jwt.Claims, error) {
+	if verifier == nil {
+		return nil, ErrAuthNotConfigured
+	}
+
+	header := r.Header.Get(authorizationHeader)
+	if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return nil, ErrMissingToken
+	}
+
+	token := strings.TrimSpace(header[len(bearerPrefix):])
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+
+	return verifier.Verify(token)
+}
+
+// newRequireRole creates a middleware that rejects requests whose authenticated claims
+// do not grant the role. It must run after the authenticate middleware.
+func newRequireRole(role string, log *logger.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := jwt.FromContext(r.Context())
+			if !ok || !claims.HasRole(role) {
+				response.WriteFromError(w, r, response.WrapErrForbidden(ErrMissingRole), log)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticate(t *testing.T) {
+	verifier, err := jwt.NewVerifier("secret")
+	require.NoError(t, err)
+
+	mockClaims := &jwt.Claims{
+		Email:     "user@mail.com",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}
+	validToken, err := jwt.SignHS256(mockClaims, "secret")
+	require.NoError(t, err)
+	expiredToken, err := jwt.SignHS256(&jwt.Claims{Email: "user@mail.com", ExpiresAt: time.Now().Add(-time.Hour).Unix()}, "secret")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		verifier   *jwt.Verifier
+		header     string
+		wantCode   int
+		wantClaims *jwt.Claims
+	}{
+		{
+			name:       "success with valid token",
+			verifier:   verifier,
+			header:     "Bearer " + validToken,
+			wantCode:   http.StatusOK,
+			wantClaims: mockClaims,
+		},
+		{
+			name:       "success with lowercase scheme",
+			verifier:   verifier,
+			header:     "bearer " + validToken,
+			wantCode:   http.StatusOK,
+			wantClaims: mockClaims,
+		},
+		{
+			name:     "failed due to missing header",
+			verifier: verifier,
+			header:   "",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "failed due to non bearer scheme",
+			verifier: verifier,
+			header:   "Basic dXNlcjpwYXNz",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "failed due to empty token",
+			verifier: verifier,
+			header:   "Bearer  ",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "failed due to expired token",
+			verifier: verifier,
+			header:   "Bearer " + expiredToken,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "failed due to authentication not configured",
+			verifier: nil,
+			header:   "Bearer " + validToken,
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotClaims *jwt.Claims
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotClaims, _ = jwt.FromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodPost, "/users", http.NoBody)
+			if tt.header != "" {
+				request.Header.Set(authorizationHeader, tt.header)
+			}
+			recorder := httptest.NewRecorder()
+
+			newAuthenticate(tt.verifier, mockLogger)(next).ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.wantCode, recorder.Code)
+			assert.Equal(t, tt.wantClaims, gotClaims)
+			if tt.wantCode == http.StatusUnauthorized {
+				assert.NotEmpty(t, recorder.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/health"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/encoder"
+)
+
+// newProbe writes the probe report as JSON with 200 when healthy, 503 otherwise
+func newProbe(probe func(ctx context.Context) health.Report) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		report := probe(r.Context())
+
+		code := http.StatusOK
+		if !report.Healthy() {
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		encoder.EncodeJson(w, report)
+	}
+}
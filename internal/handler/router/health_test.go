@@ -0,0 +1,93 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/health"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_HealthProbes(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		setup      func(r *Router, registry *health.Registry)
+		wantCode   int
+		wantStatus string
+		wantChecks []string
+	}{
+		{
+			name: "success liveness ignores readiness checks",
+			path: "/healthz",
+			setup: func(r *Router, registry *health.Registry) {
+				registry.Register("database", health.CheckFunc(func(ctx context.Context) error { return errors.New("connection refused") }))
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: health.StatusOK,
+			wantChecks: []string{},
+		},
+		{
+			name: "success readiness",
+			path: "/readyz",
+			setup: func(r *Router, registry *health.Registry) {
+				registry.Register("database", health.CheckFunc(func(ctx context.Context) error { return nil }))
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: health.StatusOK,
+			wantChecks: []string{"database"},
+		},
+		{
+			name: "failed readiness due to failing check",
+			path: "/readyz",
+			setup: func(r *Router, registry *health.Registry) {
+				registry.Register("database", health.CheckFunc(func(ctx context.Context) error { return errors.New("connection refused") }))
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: health.StatusFail,
+			wantChecks: []string{"database"},
+		},
+		{
+			name: "failed readiness once shutdown starts",
+			path: "/readyz",
+			setup: func(r *Router, registry *health.Registry) {
+				registry.Register("database", health.CheckFunc(func(ctx context.Context) error { return nil }))
+				require.NoError(t, r.Shutdown(context.Background()))
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: health.StatusFail,
+			wantChecks: []string{"shutdown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := health.NewRegistry(0, 0)
+			r := New(mockCfg, mockLogger, mockHandler, registry, metrics.NewRegistry())
+			tt.setup(r, registry)
+
+			recorder := httptest.NewRecorder()
+			r.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, http.NoBody))
+
+			assert.Equal(t, tt.wantCode, recorder.Code)
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			assert.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+
+			report := health.Report{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+			assert.Equal(t, tt.wantStatus, report.Status)
+
+			gotChecks := []string{}
+			for name := range report.Checks {
+				gotChecks = append(gotChecks, name)
+			}
+			assert.ElementsMatch(t, tt.wantChecks, gotChecks)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package router
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/encoder"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/response"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/jwt"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+)
+
+var (
+	ErrMissingLogLevel = errors.New("level is required without logger")
+)
+
+// logLevelReq sets the base level, or the override of Logger when it is set.
+// An empty level with a logger removes its override.
+type logLevelReq struct {
+	Level  string `json:"level"`
+	Logger string `json:"logger"`
+}
+
+type logLevelResp struct {
+	Level     string            `json:"level"`
+	Overrides map[string]string `json:"overrides"`
+}
+
+// getLogLevel writes the current base level and overrides
+func getLogLevel(levels *logger.Levels, log *logger.Logger) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		response.WriteOKResponse(w, r, newLogLevelResp(levels), log)
+	}
+}
+
+// putLogLevel changes the base level or a logger override and writes the resulting levels
+func putLogLevel(levels *logger.Levels, log *logger.Logger) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		req := logLevelReq{}
+		if err := encoder.DecodeJson(r, &req); err != nil {
+			response.WriteFromError(w, r, response.WrapErrBadRequest(err), log)
+			return
+		}
+
+		if req.Level == "" {
+			if req.Logger == "" {
+				response.WriteFromError(w, r, response.WrapErrBadRequest(ErrMissingLogLevel), log)
+				return
+			}
+			levels.RemoveOverride(req.Logger)
+		} else {
+			level, err := logger.ParseLevel(req.Level)
+			if err != nil {
+				response.WriteFromError(w, r, response.WrapErrBadRequest(err), log)
+				return
+			}
+			if req.Logger == "" {
+				levels.SetLevel(level)
+			} else {
+				levels.SetOverride(req.Logger, level)
+			}
+		}
+
+		var principal string
+		if claims, ok := jwt.FromContext(r.Context()); ok {
+			principal = claims.Principal()
+		}
+		log.InfoContext(r.Context(), "log level changed",
+			slog.String("level", req.Level),
+			slog.String("logger", req.Logger),
+			slog.String("principal", principal),
+		)
+
+		response.WriteOKResponse(w, r, newLogLevelResp(levels), log)
+	}
+}
+
+func newLogLevelResp(levels *logger.Levels) logLevelResp {
+	overrides := map[string]string{}
+	for name, level := range levels.Overrides() {
+		overrides[name] = level.String()
+	}
+	return logLevelResp{Level: levels.Level().String(), Overrides: overrides}
+}
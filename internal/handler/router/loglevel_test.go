@@ -0,0 +1,155 @@
+package router
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/health"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/jwt"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_LogLevel(t *testing.T) {
+	token, err := jwt.SignHS256(&jwt.Claims{Email: "admin@mail.com", ExpiresAt: time.Now().Add(time.Hour).Unix(), Roles: []string{RoleAdmin}}, mockCfg.JwtKey)
+	require.NoError(t, err)
+	userToken, err := jwt.SignHS256(&jwt.Claims{Email: "user@mail.com", ExpiresAt: time.Now().Add(time.Hour).Unix()}, mockCfg.JwtKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		method        string
+		body          string
+		token         string
+		setup         func(levels *logger.Levels)
+		wantCode      int
+		wantLevel     string
+		wantOverrides map[string]string
+	}{
+		{
+			name:          "success get levels",
+			method:        http.MethodGet,
+			token:         token,
+			setup:         func(levels *logger.Levels) { levels.SetOverride("repository", logger.LevelDebug) },
+			wantCode:      http.StatusOK,
+			wantLevel:     "INFO",
+			wantOverrides: map[string]string{"repository": "DEBUG"},
+		},
+		{
+			name:          "success set base level",
+			method:        http.MethodPut,
+			body:          `{"level":"debug"}`,
+			token:         token,
+			wantCode:      http.StatusOK,
+			wantLevel:     "DEBUG",
+			wantOverrides: map[string]string{},
+		},
+		{
+			name:          "success set logger override",
+			method:        http.MethodPut,
+			body:          `{"level":"error","logger":"usecase"}`,
+			token:         token,
+			wantCode:      http.StatusOK,
+			wantLevel:     "INFO",
+			wantOverrides: map[string]string{"usecase": "ERROR"},
+		},
+		{
+			name:          "success remove logger override",
+			method:        http.MethodPut,
+			body:          `{"logger":"usecase"}`,
+			token:         token,
+			setup:         func(levels *logger.Levels) { levels.SetOverride("usecase", logger.LevelError) },
+			wantCode:      http.StatusOK,
+			wantLevel:     "INFO",
+			wantOverrides: map[string]string{},
+		},
+		{
+			name:      "failed due to unknown level",
+			method:    http.MethodPut,
+			body:      `{"level":"verbose"}`,
+			token:     token,
+			wantCode:  http.StatusBadRequest,
+			wantLevel: "INFO",
+		},
+		{
+			name:      "failed due to missing level",
+			method:    http.MethodPut,
+			body:      `{}`,
+			token:     token,
+			wantCode:  http.StatusBadRequest,
+			wantLevel: "INFO",
+		},
+		{
+			name:      "failed due to missing token",
+			method:    http.MethodPut,
+			body:      `{"level":"debug"}`,
+			wantCode:  http.StatusUnauthorized,
+			wantLevel: "INFO",
+		},
+		{
+			name:      "failed due to token without admin role",
+			method:    http.MethodPut,
+			body:      `{"level":"debug"}`,
+			token:     userToken,
+			wantCode:  http.StatusForbidden,
+			wantLevel: "INFO",
+		},
+		{
+			name:      "failed get levels due to token without admin role",
+			method:    http.MethodGet,
+			token:     userToken,
+			wantCode:  http.StatusForbidden,
+			wantLevel: "INFO",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels := logger.NewLevels(logger.LevelInfo)
+			log := logger.NewLogger(logger.WithEnv("test"), logger.WithLevels(levels))
+			if tt.setup != nil {
+				tt.setup(levels)
+			}
+			r := New(mockCfg, log, mockHandler, health.NewRegistry(0, 0), metrics.NewRegistry())
+
+			req := httptest.NewRequest(tt.method, "/admin/log-level", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			recorder := httptest.NewRecorder()
+			r.Handler().ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.wantCode, recorder.Code)
+			assert.Equal(t, tt.wantLevel, levels.Level().String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var got struct {
+				Data logLevelResp `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			assert.Equal(t, logLevelResp{Level: tt.wantLevel, Overrides: tt.wantOverrides}, got.Data)
+		})
+	}
+
+	t.Run("not registered for loggers without runtime levels", func(t *testing.T) {
+		r := New(mockCfg, slog.New(slog.NewTextHandler(io.Discard, nil)), mockHandler, health.NewRegistry(0, 0), metrics.NewRegistry())
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/log-level", http.NoBody)
+		req.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		r.Handler().ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
@@ -0,0 +1,117 @@
+package router
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/middleware"
+)
+
+// Middleware wraps an http.Handler with additional behaviour
+type Middleware func(http.Handler) http.Handler
+
+// Chain composes middlewares into a single Middleware.
+// The first middleware is the outermost, so it runs first on the way in and last on the way out.
+func Chain(mws ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+		return next
+	}
+}
+
+// Group registers routes sharing a path prefix and a middleware stack.
+// Middlewares are applied in order: parent group, group, then route middlewares.
+type Group struct {
+	router      *httprouter.Router
+	prefix      string
+	middlewares []Middleware
+}
+
+// NewGroup creates a root Group on the given router
+func NewGroup(router *httprouter.Router, prefix string, mws ...Middleware) *Group {
+	return &Group{
+		router:      router,
+		prefix:      prefix,
+		middlewares: mws,
+	}
+}
+
+// Use appends middlewares to the group.
+// It only affects routes registered after the call.
+func (g *Group) Use(mws ...Middleware) {
+	g.middlewares = append(g.middlewares, mws...)
+}
+
+// Group creates a sub group inheriting the current prefix and middlewares
+func (g *Group) Group(prefix string, mws ...Middleware) *Group {
+	middlewares := make([]Middleware, 0, len(g.middlewares)+len(mws))
+	middlewares = append(middlewares, g.middlewares...)
+	middlewares = append(middlewares, mws...)
+
+	return &Group{
+		router:      g.router,
+		prefix:      joinPath(g.prefix, prefix),
+		middlewares: middlewares,
+	}
+}
+
+// Handle registers a handle for the given method and path with optional route middlewares
+func (g *Group) Handle(method, path string, handle httprouter.Handle, mws ...Middleware) {
+	middlewares := make([]Middleware, 0, len(g.middlewares)+len(mws))
+	middlewares = append(middlewares, g.middlewares...)
+	middlewares = append(middlewares, mws...)
+
+	pattern := joinPath(g.prefix, path)
+	handler := Chain(middlewares...)(toHandler(handle))
+	g.router.Handler(method, pattern, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		middleware.SetRoutePattern(r.Context(), pattern)
+		handler.ServeHTTP(w, r)
+	}))
+}
+
+func (g *Group) GET(path string, handle httprouter.Handle, mws ...Middleware) {
+	g.Handle(http.MethodGet, path, handle, mws...)
+}
+
+func (g *Group) POST(path string, handle httprouter.Handle, mws ...Middleware) {
+	g.Handle(http.MethodPost, path, handle, mws...)
+}
+
+func (g *Group) PUT(path string, handle httprouter.Handle, mws ...Middleware) {
+	g.Handle(http.MethodPut, path, handle, mws...)
+}
+
+func (g *Group) PATCH(path string, handle httprouter.Handle, mws ...Middleware) {
+	g.Handle(http.MethodPatch, path, handle, mws...)
+}
+
+func (g *Group) DELETE(path string, handle httprouter.Handle, mws ...Middleware) {
+	g.Handle(http.MethodDelete, path, handle, mws...)
+}
+
+// toHandler adapts httprouter.Handle to http.Handler, reading params stored by httprouter in the context
+func toHandler(handle httprouter.Handle) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handle(w, r, httprouter.ParamsFromContext(r.Context()))
+	})
+}
+
+func joinPath(prefix, p string) string {
+	if p == "" {
+		if prefix == "" {
+			return "/"
+		}
+		return prefix
+	}
+
+	joined := path.Join("/", prefix, p)
+	// keep trailing slash because httprouter treats it as a different route
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
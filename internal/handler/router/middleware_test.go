@@ -0,0 +1,172 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+// traceMiddleware records when the middleware is entered and left
+func traceMiddleware(name string, trace *[]string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*trace = append(*trace, name+">")
+			next.ServeHTTP(w, r)
+			*trace = append(*trace, "<"+name)
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	trace := []string{}
+	handler := Chain(
+		traceMiddleware("a", &trace),
+		traceMiddleware("b", &trace),
+		traceMiddleware("c", &trace),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "handler")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.Equal(t, []string{"a>", "b>", "c>", "handler", "<c", "<b", "<a"}, trace)
+}
+
+func TestChain_Empty(t *testing.T) {
+	called := false
+	handler := Chain()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.True(t, called)
+}
+
+func TestRouter_MiddlewareOrder(t *testing.T) {
+	trace := []string{}
+	r := &Router{Router: httprouter.New()}
+
+	r.Use(traceMiddleware("global1", &trace), traceMiddleware("global2", &trace))
+
+	api := r.Group("/api", traceMiddleware("api", &trace))
+	users := api.Group("/users", traceMiddleware("users", &trace))
+	users.Use(traceMiddleware("users-use", &trace))
+	users.GET("/:id", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		trace = append(trace, "handler:"+ps.ByName("id"))
+		w.WriteHeader(http.StatusOK)
+	}, traceMiddleware("route1", &trace), traceMiddleware("route2", &trace))
+
+	recorder := httptest.NewRecorder()
+	r.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/users/42", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{
+		"global1>", "global2>", "api>", "users>", "users-use>", "route1>", "route2>",
+		"handler:42",
+		"<route2", "<route1", "<users-use", "<users", "<api", "<global2", "<global1",
+	}, trace)
+}
+
+func TestRouter_GlobalMiddlewareOnNotFound(t *testing.T) {
+	trace := []string{}
+	r := &Router{Router: httprouter.New()}
+	r.Use(traceMiddleware("global", &trace))
+	r.Group("/api", traceMiddleware("api", &trace)).GET("/ping", Ping)
+
+	recorder := httptest.NewRecorder()
+	r.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/unknown", http.NoBody))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, []string{"global>", "<global"}, trace)
+}
+
+func TestGroup_UseAffectsLaterRoutesOnly(t *testing.T) {
+	trace := []string{}
+	router := httprouter.New()
+	g := NewGroup(router, "")
+
+	g.GET("/before", Ping)
+	g.Use(traceMiddleware("late", &trace))
+	g.GET("/after", Ping)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/before", http.NoBody))
+	assert.Empty(t, trace)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/after", http.NoBody))
+	assert.Equal(t, []string{"late>", "<late"}, trace)
+}
+
+func TestGroup_SubGroupDoesNotLeak(t *testing.T) {
+	trace := []string{}
+	router := httprouter.New()
+	parent := NewGroup(router, "/api")
+	parent.Group("/admin", traceMiddleware("admin", &trace)).GET("/ping", Ping)
+	parent.GET("/ping", Ping)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/ping", http.NoBody))
+	assert.Empty(t, trace)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/admin/ping", http.NoBody))
+	assert.Equal(t, []string{"admin>", "<admin"}, trace)
+}
+
+func TestGroup_Methods(t *testing.T) {
+	router := httprouter.New()
+	g := NewGroup(router, "/items")
+
+	handle := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.Write([]byte(r.Method))
+	}
+	g.GET("", handle)
+	g.POST("", handle)
+	g.PUT("/:id", handle)
+	g.PATCH("/:id", handle)
+	g.DELETE("/:id", handle)
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/items"},
+		{http.MethodPost, "/items"},
+		{http.MethodPut, "/items/1"},
+		{http.MethodPatch, "/items/1"},
+		{http.MethodDelete, "/items/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, http.NoBody))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, tt.method, strings.TrimSpace(recorder.Body.String()))
+		})
+	}
+}
+
+func TestJoinPath(t *testing.T) {
+	tests := []struct {
+		prefix string
+		path   string
+		want   string
+	}{
+		{"", "", "/"},
+		{"/users", "", "/users"},
+		{"", "/users", "/users"},
+		{"/api", "/users/:id", "/api/users/:id"},
+		{"/api/", "users", "/api/users"},
+		{"/api", "/users/", "/api/users/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix+"+"+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, joinPath(tt.prefix, tt.path))
+		})
+	}
+}
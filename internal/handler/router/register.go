@@ -5,19 +5,37 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	hn "github.com/raflynagachi/go-rest-api-starter/internal/handler/definition"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/health"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
 )
 
-func newRouter(hn hn.APIHandler) *httprouter.Router {
+func newRouter(hn hn.APIHandler, authenticate Middleware, healthRegistry *health.Registry, metricsRegistry *metrics.Registry, log *logger.Logger) *httprouter.Router {
 	router := httprouter.New()
-	// middlewares
+	root := NewGroup(router, "")
 
 	// API
-	router.GET("/users", hn.GetUser)
-	router.GET("/users/:id", hn.GetUserByID)
-	router.POST("/users", hn.CreateUser)
-	router.PUT("/users/:id", hn.UpdateUser)
+	users := root.Group("/users")
+	users.GET("", hn.GetUser)
+	users.GET("/:id", hn.GetUserByID)
+	users.POST("", hn.CreateUser, authenticate)
+	users.PUT("/:id", hn.UpdateUser, authenticate)
+	users.DELETE("/:id", hn.DeleteUser, authenticate)
+	users.POST("/:id/restore", hn.RestoreUser, authenticate)
 
-	router.GET("/ping", Ping)
+	root.GET("/ping", Ping)
+	root.GET("/healthz", newProbe(healthRegistry.Liveness))
+	root.GET("/readyz", newProbe(healthRegistry.Readiness))
+	root.GET("/metrics", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		metricsRegistry.Handler().ServeHTTP(w, r)
+	})
+
+	// admin, only for loggers created by logger.NewLogger
+	if levels, ok := logger.LevelsOf(log); ok {
+		admin := root.Group("/admin", authenticate, newRequireRole(RoleAdmin, log))
+		admin.GET("/log-level", getLogLevel(levels, log))
+		admin.PUT("/log-level", putLogLevel(levels, log))
+	}
 
 	return router
 }
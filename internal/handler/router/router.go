@@ -5,50 +5,135 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/raflynagachi/go-rest-api-starter/config"
 	hn "github.com/raflynagachi/go-rest-api-starter/internal/handler/definition"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/health"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/middleware"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/jwt"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
 )
 
 type Router struct {
-	Cfg       *config.Config
-	Router    *httprouter.Router
-	appLogger *logger.Logger
-	server    *http.Server
-	mu        sync.Mutex // mutex to ensure thread-safe access
+	Cfg         *config.Config
+	Router      *httprouter.Router
+	appLogger   *logger.Logger
+	server      *http.Server
+	closed      bool // set by Shutdown, a later Start does not serve
+	health      *health.Registry
+	middlewares []Middleware // global middlewares wrapping every request, including 404 and 405
+	mu          sync.Mutex   // mutex to ensure thread-safe access
 }
 
 // New creates a new Router instance
-func New(cfg *config.Config, log *logger.Logger, hn hn.APIHandler) *Router {
-	router := newRouter(hn)
-	return &Router{
+func New(cfg *config.Config, log *logger.Logger, hn hn.APIHandler, healthRegistry *health.Registry, metricsRegistry *metrics.Registry) *Router {
+	verifier, err := jwt.NewVerifier(cfg.JwtKey)
+	if err != nil {
+		log.Error("failed to init jwt verifier, protected routes will reject every request", logger.ErrAttr(err))
+	}
+
+	router := &Router{
 		Cfg:       cfg,
 		appLogger: log,
-		Router:    router,
+		Router:    newRouter(hn, newAuthenticate(verifier, log), healthRegistry, metricsRegistry, log),
+		health:    healthRegistry,
+	}
+	router.Use(middleware.RequestID, middleware.Tracing)
+
+	// recovery runs outside the access log and the http metrics, which see a panic as the 500 it writes
+	recovery, err := middleware.NewRecovery(log, metricsRegistry)
+	if err != nil {
+		log.Error("failed to register panic metrics, panics will not be recovered", logger.ErrAttr(err))
+	} else {
+		router.Use(recovery.Middleware)
+	}
+
+	router.Use(newAccessLog(cfg, log).Middleware)
+
+	httpMetrics, err := middleware.NewHTTPMetrics(metricsRegistry)
+	if err != nil {
+		log.Error("failed to register http metrics, requests will not be instrumented", logger.ErrAttr(err))
+	} else {
+		router.Use(httpMetrics.Middleware)
 	}
+
+	return router
+}
+
+// accessLoggerName names the access logger, so log.sampling.exempt can keep every request record
+const accessLoggerName = "access"
+
+// newAccessLog creates the access log from the log.access_log config
+func newAccessLog(cfg *config.Config, log *logger.Logger) *middleware.AccessLog {
+	accessLog := cfg.Log.AccessLog
+	return middleware.NewAccessLog(logger.Named(log, accessLoggerName), middleware.AccessLogOptions{
+		RequestBody:  accessLog.RequestBody,
+		ResponseBody: accessLog.ResponseBody,
+		MaxBodyBytes: accessLog.MaxBodyBytes,
+		TrustProxy:   accessLog.TrustProxy,
+		SkipPaths:    accessLog.SkipPaths,
+	})
 }
 
-// Start initializes and starts the HTTP server
+// Use appends global middlewares, the first registered is the outermost
+func (r *Router) Use(mws ...Middleware) {
+	r.middlewares = append(r.middlewares, mws...)
+}
+
+// Group creates a route group with the given prefix and middlewares
+func (r *Router) Group(prefix string, mws ...Middleware) *Group {
+	return NewGroup(r.Router, prefix, mws...)
+}
+
+// Handler returns the router wrapped with the global middlewares
+func (r *Router) Handler() http.Handler {
+	return Chain(r.middlewares...)(r.Router)
+}
+
+// Start initializes and starts the HTTP server, it returns http.ErrServerClosed after Shutdown
 func (r *Router) Start() error {
 	addr := fmt.Sprintf(":%d", r.Cfg.App.Port)
-	r.server = &http.Server{
+
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return http.ErrServerClosed
+	}
+	server := &http.Server{
 		Addr:    addr,
-		Handler: r.Router,
+		Handler: r.Handler(),
 	}
+	r.server = server
+	r.mu.Unlock()
 
 	r.appLogger.Info(fmt.Sprintf("Running on %s", addr))
-	return r.server.ListenAndServe()
+	return server.ListenAndServe()
 }
 
-// Shutdown gracefully stops the HTTP server
+// Shutdown gracefully stops the HTTP server, readiness fails as soon as it starts
+// and requests are still served during the health.shutdown_delay config
 func (r *Router) Shutdown(ctx context.Context) error {
+	r.health.SetShuttingDown()
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.closed = true
 	if r.server == nil {
-		return nil // server was not started
+		return nil // server was not started, a later Start returns http.ErrServerClosed
+	}
+
+	if delay := r.Cfg.Health.ShutdownDelay.Std(); delay > 0 {
+		r.appLogger.Info("Draining server", logger.StringAttr("delay", delay.String()))
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
 	}
 
 	r.appLogger.Info("Shutting down server")
@@ -1,14 +1,20 @@
 package router
 
 import (
+	"bytes"
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/julienschmidt/httprouter"
 	"github.com/raflynagachi/go-rest-api-starter/config"
 	"github.com/raflynagachi/go-rest-api-starter/internal/handler/definition/mocks"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/health"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/middleware"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -19,12 +25,13 @@ var (
 		App: config.App{
 			Port: 8080,
 		},
+		JwtKey: "secret",
 	}
 	mockLogger = logger.NewLogger()
 )
 
 func TestNewRouter(t *testing.T) {
-	router := New(mockCfg, mockLogger, mockHandler)
+	router := New(mockCfg, mockLogger, mockHandler, health.NewRegistry(0, 0), metrics.NewRegistry())
 
 	assert.NotNil(t, router)
 	assert.Equal(t, mockCfg, router.Cfg)
@@ -32,7 +39,7 @@ func TestNewRouter(t *testing.T) {
 }
 
 func TestRouter_ServeHTTP(t *testing.T) {
-	r := New(mockCfg, mockLogger, mockHandler)
+	r := New(mockCfg, mockLogger, mockHandler, health.NewRegistry(0, 0), metrics.NewRegistry())
 
 	go func() {
 		r.ServeHTTP()
@@ -47,13 +54,15 @@ func TestRouter_ServeHTTP(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
+	assert.NotEmpty(t, resp.Header.Get(middleware.HeaderRequestID))
+
 	// test shutdown
 	err = r.Shutdown(context.Background())
 	require.NoError(t, err)
 }
 
 func TestRouter_Shutdown(t *testing.T) {
-	r := New(mockCfg, mockLogger, mockHandler)
+	r := New(mockCfg, mockLogger, mockHandler, health.NewRegistry(0, 0), metrics.NewRegistry())
 
 	err := r.Shutdown(context.Background())
 	require.NoError(t, err)
@@ -62,3 +71,105 @@ func TestRouter_Shutdown(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, resp)
 }
+
+func TestRouter_ShutdownBeforeStart(t *testing.T) {
+	cfg := *mockCfg
+	cfg.App.Port = 8082
+	r := New(&cfg, mockLogger, mockHandler, health.NewRegistry(0, 0), metrics.NewRegistry())
+
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	// a server started after shutdown does not serve
+	assert.ErrorIs(t, r.Start(), http.ErrServerClosed)
+	assert.NoError(t, r.ServeHTTP())
+	_, err := http.Get("http://localhost:8082/ping")
+	assert.Error(t, err)
+}
+
+func TestRouter_StartShutdownRace(t *testing.T) {
+	cfg := *mockCfg
+	cfg.App.Port = 8083
+	r := New(&cfg, mockLogger, mockHandler, health.NewRegistry(0, 0), metrics.NewRegistry())
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- r.ServeHTTP()
+	}()
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	// whichever runs first, the server stops
+	select {
+	case err := <-serveErr:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("ServeHTTP() did not return after Shutdown")
+	}
+}
+
+func TestRouter_ShutdownDelay(t *testing.T) {
+	cfg := *mockCfg
+	cfg.App.Port = 8081
+	cfg.Health.ShutdownDelay = config.Duration(300 * time.Millisecond)
+	r := New(&cfg, mockLogger, mockHandler, health.NewRegistry(0, 0), metrics.NewRegistry())
+
+	go func() {
+		r.ServeHTTP()
+	}()
+
+	// allow the server to start
+	time.Sleep(100 * time.Millisecond)
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- r.Shutdown(context.Background())
+	}()
+
+	// the server still answers during the delay, with a failing readiness
+	time.Sleep(100 * time.Millisecond)
+	resp, err := http.Get("http://localhost:8081/readyz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	require.NoError(t, <-shutdownErr)
+	_, err = http.Get("http://localhost:8081/readyz")
+	assert.Error(t, err)
+}
+
+func TestRouter_Metrics(t *testing.T) {
+	r := New(mockCfg, mockLogger, mockHandler, health.NewRegistry(0, 0), metrics.NewRegistry())
+
+	r.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", http.NoBody))
+	r.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", http.NoBody))
+
+	recorder := httptest.NewRecorder()
+	r.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, metrics.ContentType, recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), `http_requests_total{method="GET",route="/ping",status="200"} 1`)
+	assert.Contains(t, recorder.Body.String(), `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+}
+
+func TestRouter_RecoversPanic(t *testing.T) {
+	var logs bytes.Buffer
+	metricsRegistry := metrics.NewRegistry()
+	r := New(mockCfg, logger.NewLogger(logger.WithSinks(logger.Sink{Writer: &logs})), mockHandler, health.NewRegistry(0, 0), metricsRegistry)
+	r.Group("/debug").GET("/panic", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		panic("boom")
+	})
+
+	recorder := httptest.NewRecorder()
+	r.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/panic", http.NoBody))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.NotEmpty(t, recorder.Header().Get(middleware.HeaderRequestID))
+	assert.Contains(t, logs.String(), "panic recovered")
+	assert.Regexp(t, `\[ERROR\] http request .*"status":500`, logs.String())
+
+	recorder = httptest.NewRecorder()
+	r.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	assert.Contains(t, recorder.Body.String(), `http_panics_recovered_total{method="GET",route="/debug/panic"} 1`)
+	assert.Contains(t, recorder.Body.String(), `http_requests_total{method="GET",route="/debug/panic",status="500"} 1`)
+}
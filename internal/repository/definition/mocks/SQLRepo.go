@@ -47,6 +47,24 @@ func (_m *SQLRepo) CountUser(ctx context.Context, filter request.UserFilter) (in
 	return r0, r1
 }
 
+// DeleteUser provides a mock function with given fields: ctx, tx, user
+func (_m *SQLRepo) DeleteUser(ctx context.Context, tx *sqlx.Tx, user *model.User) error {
+	ret := _m.Called(ctx, tx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, *model.User) error); ok {
+		r0 = rf(ctx, tx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetUser provides a mock function with given fields: ctx, filter
 func (_m *SQLRepo) GetUser(ctx context.Context, filter request.UserFilter) ([]*model.User, error) {
 	ret := _m.Called(ctx, filter)
@@ -135,9 +153,27 @@ func (_m *SQLRepo) InsertUser(ctx context.Context, tx *sqlx.Tx, user *model.User
 	return r0, r1
 }
 
-// TxBegin provides a mock function with given fields:
-func (_m *SQLRepo) TxBegin() (*sqlx.Tx, error) {
-	ret := _m.Called()
+// RestoreUser provides a mock function with given fields: ctx, tx, user
+func (_m *SQLRepo) RestoreUser(ctx context.Context, tx *sqlx.Tx, user *model.User) error {
+	ret := _m.Called(ctx, tx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, *model.User) error); ok {
+		r0 = rf(ctx, tx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TxBegin provides a mock function with given fields: ctx
+func (_m *SQLRepo) TxBegin(ctx context.Context) (*sqlx.Tx, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for TxBegin")
@@ -145,19 +181,19 @@ func (_m *SQLRepo) TxBegin() (*sqlx.Tx, error) {
 
 	var r0 *sqlx.Tx
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (*sqlx.Tx, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) (*sqlx.Tx, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() *sqlx.Tx); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) *sqlx.Tx); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*sqlx.Tx)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	sqlx "github.com/jmoiron/sqlx"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,9 +14,9 @@ type Transaction struct {
 	mock.Mock
 }
 
-// TxBegin provides a mock function with given fields:
-func (_m *Transaction) TxBegin() (*sqlx.Tx, error) {
-	ret := _m.Called()
+// TxBegin provides a mock function with given fields: ctx
+func (_m *Transaction) TxBegin(ctx context.Context) (*sqlx.Tx, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for TxBegin")
@@ -22,19 +24,19 @@ func (_m *Transaction) TxBegin() (*sqlx.Tx, error) {
 
 	var r0 *sqlx.Tx
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (*sqlx.Tx, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) (*sqlx.Tx, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() *sqlx.Tx); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) *sqlx.Tx); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*sqlx.Tx)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2,17 +2,57 @@ package postgres
 
 import (
 	"strings"
+	"time"
 
 	req "github.com/raflynagachi/go-rest-api-starter/internal/dto/web/request"
 )
 
 func filterUser(filter req.UserFilter) (string, []interface{}) {
+	values, args := userConditions(filter)
+	return whereClause(values), args
+}
+
+// userSortColumns maps the sort fields to columns, so no other input reaches ORDER BY
+var userSortColumns = map[string]string{
+	"id":         "id",
+	"email":      "email",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// likeEscaper escapes the LIKE wildcards so they match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// userConditions returns the conditions of the user filter without the keyset
+func userConditions(filter req.UserFilter) ([]string, []interface{}) {
 	values := []string{}
 	args := []interface{}{}
 
 	if filter.Email != "" {
-		values = append(values, `email LIKE '%'||?||'%'`)
-		args = append(args, filter.Email)
+		switch filter.EmailMatch {
+		case req.EmailMatchExact:
+			values = append(values, "email = ?")
+			args = append(args, filter.Email)
+		case req.EmailMatchPrefix:
+			values = append(values, "email LIKE ?||'%'")
+			args = append(args, likeEscaper.Replace(filter.Email))
+		default:
+			values = append(values, `email LIKE '%'||?||'%'`)
+			args = append(args, likeEscaper.Replace(filter.Email))
+		}
+	}
+
+	if len(filter.IDs) > 0 {
+		placeholders := strings.Repeat("?, ", len(filter.IDs))
+		values = append(values, "id IN ("+placeholders[:len(placeholders)-2]+")")
+		for _, id := range filter.IDs {
+			args = append(args, id)
+		}
+	}
+
+	if filter.CreatedBy != "" {
+		values = append(values, "created_by = ?")
+		args = append(args, filter.CreatedBy)
 	}
 
 	if !filter.CreatedAt.IsZero() {
@@ -20,9 +60,70 @@ func filterUser(filter req.UserFilter) (string, []interface{}) {
 		args = append(args, filter.CreatedAt)
 	}
 
-	var whereClause string
-	if len(values) > 0 {
-		whereClause = " WHERE " + strings.Join(values, " AND ")
+	values, args = timeRange(values, args, "created_at", filter.CreatedAtGte, filter.CreatedAtGt, filter.CreatedAtLte, filter.CreatedAtLt)
+	values, args = timeRange(values, args, "updated_at", filter.UpdatedAtGte, filter.UpdatedAtGt, filter.UpdatedAtLte, filter.UpdatedAtLt)
+
+	if !filter.IncludeDeleted {
+		values = append(values, "deleted_at IS NULL")
+	}
+
+	return values, args
+}
+
+// timeRange appends the conditions of the non zero bounds on column
+func timeRange(values []string, args []interface{}, column string, gte, gt, lte, lt time.Time) ([]string, []interface{}) {
+	bounds := []struct {
+		op    string
+		value time.Time
+	}{{">=", gte}, {">", gt}, {"<=", lte}, {"<", lt}}
+
+	for _, bound := range bounds {
+		if bound.value.IsZero() {
+			continue
+		}
+		values = append(values, column+" "+bound.op+" ?")
+		args = append(args, bound.value)
+	}
+	return values, args
+}
+
+// orderUser returns the ORDER BY of the sorts, id breaks ties so pages are stable
+func orderUser(sorts []req.SortField) string {
+	if len(sorts) == 0 {
+		return " ORDER BY created_at, id"
+	}
+
+	terms := make([]string, 0, len(sorts)+1)
+	hasID := false
+	for _, sort := range sorts {
+		column, ok := userSortColumns[sort.Field]
+		if !ok {
+			continue
+		}
+		if sort.Desc {
+			column += " DESC"
+		}
+		terms = append(terms, column)
+		hasID = hasID || sort.Field == "id"
+	}
+	if !hasID {
+		terms = append(terms, "id")
+	}
+	return " ORDER BY " + strings.Join(terms, ", ")
+}
+
+// keysetUser returns the condition and order placing rows after the keyset, or before it when backward
+func keysetUser(keyset req.Keyset) (condition string, args []interface{}, order string) {
+	args = []interface{}{keyset.CreatedAt, keyset.ID}
+	if keyset.Backward {
+		return "(created_at, id) < (?, ?)", args, " ORDER BY created_at DESC, id DESC"
+	}
+	return "(created_at, id) > (?, ?)", args, " ORDER BY created_at, id"
+}
+
+func whereClause(values []string) string {
+	if len(values) == 0 {
+		return ""
 	}
-	return whereClause, args
+	return " WHERE " + strings.Join(values, " AND ")
 }
@@ -24,12 +24,18 @@ func TestFilterUser(t *testing.T) {
 			filter: req.UserFilter{
 				Email: mockEmail,
 			},
-			wantClause: " WHERE email LIKE '%'||?||'%'",
+			wantClause: " WHERE email LIKE '%'||?||'%' AND deleted_at IS NULL",
 			wantArgs:   []interface{}{mockEmail},
 		},
 		{
 			name:       "success without filter",
 			filter:     req.UserFilter{},
+			wantClause: " WHERE deleted_at IS NULL",
+			wantArgs:   nil,
+		},
+		{
+			name:       "success include deleted",
+			filter:     req.UserFilter{IncludeDeleted: true},
 			wantClause: "",
 			wantArgs:   nil,
 		},
@@ -39,9 +45,44 @@ func TestFilterUser(t *testing.T) {
 				Email:     mockEmail,
 				CreatedAt: mockTime,
 			},
-			wantClause: " WHERE email LIKE '%'||?||'%' AND created_at >= ?",
+			wantClause: " WHERE email LIKE '%'||?||'%' AND created_at >= ? AND deleted_at IS NULL",
 			wantArgs:   []interface{}{mockEmail, mockTime},
 		},
+		{
+			name:       "success exact email",
+			filter:     req.UserFilter{Email: mockEmail, EmailMatch: req.EmailMatchExact},
+			wantClause: " WHERE email = ? AND deleted_at IS NULL",
+			wantArgs:   []interface{}{mockEmail},
+		},
+		{
+			name:       "success prefix email escapes wildcards",
+			filter:     req.UserFilter{Email: `a_b%c\`, EmailMatch: req.EmailMatchPrefix},
+			wantClause: " WHERE email LIKE ?||'%' AND deleted_at IS NULL",
+			wantArgs:   []interface{}{`a\_b\%c\\`},
+		},
+		{
+			name:       "success injection stays an argument",
+			filter:     req.UserFilter{Email: "'; DROP TABLE users; --", CreatedBy: "' OR '1'='1"},
+			wantClause: ` WHERE email LIKE '%'||?||'%' AND created_by = ? AND deleted_at IS NULL`,
+			wantArgs:   []interface{}{"'; DROP TABLE users; --", "' OR '1'='1"},
+		},
+		{
+			name:       "success id in",
+			filter:     req.UserFilter{IDs: []int64{1, 2, 3}, IncludeDeleted: true},
+			wantClause: " WHERE id IN (?, ?, ?)",
+			wantArgs:   []interface{}{int64(1), int64(2), int64(3)},
+		},
+		{
+			name: "success time ranges",
+			filter: req.UserFilter{
+				CreatedAtGt:  mockTime,
+				CreatedAtLte: mockTime.Add(time.Hour),
+				UpdatedAtGte: mockTime,
+				UpdatedAtLt:  mockTime.Add(time.Minute),
+			},
+			wantClause: " WHERE created_at > ? AND created_at <= ? AND updated_at >= ? AND updated_at < ? AND deleted_at IS NULL",
+			wantArgs:   []interface{}{mockTime, mockTime.Add(time.Hour), mockTime, mockTime.Add(time.Minute)},
+		},
 	}
 
 	for _, tt := range tests {
@@ -52,3 +93,37 @@ func TestFilterUser(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderUser(t *testing.T) {
+	tests := []struct {
+		name  string
+		sorts []req.SortField
+		want  string
+	}{
+		{
+			name: "success default order",
+			want: " ORDER BY created_at, id",
+		},
+		{
+			name:  "success sort with id tie breaker",
+			sorts: []req.SortField{{Field: "created_at", Desc: true}, {Field: "email"}},
+			want:  " ORDER BY created_at DESC, email, id",
+		},
+		{
+			name:  "success sort by id",
+			sorts: []req.SortField{{Field: "id", Desc: true}},
+			want:  " ORDER BY id DESC",
+		},
+		{
+			name:  "success unknown field is dropped",
+			sorts: []req.SortField{{Field: "email; DROP TABLE users"}, {Field: "updated_at"}},
+			want:  " ORDER BY updated_at, id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, orderUser(tt.sorts))
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"sync"
+
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	repo "github.com/raflynagachi/go-rest-api-starter/internal/repository/definition"
@@ -11,6 +13,7 @@ import (
 type PostgresRepo struct {
 	DB        *sqlx.DB
 	appLogger *logger.Logger
+	txSpans   sync.Map // *sqlx.Tx to the *tracing.Span ended by TxEnd
 }
 
 func New(db *sqlx.DB, log *logger.Logger) repo.SQLRepo {
@@ -22,4 +25,5 @@ func New(db *sqlx.DB, log *logger.Logger) repo.SQLRepo {
 
 var (
 	generatePagination = database.QueryPagination
+	generateLimit      = database.QueryLimit
 )
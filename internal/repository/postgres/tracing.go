@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
+)
+
+// startSpan starts a client span for a database call, the statement is added once the query is built
+func startSpan(ctx context.Context, name string) (context.Context, *tracing.Span) {
+	return tracing.Start(ctx, name,
+		tracing.WithSpanKind(tracing.SpanKindClient),
+		tracing.WithAttributes(tracing.String("db.system", "postgresql")),
+	)
+}
+
+// statementAttr records the query with its indentation collapsed, arguments are never recorded
+func statementAttr(query string) tracing.Attribute {
+	return tracing.String("db.statement", strings.Join(strings.Fields(query), " "))
+}
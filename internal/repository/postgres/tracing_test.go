@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/raflynagachi/go-rest-api-starter/internal/util/testutil"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing/tracetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresRepo_Spans(t *testing.T) {
+	recorder := tracetest.NewRecorder()
+	tracer := tracing.NewTracer("test-service", recorder, nil)
+	r := &PostgresRepo{DB: sqlxDB}
+
+	ctx, parent := tracer.Start(context.Background(), "APIUsecase.DeleteUser")
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery(regexp.QuoteMeta("SELECT id, email")).WillReturnError(testutil.MockErr)
+	mockSql.ExpectRollback()
+
+	tx, err := r.TxBegin(ctx)
+	require.NoError(t, err)
+	_, err = r.GetUserByID(ctx, 1)
+	require.Error(t, err)
+	assert.Error(t, r.TxEnd(tx, err))
+	parent.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	spans := recorder.Spans()
+	require.Len(t, spans, 3)
+	query, txSpan := spans[0], spans[1]
+
+	assert.Equal(t, "PostgresRepo.GetUserByID", query.Name)
+	assert.Equal(t, tracing.SpanKindClient, query.Kind)
+	assert.Equal(t, parent.SpanContext().SpanID, query.ParentSpanID)
+	assert.Equal(t, tracing.StatusError, query.Status)
+	assert.Contains(t, query.Attributes, tracing.String("db.system", "postgresql"))
+	assert.Contains(t, query.Attributes, tracing.String("db.statement",
+		"SELECT id, email, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by FROM users WHERE id = ?"))
+
+	assert.Equal(t, "PostgresRepo.Tx", txSpan.Name)
+	assert.Equal(t, parent.SpanContext().SpanID, txSpan.ParentSpanID)
+	assert.Equal(t, tracing.StatusUnset, txSpan.Status, "a successful rollback is not a span error")
+	assert.Contains(t, txSpan.Attributes, tracing.String("db.transaction.result", txRollback))
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
@@ -1,32 +1,79 @@
 package postgres
 
 import (
+	"context"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	"github.com/raflynagachi/go-rest-api-starter/internal/apperror"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/database"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
+)
+
+const (
+	txCommit   = "commit"
+	txRollback = "rollback"
+
+	txStatusOK    = "ok"
+	txStatusError = "error"
 )
 
-func (r *PostgresRepo) TxBegin() (*sqlx.Tx, error) {
+var (
+	txTotal = metrics.NewCounterVec("db_transactions_total", "Total number of ended database transactions.", "result", "status")
+)
+
+func init() {
+	metrics.Default.MustRegister(txTotal)
+}
+
+// TxBegin starts a transaction, its span lasts until TxEnd
+func (r *PostgresRepo) TxBegin(ctx context.Context) (*sqlx.Tx, error) {
+	_, span := startSpan(ctx, "PostgresRepo.Tx")
+
 	tx, err := database.TxBegin(r.DB)
 	if err != nil {
+		span.RecordError(err)
+		span.End()
 		return nil, errors.Wrap(err, "PostgresRepo.TxBegin.TxBegin")
 	}
+
+	r.txSpans.Store(tx, span)
 	return tx, nil
 }
 
 func (r *PostgresRepo) TxEnd(tx *sqlx.Tx, err error) error {
 	if err != nil {
 		if rbErr := database.TxRollback(tx); rbErr != nil {
+			r.recordTxEnd(tx, txRollback, rbErr)
 			return errors.Wrap(err, "PostgresRepo.TxEnd.TxRollback")
 		}
+		r.recordTxEnd(tx, txRollback, nil)
 		return apperror.ErrTxDone
 	}
 
 	err = database.TxCommit(tx)
 	if err != nil {
+		r.recordTxEnd(tx, txCommit, err)
 		return errors.Wrap(err, "PostgresRepo.TxEnd.TxCommit")
 	}
+	r.recordTxEnd(tx, txCommit, nil)
 
 	return nil
 }
+
+// recordTxEnd counts the ended transaction and ends the span started by TxBegin
+func (r *PostgresRepo) recordTxEnd(tx *sqlx.Tx, result string, err error) {
+	status := txStatusOK
+	if err != nil {
+		status = txStatusError
+	}
+	txTotal.WithLabelValues(result, status).Inc()
+
+	if val, ok := r.txSpans.LoadAndDelete(tx); ok {
+		span := val.(*tracing.Span)
+		span.SetAttributes(tracing.String("db.transaction.result", result))
+		span.RecordError(err)
+		span.End()
+	}
+}
@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"context"
+
 	"testing"
 
 	"github.com/jmoiron/sqlx"
@@ -49,7 +51,7 @@ func TestPostgresRepo_TxBegin(t *testing.T) {
 
 			tt.setup()
 
-			got, err := r.TxBegin()
+			got, err := r.TxBegin(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PostgresRepo.TxBegin() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -69,11 +71,13 @@ func TestPostgresRepo_TxEnd(t *testing.T) {
 		err error
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		setup   func()
-		wantErr bool
+		name       string
+		fields     fields
+		args       args
+		setup      func()
+		wantErr    bool
+		wantResult string
+		wantStatus string
 	}{
 		{
 			name: "success commit",
@@ -86,7 +90,9 @@ func TestPostgresRepo_TxEnd(t *testing.T) {
 			setup: func() {
 				mockSql.ExpectCommit()
 			},
-			wantErr: false,
+			wantErr:    false,
+			wantResult: txCommit,
+			wantStatus: txStatusOK,
 		},
 		{
 			name: "failed due to commit error",
@@ -99,7 +105,9 @@ func TestPostgresRepo_TxEnd(t *testing.T) {
 			setup: func() {
 				mockSql.ExpectCommit().WillReturnError(testutil.MockErr)
 			},
-			wantErr: true,
+			wantErr:    true,
+			wantResult: txCommit,
+			wantStatus: txStatusError,
 		},
 		{
 			name: "failed due to transaction rollback",
@@ -112,7 +120,9 @@ func TestPostgresRepo_TxEnd(t *testing.T) {
 			setup: func() {
 				mockSql.ExpectRollback()
 			},
-			wantErr: true,
+			wantErr:    true,
+			wantResult: txRollback,
+			wantStatus: txStatusOK,
 		},
 		{
 			name: "failed due to transaction rollback error",
@@ -125,7 +135,9 @@ func TestPostgresRepo_TxEnd(t *testing.T) {
 			setup: func() {
 				mockSql.ExpectRollback().WillReturnError(testutil.MockErr)
 			},
-			wantErr: true,
+			wantErr:    true,
+			wantResult: txRollback,
+			wantStatus: txStatusError,
 		},
 	}
 	for _, tt := range tests {
@@ -141,10 +153,15 @@ func TestPostgresRepo_TxEnd(t *testing.T) {
 			}
 
 			tt.setup()
+			counter := txTotal.WithLabelValues(tt.wantResult, tt.wantStatus)
+			before := counter.Value()
 
 			if err := r.TxEnd(mockTx, tt.args.err); (err != nil) != tt.wantErr {
 				t.Errorf("PostgresRepo.TxEnd() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if got := counter.Value() - before; got != 1 {
+				t.Errorf("PostgresRepo.TxEnd() incremented db_transactions_total{result=%q,status=%q} by %v, want 1", tt.wantResult, tt.wantStatus, got)
+			}
 		})
 	}
 }
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"slices"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
@@ -11,23 +12,43 @@ import (
 	req "github.com/raflynagachi/go-rest-api-starter/internal/dto/web/request"
 	"github.com/raflynagachi/go-rest-api-starter/internal/model"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/database"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
 )
 
-func (r *PostgresRepo) GetUser(ctx context.Context, filter req.UserFilter) ([]*model.User, error) {
+func (r *PostgresRepo) GetUser(ctx context.Context, filter req.UserFilter) (_ []*model.User, err error) {
+	ctx, span := startSpan(ctx, "PostgresRepo.GetUser")
+	defer tracing.End(span, &err)
+
 	query := `
 		SELECT
 			id, email, created_at, created_by,
-			updated_at, updated_by
+			updated_at, updated_by, deleted_at, deleted_by
 		FROM users
 	`
 
-	whereClause, args := filterUser(filter)
-	pagination, err := generatePagination(filter.Page, filter.Limit)
-	if err != nil {
-		return nil, errors.Wrap(err, "PostgresRepo.GetUser.generatePagination")
+	conditions, args := userConditions(filter)
+	order := orderUser(filter.Sorts)
+
+	var pagination string
+	if filter.Keyset != nil {
+		condition, keysetArgs, keysetOrder := keysetUser(*filter.Keyset)
+		conditions = append(conditions, condition)
+		args = append(args, keysetArgs...)
+		order = keysetOrder
+
+		pagination, err = generateLimit(filter.Limit)
+		if err != nil {
+			return nil, errors.Wrap(err, "PostgresRepo.GetUser.generateLimit")
+		}
+	} else {
+		pagination, err = generatePagination(filter.Page, filter.Limit)
+		if err != nil {
+			return nil, errors.Wrap(err, "PostgresRepo.GetUser.generatePagination")
+		}
 	}
 
-	query = r.DB.Rebind(query + whereClause + pagination)
+	query = r.DB.Rebind(query + whereClause(conditions) + order + " " + pagination)
+	span.SetAttributes(statementAttr(query))
 
 	users := make([]*model.User, 0)
 	err = r.DB.SelectContext(ctx, &users, query, args...)
@@ -35,10 +56,18 @@ func (r *PostgresRepo) GetUser(ctx context.Context, filter req.UserFilter) ([]*m
 		return nil, errors.Wrap(err, "PostgresRepo.GetUser.SelectContext")
 	}
 
+	// a backward page is read from the keyset outwards, it is returned in the usual order
+	if filter.Keyset != nil && filter.Keyset.Backward {
+		slices.Reverse(users)
+	}
+
 	return users, nil
 }
 
-func (r *PostgresRepo) CountUser(ctx context.Context, filter req.UserFilter) (int64, error) {
+func (r *PostgresRepo) CountUser(ctx context.Context, filter req.UserFilter) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "PostgresRepo.CountUser")
+	defer tracing.End(span, &err)
+
 	query := `
 		SELECT COUNT(id)
 		FROM users
@@ -46,9 +75,10 @@ func (r *PostgresRepo) CountUser(ctx context.Context, filter req.UserFilter) (in
 
 	whereClause, args := filterUser(filter)
 	query = r.DB.Rebind(query + whereClause)
+	span.SetAttributes(statementAttr(query))
 
 	var totalData int64
-	err := r.DB.GetContext(ctx, &totalData, query, args...)
+	err = r.DB.GetContext(ctx, &totalData, query, args...)
 	if err != nil {
 		return 0, errors.Wrap(err, "PostgresRepo.GetUserByID.GetContext")
 	}
@@ -56,7 +86,10 @@ func (r *PostgresRepo) CountUser(ctx context.Context, filter req.UserFilter) (in
 	return totalData, nil
 }
 
-func (r *PostgresRepo) GetUserByID(ctx context.Context, id int64) (*model.User, error) {
+func (r *PostgresRepo) GetUserByID(ctx context.Context, id int64) (_ *model.User, err error) {
+	ctx, span := startSpan(ctx, "PostgresRepo.GetUserByID")
+	defer tracing.End(span, &err)
+
 	query := `
 		SELECT
 			id, email, created_at, created_by,
@@ -66,9 +99,10 @@ func (r *PostgresRepo) GetUserByID(ctx context.Context, id int64) (*model.User,
 	`
 
 	query = r.DB.Rebind(query)
+	span.SetAttributes(statementAttr(query))
 
 	user := &model.User{}
-	err := r.DB.GetContext(ctx, user, query, id)
+	err = r.DB.GetContext(ctx, user, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.Wrap(apperror.ErrNotFound, "PostgresRepo.GetUserByID.GetContext")
@@ -79,7 +113,10 @@ func (r *PostgresRepo) GetUserByID(ctx context.Context, id int64) (*model.User,
 	return user, nil
 }
 
-func (r *PostgresRepo) InsertUser(ctx context.Context, tx *sqlx.Tx, user *model.User) (int64, error) {
+func (r *PostgresRepo) InsertUser(ctx context.Context, tx *sqlx.Tx, user *model.User) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "PostgresRepo.InsertUser")
+	defer tracing.End(span, &err)
+
 	query := `
 		INSERT INTO users (email, created_at, created_by)
 		VALUES (?, ?, ?)
@@ -87,9 +124,10 @@ func (r *PostgresRepo) InsertUser(ctx context.Context, tx *sqlx.Tx, user *model.
 	`
 
 	query = r.DB.Rebind(query)
+	span.SetAttributes(statementAttr(query))
 
 	var lastID int64
-	err := tx.GetContext(ctx, &lastID, query, user.Email, user.Created.CreatedAt, user.Created.CreatedBy)
+	err = tx.GetContext(ctx, &lastID, query, user.Email, user.Created.CreatedAt, user.Created.CreatedBy)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			if pqErr.Code == database.ERR_PQ_CODE_DUPLICATE {
@@ -102,7 +140,10 @@ func (r *PostgresRepo) InsertUser(ctx context.Context, tx *sqlx.Tx, user *model.
 	return lastID, nil
 }
 
-func (r *PostgresRepo) UpdateUser(ctx context.Context, tx *sqlx.Tx, user *model.User) error {
+func (r *PostgresRepo) UpdateUser(ctx context.Context, tx *sqlx.Tx, user *model.User) (err error) {
+	ctx, span := startSpan(ctx, "PostgresRepo.UpdateUser")
+	defer tracing.End(span, &err)
+
 	query := `
 		UPDATE users SET
 			email= COALESCE(:email, email),
@@ -111,7 +152,8 @@ func (r *PostgresRepo) UpdateUser(ctx context.Context, tx *sqlx.Tx, user *model.
 		WHERE id = :id
 	`
 
-	_, err := r.DB.NamedExecContext(ctx, query, user)
+	span.SetAttributes(statementAttr(query))
+	_, err = tx.NamedExecContext(ctx, query, user)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			if pqErr.Code == database.ERR_PQ_CODE_DUPLICATE {
@@ -123,3 +165,61 @@ func (r *PostgresRepo) UpdateUser(ctx context.Context, tx *sqlx.Tx, user *model.
 
 	return nil
 }
+
+func (r *PostgresRepo) DeleteUser(ctx context.Context, tx *sqlx.Tx, user *model.User) (err error) {
+	ctx, span := startSpan(ctx, "PostgresRepo.DeleteUser")
+	defer tracing.End(span, &err)
+
+	query := `
+		UPDATE users SET
+			deleted_at = :deleted_at,
+			deleted_by = :deleted_by
+		WHERE id = :id AND deleted_at IS NULL
+	`
+
+	span.SetAttributes(statementAttr(query))
+	result, err := tx.NamedExecContext(ctx, query, user)
+	if err != nil {
+		return errors.Wrap(err, "PostgresRepo.DeleteUser.NamedExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "PostgresRepo.DeleteUser.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(apperror.ErrNotFound, "PostgresRepo.DeleteUser.RowsAffected")
+	}
+
+	return nil
+}
+
+func (r *PostgresRepo) RestoreUser(ctx context.Context, tx *sqlx.Tx, user *model.User) (err error) {
+	ctx, span := startSpan(ctx, "PostgresRepo.RestoreUser")
+	defer tracing.End(span, &err)
+
+	query := `
+		UPDATE users SET
+			deleted_at = NULL,
+			deleted_by = NULL,
+			updated_at = :updated_at,
+			updated_by = :updated_by
+		WHERE id = :id AND deleted_at IS NOT NULL
+	`
+
+	span.SetAttributes(statementAttr(query))
+	result, err := tx.NamedExecContext(ctx, query, user)
+	if err != nil {
+		return errors.Wrap(err, "PostgresRepo.RestoreUser.NamedExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "PostgresRepo.RestoreUser.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(apperror.ErrNotFound, "PostgresRepo.RestoreUser.RowsAffected")
+	}
+
+	return nil
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"reflect"
+	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/guregu/null/v5"
 	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/raflynagachi/go-rest-api-starter/internal/apperror"
 	req "github.com/raflynagachi/go-rest-api-starter/internal/dto/web/request"
 	"github.com/raflynagachi/go-rest-api-starter/internal/model"
 	randomutil "github.com/raflynagachi/go-rest-api-starter/internal/util/random"
@@ -51,12 +55,60 @@ func TestPostgresRepo_GetUser(t *testing.T) {
 				mockSql.ExpectQuery("SELECT").
 					WithArgs(mockUser.Email).
 					WillReturnRows(
-						mockSql.NewRows([]string{"id", "email", "created_at", "created_by", "updated_at", "updated_by"}).
-							AddRow(mockUser.ID, mockUser.Email, mockUser.CreatedAt, mockUser.CreatedBy, mockUser.UpdatedAt, mockUser.UpdatedBy))
+						mockSql.NewRows([]string{"id", "email", "created_at", "created_by", "updated_at", "updated_by", "deleted_at", "deleted_by"}).
+							AddRow(mockUser.ID, mockUser.Email, mockUser.CreatedAt, mockUser.CreatedBy, mockUser.UpdatedAt, mockUser.UpdatedBy, mockUser.DeletedAt, mockUser.DeletedBy))
 			},
 			want:    mockUsers,
 			wantErr: false,
 		},
+		{
+			name:   "success get user after keyset",
+			fields: fields{DB: sqlxDB},
+			args: args{ctx: context.Background(), filter: req.UserFilter{
+				Pagination: req.Pagination{Limit: 2},
+				Keyset:     &req.Keyset{CreatedAt: mockUser.CreatedAt, ID: mockUser.ID},
+			}},
+			setup: func() {
+				mockSql.ExpectQuery(regexp.QuoteMeta("WHERE deleted_at IS NULL AND (created_at, id) > (?, ?) ORDER BY created_at, id LIMIT 2")).
+					WithArgs(mockUser.CreatedAt, mockUser.ID).
+					WillReturnRows(
+						mockSql.NewRows([]string{"id", "email", "created_at", "created_by", "updated_at", "updated_by", "deleted_at", "deleted_by"}).
+							AddRow(mockUser.ID, mockUser.Email, mockUser.CreatedAt, mockUser.CreatedBy, mockUser.UpdatedAt, mockUser.UpdatedBy, mockUser.DeletedAt, mockUser.DeletedBy))
+			},
+			want:    mockUsers,
+			wantErr: false,
+		},
+		{
+			name:   "success get user before keyset in ascending order",
+			fields: fields{DB: sqlxDB},
+			args: args{ctx: context.Background(), filter: req.UserFilter{
+				Pagination: req.Pagination{Limit: 2},
+				Keyset:     &req.Keyset{CreatedAt: mockUser.CreatedAt, ID: mockUser.ID + 2, Backward: true},
+			}},
+			setup: func() {
+				mockSql.ExpectQuery(regexp.QuoteMeta("WHERE deleted_at IS NULL AND (created_at, id) < (?, ?) ORDER BY created_at DESC, id DESC LIMIT 2")).
+					WithArgs(mockUser.CreatedAt, mockUser.ID+2).
+					WillReturnRows(
+						mockSql.NewRows([]string{"id", "email", "created_at", "created_by"}).
+							AddRow(mockUser.ID+1, mockUser.Email, mockUser.CreatedAt, mockUser.CreatedBy).
+							AddRow(mockUser.ID, mockUser.Email, mockUser.CreatedAt, mockUser.CreatedBy))
+			},
+			want: []*model.User{
+				{ID: mockUser.ID, Email: mockUser.Email, Created: mockUser.Created},
+				{ID: mockUser.ID + 1, Email: mockUser.Email, Created: mockUser.Created},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "failed due to invalid keyset limit",
+			fields: fields{DB: sqlxDB},
+			args: args{ctx: context.Background(), filter: req.UserFilter{
+				Keyset: &req.Keyset{CreatedAt: mockUser.CreatedAt, ID: mockUser.ID},
+			}},
+			setup:   func() {},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name:    "failed due to invalid pagination",
 			fields:  fields{DB: sqlxDB},
@@ -352,6 +404,12 @@ func TestPostgresRepo_InsertUser(t *testing.T) {
 func TestPostgresRepo_UpdateUser(t *testing.T) {
 	mockUser := randomutil.RandomUser()
 
+	// the update must run in tx, a statement sent to the repository DB fails
+	_, noQueryDB, _, err := testutil.InitMockDB()
+	if err != nil {
+		t.Fatalf("Failed InitMockDB(): %v", err)
+	}
+
 	type fields struct {
 		DB *sqlx.DB
 	}
@@ -369,7 +427,7 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 	}{
 		{
 			name:   "success update user",
-			fields: fields{DB: sqlxDB},
+			fields: fields{DB: noQueryDB},
 			args: args{
 				ctx:  context.Background(),
 				user: mockUser,
@@ -382,7 +440,7 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 		},
 		{
 			name:   "failed due to unique constraint error",
-			fields: fields{DB: sqlxDB},
+			fields: fields{DB: noQueryDB},
 			args:   args{ctx: context.Background(), user: mockUser},
 			setup: func() {
 				mockSql.ExpectExec("UPDATE").WithArgs(mockUser.Email, mockUser.UpdatedAt, mockUser.UpdatedBy, mockUser.ID).
@@ -392,7 +450,7 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 		},
 		{
 			name:   "failed due to connection error",
-			fields: fields{DB: sqlxDB},
+			fields: fields{DB: noQueryDB},
 			args: args{
 				ctx:  context.Background(),
 				user: mockUser,
@@ -420,6 +478,175 @@ func TestPostgresRepo_UpdateUser(t *testing.T) {
 			if err := r.UpdateUser(tt.args.ctx, tt.args.tx, tt.args.user); (err != nil) != tt.wantErr {
 				t.Errorf("PostgresRepo.UpdateUser() error = %v, wantErr %v", err, tt.wantErr)
 			}
+
+			// rolling back undoes the update as it ran in the transaction
+			mockSql.ExpectRollback()
+			assert.NoError(t, tt.args.tx.Rollback())
+			assert.NoError(t, mockSql.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostgresRepo_DeleteUser(t *testing.T) {
+	mockUser := randomutil.RandomUser()
+	mockUser.Deleted = model.Deleted{
+		DeletedAt: null.TimeFrom(mockUser.CreatedAt),
+		DeletedBy: null.StringFrom("SYSTEM"),
+	}
+
+	type fields struct {
+		DB *sqlx.DB
+	}
+	type args struct {
+		ctx  context.Context
+		user *model.User
+		tx   *sqlx.Tx
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		setup   func()
+		wantErr error
+	}{
+		{
+			name:   "success delete user",
+			fields: fields{DB: sqlxDB},
+			args:   args{ctx: context.Background(), user: mockUser},
+			setup: func() {
+				mockSql.ExpectExec("UPDATE users SET").WithArgs(mockUser.DeletedAt, mockUser.DeletedBy, mockUser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "failed due to user not found or already deleted",
+			fields: fields{DB: sqlxDB},
+			args:   args{ctx: context.Background(), user: mockUser},
+			setup: func() {
+				mockSql.ExpectExec("UPDATE users SET").WithArgs(mockUser.DeletedAt, mockUser.DeletedBy, mockUser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name:   "failed due to rows affected error",
+			fields: fields{DB: sqlxDB},
+			args:   args{ctx: context.Background(), user: mockUser},
+			setup: func() {
+				mockSql.ExpectExec("UPDATE users SET").WithArgs(mockUser.DeletedAt, mockUser.DeletedBy, mockUser.ID).
+					WillReturnResult(sqlmock.NewErrorResult(testutil.MockErr))
+			},
+			wantErr: testutil.MockErr,
+		},
+		{
+			name:   "failed due to connection error",
+			fields: fields{DB: sqlxDB},
+			args:   args{ctx: context.Background(), user: mockUser},
+			setup: func() {
+				mockSql.ExpectExec("UPDATE users SET").WithArgs(mockUser.DeletedAt, mockUser.DeletedBy, mockUser.ID).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &PostgresRepo{
+				DB: tt.fields.DB,
+			}
+
+			mockSql.ExpectBegin()
+			tt.setup()
+
+			var err error
+			tt.args.tx, err = testutil.InitBeginx(sqlxDB)
+			assert.NoError(t, err)
+
+			err = r.DeleteUser(tt.args.ctx, tt.args.tx, tt.args.user)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresRepo.DeleteUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPostgresRepo_RestoreUser(t *testing.T) {
+	mockUser := randomutil.RandomUser()
+
+	type fields struct {
+		DB *sqlx.DB
+	}
+	type args struct {
+		ctx  context.Context
+		user *model.User
+		tx   *sqlx.Tx
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		setup   func()
+		wantErr error
+	}{
+		{
+			name:   "success restore user",
+			fields: fields{DB: sqlxDB},
+			args:   args{ctx: context.Background(), user: mockUser},
+			setup: func() {
+				mockSql.ExpectExec("UPDATE users SET").WithArgs(mockUser.UpdatedAt, mockUser.UpdatedBy, mockUser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "failed due to deleted user not found",
+			fields: fields{DB: sqlxDB},
+			args:   args{ctx: context.Background(), user: mockUser},
+			setup: func() {
+				mockSql.ExpectExec("UPDATE users SET").WithArgs(mockUser.UpdatedAt, mockUser.UpdatedBy, mockUser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name:   "failed due to rows affected error",
+			fields: fields{DB: sqlxDB},
+			args:   args{ctx: context.Background(), user: mockUser},
+			setup: func() {
+				mockSql.ExpectExec("UPDATE users SET").WithArgs(mockUser.UpdatedAt, mockUser.UpdatedBy, mockUser.ID).
+					WillReturnResult(sqlmock.NewErrorResult(testutil.MockErr))
+			},
+			wantErr: testutil.MockErr,
+		},
+		{
+			name:   "failed due to connection error",
+			fields: fields{DB: sqlxDB},
+			args:   args{ctx: context.Background(), user: mockUser},
+			setup: func() {
+				mockSql.ExpectExec("UPDATE users SET").WithArgs(mockUser.UpdatedAt, mockUser.UpdatedBy, mockUser.ID).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &PostgresRepo{
+				DB: tt.fields.DB,
+			}
+
+			mockSql.ExpectBegin()
+			tt.setup()
+
+			var err error
+			tt.args.tx, err = testutil.InitBeginx(sqlxDB)
+			assert.NoError(t, err)
+
+			err = r.RestoreUser(tt.args.ctx, tt.args.tx, tt.args.user)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresRepo.RestoreUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
 		})
 	}
 }
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 
+	"github.com/guregu/null/v5"
 	"github.com/pkg/errors"
 	"github.com/raflynagachi/go-rest-api-starter/internal/apperror"
 	req "github.com/raflynagachi/go-rest-api-starter/internal/dto/web/request"
@@ -10,52 +11,118 @@ import (
 	"github.com/raflynagachi/go-rest-api-starter/internal/model"
 	paginationutil "github.com/raflynagachi/go-rest-api-starter/internal/util/pagination"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/http/response"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/jwt"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/validator"
 )
 
-func (u *APIUsecaseImpl) GetUser(ctx context.Context, filter req.UserFilter) (*resp.ListResponse, error) {
+func (u *APIUsecaseImpl) GetUser(ctx context.Context, filter req.UserFilter) (_ *resp.ListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "APIUsecase.GetUser")
+	defer tracing.End(span, &err)
+
 	filter.Pagination.Validate()
 
-	invs, err := u.repo.GetUser(ctx, filter)
+	err = validator.Validate(filter)
 	if err != nil {
-		return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.GetUser.GetUser")
+		return nil, errors.Wrap(response.WrapErrBadRequest(err), "APIUsecase.GetUser.Validate")
 	}
 
-	count, err := u.repo.CountUser(ctx, filter)
+	filter.Sorts, err = req.ParseSort(filter.Sort, req.UserSortFields)
 	if err != nil {
-		return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.GetUser.CountUser")
+		return nil, errors.Wrap(response.WrapErrBadRequest(err), "APIUsecase.GetUser.ParseSort")
+	}
+
+	query := filter
+	if filter.Cursor != "" {
+		if len(filter.Sorts) > 0 {
+			return nil, errors.Wrap(response.WrapErrBadRequest(ErrCursorWithSort), "APIUsecase.GetUser.Sort")
+		}
+		query.Keyset, err = u.decodeCursor(filter.Cursor, filter.FilterHash())
+		if err != nil {
+			return nil, errors.Wrap(response.WrapErrBadRequest(err), "APIUsecase.GetUser.decodeCursor")
+		}
+		// one more row tells whether a page follows in the cursor direction
+		query.Limit++
+	}
+
+	users, err := u.repo.GetUser(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.GetUser.GetUser")
+	}
+
+	pagination := resp.PaginationResponse{Limit: filter.Limit}
+	if query.Keyset == nil {
+		pagination.Page = filter.Page
+	}
+
+	if !filter.SkipCount {
+		count, err := u.repo.CountUser(ctx, filter)
+		if err != nil {
+			return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.GetUser.CountUser")
+		}
+		pagination.Total = null.IntFrom(count)
+		pagination.TotalPage = null.IntFrom(paginationutil.TotalPage(count, int64(filter.Limit)))
+	}
+
+	var hasNext, hasPrev bool
+	switch {
+	case query.Keyset == nil:
+		hasPrev = filter.Page > 1
+		if pagination.Total.Valid {
+			hasNext = int64(filter.Page*filter.Limit) < pagination.Total.Int64
+		} else {
+			hasNext = len(users) == filter.Limit
+		}
+	case query.Keyset.Backward:
+		hasNext = true
+		hasPrev = len(users) > filter.Limit
+		if hasPrev {
+			users = users[1:]
+		}
+	default:
+		hasPrev = true
+		hasNext = len(users) > filter.Limit
+		if hasNext {
+			users = users[:filter.Limit]
+		}
+	}
+
+	// cursors follow the (created_at, id) order, a sorted listing pages by offset only
+	if len(users) > 0 && len(filter.Sorts) == 0 {
+		filterHash := filter.FilterHash()
+		if hasNext {
+			last := users[len(users)-1]
+			pagination.NextCursor, err = u.encodeCursor(req.Keyset{CreatedAt: last.CreatedAt, ID: last.ID, Filter: filterHash})
+			if err != nil {
+				return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.GetUser.encodeCursor")
+			}
+		}
+		if hasPrev {
+			first := users[0]
+			pagination.PrevCursor, err = u.encodeCursor(req.Keyset{CreatedAt: first.CreatedAt, ID: first.ID, Backward: true, Filter: filterHash})
+			if err != nil {
+				return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.GetUser.encodeCursor")
+			}
+		}
 	}
 
 	userResp := make([]*resp.UserResponse, 0)
-	for _, user := range invs {
-		userResp = append(userResp, &resp.UserResponse{
-			ID:    user.ID,
-			Email: user.Email,
-			CreatedResponse: resp.CreatedResponse{
-				CreatedAt: user.CreatedAt,
-				CreatedBy: user.CreatedBy,
-			},
-			UpdatedResponse: resp.UpdatedResponse{
-				UpdatedAt: user.UpdatedAt,
-				UpdatedBy: user.UpdatedBy,
-			},
-		})
+	for _, user := range users {
+		userResp = append(userResp, toUserResponse(user))
 	}
 
 	res := &resp.ListResponse{
-		Data: userResp,
-		PaginationResponse: resp.PaginationResponse{
-			Page:      filter.Page,
-			Limit:     filter.Limit,
-			TotalPage: paginationutil.TotalPage(int64(count), int64(filter.Limit)),
-			Total:     count,
-		},
+		Data:               userResp,
+		PaginationResponse: pagination,
 	}
 
 	return res, nil
 }
 
-func (u *APIUsecaseImpl) GetUserByID(ctx context.Context, id int64) (*resp.UserResponse, error) {
+func (u *APIUsecaseImpl) GetUserByID(ctx context.Context, id int64, filter req.UserDetailFilter) (_ *resp.UserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "APIUsecase.GetUserByID")
+	defer tracing.End(span, &err)
+
 	user, err := u.repo.GetUserByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
@@ -64,41 +131,38 @@ func (u *APIUsecaseImpl) GetUserByID(ctx context.Context, id int64) (*resp.UserR
 		return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.GetUserByID.GetUserByID")
 	}
 
-	res := &resp.UserResponse{
-		ID:    user.ID,
-		Email: user.Email,
-		CreatedResponse: resp.CreatedResponse{
-			CreatedAt: user.CreatedAt,
-			CreatedBy: user.CreatedBy,
-		},
-		UpdatedResponse: resp.UpdatedResponse{
-			UpdatedAt: user.UpdatedAt,
-			UpdatedBy: user.UpdatedBy,
-		},
+	if user.DeletedAt.Valid && !filter.IncludeDeleted {
+		return nil, errors.Wrap(response.WrapErrNotFound(apperror.ErrNotFound), "APIUsecase.GetUserByID.DeletedAt")
 	}
 
-	return res, nil
+	return toUserResponse(user), nil
 }
 
-func (u *APIUsecaseImpl) CreateUser(ctx context.Context, userReq *req.CreateUpdateUserReq) error {
-	err := validator.Validate(userReq)
-	if err != nil {
-		return errors.Wrap(response.WrapErrBadRequest(err), "APIUsecase.CreateUser.Validate")
+func (u *APIUsecaseImpl) CreateUser(ctx context.Context, userReq *req.CreateUpdateUserReq) (_ *resp.UserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "APIUsecase.CreateUser")
+	defer tracing.End(span, &err)
+
+	claims, ok := jwt.FromContext(ctx)
+	if !ok {
+		return nil, errors.Wrap(response.WrapErrUnauthorized(ErrMissingPrincipal), "APIUsecase.CreateUser.FromContext")
 	}
 
-	// TODO: implement JWT
+	err = validator.Validate(userReq)
+	if err != nil {
+		return nil, errors.Wrap(response.WrapErrBadRequest(err), "APIUsecase.CreateUser.Validate")
+	}
 
 	user := &model.User{
 		Email: userReq.Email,
 		Created: model.Created{
-			CreatedAt: getTimeNow,
-			CreatedBy: userReq.Email, // TODO: change to email in JWT
+			CreatedAt: getTimeNow(),
+			CreatedBy: claims.Principal(),
 		},
 	}
 
-	tx, err := u.repo.TxBegin()
+	tx, err := u.repo.TxBegin(ctx)
 	if err != nil {
-		return errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.CreateUser.TxBegin")
+		return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.CreateUser.TxBegin")
 	}
 	defer func() {
 		if txErr := u.repo.TxEnd(tx, err); txErr != nil {
@@ -107,54 +171,195 @@ func (u *APIUsecaseImpl) CreateUser(ctx context.Context, userReq *req.CreateUpda
 		}
 	}()
 
-	_, err = u.repo.InsertUser(ctx, tx, user)
+	user.ID, err = u.repo.InsertUser(ctx, tx, user)
 	if err != nil {
-		return errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.CreateUser.InsertUser")
+		return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.CreateUser.InsertUser")
 	}
 
-	return nil
+	return toUserResponse(user), nil
 }
 
-func (u *APIUsecaseImpl) UpdateUser(ctx context.Context, id int64, userReq *req.CreateUpdateUserReq) error {
-	err := validator.Validate(userReq)
+func (u *APIUsecaseImpl) UpdateUser(ctx context.Context, id int64, userReq *req.CreateUpdateUserReq) (updated *resp.UserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "APIUsecase.UpdateUser")
+	defer tracing.End(span, &err)
+
+	claims, ok := jwt.FromContext(ctx)
+	if !ok {
+		return nil, errors.Wrap(response.WrapErrUnauthorized(ErrMissingPrincipal), "APIUsecase.UpdateUser.FromContext")
+	}
+
+	err = validator.Validate(userReq)
 	if err != nil {
-		return errors.Wrap(response.WrapErrBadRequest(err), "APIUsecase.UpdateUser.Validate")
+		return nil, errors.Wrap(response.WrapErrBadRequest(err), "APIUsecase.UpdateUser.Validate")
 	}
 
-	_, err = u.repo.GetUserByID(ctx, id)
+	existing, err := u.repo.GetUserByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
-			return errors.Wrap(response.WrapErrNotFound(err), "APIUsecase.UpdateUser.GetUserByID")
+			return nil, errors.Wrap(response.WrapErrNotFound(err), "APIUsecase.UpdateUser.GetUserByID")
 		}
-		return errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.UpdateUser.GetUserByID")
+		return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.UpdateUser.GetUserByID")
 	}
 
-	// TODO: implement JWT
+	if existing.DeletedAt.Valid {
+		return nil, errors.Wrap(response.WrapErrNotFound(apperror.ErrNotFound), "APIUsecase.UpdateUser.DeletedAt")
+	}
 
 	user := &model.User{
 		ID:    id,
 		Email: userReq.Email,
-		Created: model.Created{
-			CreatedAt: getTimeNow,
-			CreatedBy: userReq.Email, // TODO: change to email in JWT
+		Updated: model.Updated{
+			UpdatedAt: null.TimeFrom(getTimeNow()),
+			UpdatedBy: null.StringFrom(claims.Principal()),
+		},
+	}
+
+	tx, err := u.repo.TxBegin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.UpdateUser.TxBegin")
+	}
+	defer func() {
+		txErr := u.repo.TxEnd(tx, err)
+		if txErr == nil {
+			return
+		}
+		txErr = errors.Wrap(txErr, "APIUsecase.UpdateUser.TxEnd")
+		if err == nil {
+			// the commit failed, the update returned to the client never happened
+			updated, err = nil, response.WrapErrInternalServer(txErr)
+			return
+		}
+		u.appLogger.ErrorContext(ctx, txErr.Error())
+	}()
+
+	err = u.repo.UpdateUser(ctx, tx, user)
+	if err != nil {
+		return nil, errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.UpdateUser.UpdateUser")
+	}
+
+	result := *existing
+	result.Email = user.Email
+	result.Updated = user.Updated
+	return toUserResponse(&result), nil
+}
+
+func (u *APIUsecaseImpl) DeleteUser(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "APIUsecase.DeleteUser")
+	defer tracing.End(span, &err)
+
+	claims, ok := jwt.FromContext(ctx)
+	if !ok {
+		return errors.Wrap(response.WrapErrUnauthorized(ErrMissingPrincipal), "APIUsecase.DeleteUser.FromContext")
+	}
+
+	user := &model.User{
+		ID: id,
+		Deleted: model.Deleted{
+			DeletedAt: null.TimeFrom(getTimeNow()),
+			DeletedBy: null.StringFrom(claims.Principal()),
 		},
 	}
 
-	tx, err := u.repo.TxBegin()
+	tx, err := u.repo.TxBegin(ctx)
 	if err != nil {
-		return errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.UpdateUser.TxBegin")
+		return errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.DeleteUser.TxBegin")
 	}
 	defer func() {
 		if txErr := u.repo.TxEnd(tx, err); txErr != nil {
-			txErr = errors.Wrap(txErr, "APIUsecase.UpdateUser.TxEnd")
+			txErr = errors.Wrap(txErr, "APIUsecase.DeleteUser.TxEnd")
 			u.appLogger.ErrorContext(ctx, txErr.Error())
 		}
 	}()
 
-	err = u.repo.UpdateUser(ctx, tx, user)
+	err = u.repo.DeleteUser(ctx, tx, user)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return errors.Wrap(response.WrapErrNotFound(err), "APIUsecase.DeleteUser.DeleteUser")
+		}
+		return errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.DeleteUser.DeleteUser")
+	}
+
+	return nil
+}
+
+func (u *APIUsecaseImpl) RestoreUser(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "APIUsecase.RestoreUser")
+	defer tracing.End(span, &err)
+
+	claims, ok := jwt.FromContext(ctx)
+	if !ok {
+		return errors.Wrap(response.WrapErrUnauthorized(ErrMissingPrincipal), "APIUsecase.RestoreUser.FromContext")
+	}
+
+	user := &model.User{
+		ID: id,
+		Updated: model.Updated{
+			UpdatedAt: null.TimeFrom(getTimeNow()),
+			UpdatedBy: null.StringFrom(claims.Principal()),
+		},
+	}
+
+	tx, err := u.repo.TxBegin(ctx)
+	if err != nil {
+		return errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.RestoreUser.TxBegin")
+	}
+	defer func() {
+		if txErr := u.repo.TxEnd(tx, err); txErr != nil {
+			txErr = errors.Wrap(txErr, "APIUsecase.RestoreUser.TxEnd")
+			u.appLogger.ErrorContext(ctx, txErr.Error())
+		}
+	}()
+
+	err = u.repo.RestoreUser(ctx, tx, user)
 	if err != nil {
-		return errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.UpdateUser.UpdateUser")
+		if errors.Is(err, apperror.ErrNotFound) {
+			return errors.Wrap(response.WrapErrNotFound(err), "APIUsecase.RestoreUser.RestoreUser")
+		}
+		return errors.Wrap(response.WrapErrInternalServer(err), "APIUsecase.RestoreUser.RestoreUser")
 	}
 
 	return nil
 }
+
+// toUserResponse maps user model to its response
+func toUserResponse(user *model.User) *resp.UserResponse {
+	return &resp.UserResponse{
+		ID:    user.ID,
+		Email: user.Email,
+		CreatedResponse: resp.CreatedResponse{
+			CreatedAt: user.CreatedAt,
+			CreatedBy: user.CreatedBy,
+		},
+		UpdatedResponse: resp.UpdatedResponse{
+			UpdatedAt: user.UpdatedAt,
+			UpdatedBy: user.UpdatedBy,
+		},
+		DeletedResponse: resp.DeletedResponse{
+			DeletedAt: user.DeletedAt,
+			DeletedBy: user.DeletedBy,
+		},
+	}
+}
+
+// decodeCursor returns the keyset of token, which must have been issued for the filter of filterHash
+func (u *APIUsecaseImpl) decodeCursor(token string, filterHash string) (*req.Keyset, error) {
+	if u.cursors == nil {
+		return nil, ErrCursorUnavailable
+	}
+
+	keyset := &req.Keyset{}
+	if err := u.cursors.Decode(token, keyset); err != nil {
+		return nil, err
+	}
+	if keyset.Filter != filterHash {
+		return nil, ErrCursorFilter
+	}
+	return keyset, nil
+}
+
+func (u *APIUsecaseImpl) encodeCursor(keyset req.Keyset) (string, error) {
+	if u.cursors == nil {
+		return "", nil
+	}
+	return u.cursors.Encode(keyset)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/guregu/null/v5"
 	"github.com/jmoiron/sqlx"
 	"github.com/raflynagachi/go-rest-api-starter/config"
 	"github.com/raflynagachi/go-rest-api-starter/internal/apperror"
@@ -12,10 +14,16 @@ import (
 	resp "github.com/raflynagachi/go-rest-api-starter/internal/dto/web/response"
 	"github.com/raflynagachi/go-rest-api-starter/internal/model"
 	repo "github.com/raflynagachi/go-rest-api-starter/internal/repository/definition"
+	"github.com/raflynagachi/go-rest-api-starter/internal/repository/definition/mocks"
 	paginationutil "github.com/raflynagachi/go-rest-api-starter/internal/util/pagination"
 	randomutil "github.com/raflynagachi/go-rest-api-starter/internal/util/random"
 	"github.com/raflynagachi/go-rest-api-starter/internal/util/testutil"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/cursor"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/response"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/jwt"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAPIUsecaseImpl_GetUser(t *testing.T) {
@@ -40,13 +48,19 @@ func TestAPIUsecaseImpl_GetUser(t *testing.T) {
 	mockFilter := req.UserFilter{
 		Pagination: mockPagination,
 	}
+	mockSortFilter := req.UserFilter{
+		Sort:       "-email",
+		Pagination: mockPagination,
+	}
+	mockSortedFilter := mockSortFilter
+	mockSortedFilter.Sorts = []req.SortField{{Field: "email", Desc: true}}
 	mockResp := &resp.ListResponse{
 		Data: mockUserResp,
 		PaginationResponse: resp.PaginationResponse{
 			Page:      mockPagination.Page,
-			TotalPage: paginationutil.TotalPage(mockCount, int64(mockPagination.Limit)),
+			TotalPage: null.IntFrom(paginationutil.TotalPage(mockCount, int64(mockPagination.Limit))),
 			Limit:     mockPagination.Limit,
-			Total:     mockCount,
+			Total:     null.IntFrom(mockCount),
 		},
 	}
 
@@ -77,14 +91,75 @@ func TestAPIUsecaseImpl_GetUser(t *testing.T) {
 				filter: mockFilter,
 			},
 			setup: func() {
-				mockRepo.On("GetUser", context.Background(), mockFilter).
+				mockRepo.On("GetUser", testutil.TracedCtx, mockFilter).
+					Once().Return([]*model.User{mockUser}, nil)
+				mockRepo.On("CountUser", testutil.TracedCtx, mockFilter).
+					Once().Return(mockCount, nil)
+			},
+			want:    mockResp,
+			wantErr: false,
+		},
+		{
+			name: "success with sort",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx:    context.Background(),
+				filter: mockSortFilter,
+			},
+			setup: func() {
+				mockRepo.On("GetUser", testutil.TracedCtx, mockSortedFilter).
 					Once().Return([]*model.User{mockUser}, nil)
-				mockRepo.On("CountUser", context.Background(), mockFilter).
+				mockRepo.On("CountUser", testutil.TracedCtx, mockSortedFilter).
 					Once().Return(mockCount, nil)
 			},
 			want:    mockResp,
 			wantErr: false,
 		},
+		{
+			name: "failed due to unknown sort field",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx:    context.Background(),
+				filter: req.UserFilter{Sort: "password", Pagination: mockPagination},
+			},
+			setup:   func() {},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "failed due to invalid email match",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx:    context.Background(),
+				filter: req.UserFilter{Email: "a", EmailMatch: "regex", Pagination: mockPagination},
+			},
+			setup:   func() {},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "failed due to cursor with sort",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx:    context.Background(),
+				filter: req.UserFilter{Sort: "email", Pagination: req.Pagination{Page: 1, Limit: 1, Cursor: "token"}},
+			},
+			setup:   func() {},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "failed due to get user error",
 			fields: fields{
@@ -96,7 +171,7 @@ func TestAPIUsecaseImpl_GetUser(t *testing.T) {
 				filter: mockFilter,
 			},
 			setup: func() {
-				mockRepo.On("GetUser", context.Background(), mockFilter).
+				mockRepo.On("GetUser", testutil.TracedCtx, mockFilter).
 					Once().Return(nil, testutil.MockErr)
 			},
 			want:    nil,
@@ -113,9 +188,9 @@ func TestAPIUsecaseImpl_GetUser(t *testing.T) {
 				filter: mockFilter,
 			},
 			setup: func() {
-				mockRepo.On("GetUser", context.Background(), mockFilter).
+				mockRepo.On("GetUser", testutil.TracedCtx, mockFilter).
 					Once().Return([]*model.User{mockUser}, nil)
-				mockRepo.On("CountUser", context.Background(), mockFilter).
+				mockRepo.On("CountUser", testutil.TracedCtx, mockFilter).
 					Once().Return(int64(0), testutil.MockErr)
 			},
 			want:    nil,
@@ -143,6 +218,144 @@ func TestAPIUsecaseImpl_GetUser(t *testing.T) {
 	}
 }
 
+func TestAPIUsecaseImpl_GetUser_Cursor(t *testing.T) {
+	signer, err := cursor.NewSigner("secret")
+	require.NoError(t, err)
+
+	baseTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	mockUsers := make([]*model.User, 0, 4)
+	for i := 1; i <= 4; i++ {
+		user := randomutil.RandomUser()
+		user.ID = int64(i)
+		user.CreatedAt = baseTime.Add(time.Duration(i) * time.Minute)
+		mockUsers = append(mockUsers, user)
+	}
+	keysetOf := func(user *model.User, backward bool) *req.Keyset {
+		return &req.Keyset{CreatedAt: user.CreatedAt, ID: user.ID, Backward: backward, Filter: req.UserFilter{}.FilterHash()}
+	}
+	encode := func(keyset *req.Keyset) string {
+		token, err := signer.Encode(keyset)
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name       string
+		filter     req.UserFilter
+		setup      func(sqlRepo *mocks.SQLRepo)
+		wantIDs    []int64
+		wantPage   int
+		wantTotal  null.Int
+		wantNext   *req.Keyset
+		wantPrev   *req.Keyset
+		wantErrRes error
+	}{
+		{
+			name:   "success first page without count",
+			filter: req.UserFilter{Pagination: req.Pagination{Page: 1, Limit: 2, SkipCount: true}},
+			setup: func(sqlRepo *mocks.SQLRepo) {
+				sqlRepo.On("GetUser", testutil.TracedCtx, req.UserFilter{Pagination: req.Pagination{Page: 1, Limit: 2, SkipCount: true}}).
+					Once().Return(mockUsers[:2], nil)
+			},
+			wantIDs:  []int64{1, 2},
+			wantPage: 1,
+			wantNext: keysetOf(mockUsers[1], false),
+		},
+		{
+			name:   "success offset page with count",
+			filter: req.UserFilter{Pagination: req.Pagination{Page: 2, Limit: 2}},
+			setup: func(sqlRepo *mocks.SQLRepo) {
+				filter := req.UserFilter{Pagination: req.Pagination{Page: 2, Limit: 2}}
+				sqlRepo.On("GetUser", testutil.TracedCtx, filter).Once().Return(mockUsers[2:], nil)
+				sqlRepo.On("CountUser", testutil.TracedCtx, filter).Once().Return(int64(4), nil)
+			},
+			wantIDs:   []int64{3, 4},
+			wantPage:  2,
+			wantTotal: null.IntFrom(4),
+			wantPrev:  keysetOf(mockUsers[2], true),
+		},
+		{
+			name:   "success next cursor with more rows",
+			filter: req.UserFilter{Pagination: req.Pagination{Limit: 2, Cursor: encode(keysetOf(mockUsers[0], false)), SkipCount: true}},
+			setup: func(sqlRepo *mocks.SQLRepo) {
+				sqlRepo.On("GetUser", testutil.TracedCtx, mock.MatchedBy(func(filter req.UserFilter) bool {
+					return filter.Limit == 3 && reflect.DeepEqual(filter.Keyset, keysetOf(mockUsers[0], false))
+				})).Once().Return(mockUsers[1:], nil)
+			},
+			wantIDs:  []int64{2, 3},
+			wantNext: keysetOf(mockUsers[2], false),
+			wantPrev: keysetOf(mockUsers[1], true),
+		},
+		{
+			name:   "success prev cursor reaching the start",
+			filter: req.UserFilter{Pagination: req.Pagination{Limit: 2, Cursor: encode(keysetOf(mockUsers[2], true)), SkipCount: true}},
+			setup: func(sqlRepo *mocks.SQLRepo) {
+				sqlRepo.On("GetUser", testutil.TracedCtx, mock.MatchedBy(func(filter req.UserFilter) bool {
+					return filter.Limit == 3 && reflect.DeepEqual(filter.Keyset, keysetOf(mockUsers[2], true))
+				})).Once().Return(mockUsers[:2], nil)
+			},
+			wantIDs:  []int64{1, 2},
+			wantNext: keysetOf(mockUsers[1], false),
+		},
+		{
+			name:       "failed due to forged cursor",
+			filter:     req.UserFilter{Pagination: req.Pagination{Limit: 2, Cursor: "eyJpZCI6MX0.c2lnbmF0dXJl"}},
+			setup:      func(sqlRepo *mocks.SQLRepo) {},
+			wantErrRes: response.WrapErrBadRequest(cursor.ErrInvalidCursor),
+		},
+		{
+			name: "failed due to cursor of another filter",
+			filter: req.UserFilter{
+				Email:      "user@mail.com",
+				Pagination: req.Pagination{Limit: 2, Cursor: encode(keysetOf(mockUsers[0], false))},
+			},
+			setup:      func(sqlRepo *mocks.SQLRepo) {},
+			wantErrRes: response.WrapErrBadRequest(ErrCursorFilter),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlRepo := new(mocks.SQLRepo)
+			tt.setup(sqlRepo)
+			u := &APIUsecaseImpl{cfg: mockCfg, repo: sqlRepo, cursors: signer}
+
+			got, err := u.GetUser(context.Background(), tt.filter)
+			if tt.wantErrRes != nil {
+				errResp, _ := response.FindErrResponse(err)
+				assert.Equal(t, tt.wantErrRes, errResp)
+				return
+			}
+			require.NoError(t, err)
+			sqlRepo.AssertExpectations(t)
+
+			gotIDs := []int64{}
+			for _, user := range got.Data.([]*resp.UserResponse) {
+				gotIDs = append(gotIDs, user.ID)
+			}
+			assert.Equal(t, tt.wantIDs, gotIDs)
+			assert.Equal(t, tt.wantPage, got.Page)
+			assert.Equal(t, tt.wantTotal, got.Total)
+
+			for _, c := range []struct {
+				token string
+				want  *req.Keyset
+			}{{got.NextCursor, tt.wantNext}, {got.PrevCursor, tt.wantPrev}} {
+				if c.want == nil {
+					assert.Empty(t, c.token)
+					continue
+				}
+				keyset := &req.Keyset{}
+				require.NoError(t, signer.Decode(c.token, keyset))
+				assert.True(t, c.want.CreatedAt.Equal(keyset.CreatedAt))
+				assert.Equal(t, c.want.ID, keyset.ID)
+				assert.Equal(t, c.want.Backward, keyset.Backward)
+				assert.Equal(t, c.want.Filter, keyset.Filter)
+			}
+		})
+	}
+}
+
 func TestAPIUsecaseImpl_GetUserByID(t *testing.T) {
 	mockUser := randomutil.RandomUser()
 	mockResp := &resp.UserResponse{
@@ -162,9 +375,32 @@ func TestAPIUsecaseImpl_GetUserByID(t *testing.T) {
 		cfg  *config.Config
 		repo repo.SQLRepo
 	}
+	mockDeletedUser := randomutil.RandomUser()
+	mockDeletedUser.Deleted = model.Deleted{
+		DeletedAt: null.TimeFrom(mockDeletedUser.CreatedAt),
+		DeletedBy: null.StringFrom("SYSTEM"),
+	}
+	mockDeletedResp := &resp.UserResponse{
+		ID:    mockDeletedUser.ID,
+		Email: mockDeletedUser.Email,
+		CreatedResponse: resp.CreatedResponse{
+			CreatedAt: mockDeletedUser.CreatedAt,
+			CreatedBy: mockDeletedUser.CreatedBy,
+		},
+		UpdatedResponse: resp.UpdatedResponse{
+			UpdatedAt: mockDeletedUser.UpdatedAt,
+			UpdatedBy: mockDeletedUser.UpdatedBy,
+		},
+		DeletedResponse: resp.DeletedResponse{
+			DeletedAt: mockDeletedUser.DeletedAt,
+			DeletedBy: mockDeletedUser.DeletedBy,
+		},
+	}
+
 	type args struct {
-		ctx context.Context
-		id  int64
+		ctx    context.Context
+		id     int64
+		filter req.UserDetailFilter
 	}
 	tests := []struct {
 		name    string
@@ -185,12 +421,47 @@ func TestAPIUsecaseImpl_GetUserByID(t *testing.T) {
 				id:  mockUser.ID,
 			},
 			setup: func() {
-				mockRepo.On("GetUserByID", context.Background(), mockUser.ID).
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockUser.ID).
 					Once().Return(mockUser, nil)
 			},
 			want:    mockResp,
 			wantErr: false,
 		},
+		{
+			name: "success get deleted user with include deleted",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx:    context.Background(),
+				id:     mockDeletedUser.ID,
+				filter: req.UserDetailFilter{IncludeDeleted: true},
+			},
+			setup: func() {
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockDeletedUser.ID).
+					Once().Return(mockDeletedUser, nil)
+			},
+			want:    mockDeletedResp,
+			wantErr: false,
+		},
+		{
+			name: "failed due to user is deleted",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  mockDeletedUser.ID,
+			},
+			setup: func() {
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockDeletedUser.ID).
+					Once().Return(mockDeletedUser, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "failed due to user not found",
 			fields: fields{
@@ -202,7 +473,7 @@ func TestAPIUsecaseImpl_GetUserByID(t *testing.T) {
 				id:  mockUser.ID,
 			},
 			setup: func() {
-				mockRepo.On("GetUserByID", context.Background(), mockUser.ID).
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockUser.ID).
 					Once().Return(nil, apperror.ErrNotFound)
 			},
 			want:    nil,
@@ -219,7 +490,7 @@ func TestAPIUsecaseImpl_GetUserByID(t *testing.T) {
 				id:  mockUser.ID,
 			},
 			setup: func() {
-				mockRepo.On("GetUserByID", context.Background(), mockUser.ID).
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockUser.ID).
 					Once().Return(nil, testutil.MockErr)
 			},
 			want:    nil,
@@ -235,7 +506,7 @@ func TestAPIUsecaseImpl_GetUserByID(t *testing.T) {
 
 			tt.setup()
 
-			got, err := u.GetUserByID(tt.args.ctx, tt.args.id)
+			got, err := u.GetUserByID(tt.args.ctx, tt.args.id, tt.args.filter)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("APIUsecaseImpl.GetUserByID() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -249,6 +520,7 @@ func TestAPIUsecaseImpl_GetUserByID(t *testing.T) {
 
 func TestAPIUsecaseImpl_CreateUser(t *testing.T) {
 	mockUser := randomutil.RandomUser()
+	mockCtx := jwt.NewContext(context.Background(), &jwt.Claims{Email: "SYSTEM"})
 
 	mockTx := &sqlx.Tx{}
 
@@ -265,6 +537,7 @@ func TestAPIUsecaseImpl_CreateUser(t *testing.T) {
 		fields  fields
 		args    args
 		setup   func()
+		want    *resp.UserResponse
 		wantErr bool
 	}{
 		{
@@ -274,18 +547,40 @@ func TestAPIUsecaseImpl_CreateUser(t *testing.T) {
 				repo: mockRepo,
 			},
 			args: args{
-				ctx: context.Background(),
+				ctx: mockCtx,
 				userReq: &req.CreateUpdateUserReq{
 					Email: mockUser.Email,
 				},
 			},
 			setup: func() {
-				mockRepo.On("TxBegin").Once().Return(mockTx, nil)
-				mockRepo.On("InsertUser", context.Background(), mockTx, mock.Anything).Once().Return(mockUser.ID, nil)
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("InsertUser", testutil.TracedCtx, mockTx, mock.MatchedBy(func(user *model.User) bool {
+					return user.CreatedBy == "SYSTEM" && user.CreatedAt.Equal(mockNow)
+				})).Once().Return(mockUser.ID, nil)
 				mockRepo.On("TxEnd", mockTx, nil).Once().Return(nil)
 			},
+			want: &resp.UserResponse{
+				ID:              mockUser.ID,
+				Email:           mockUser.Email,
+				CreatedResponse: resp.CreatedResponse{CreatedAt: mockNow, CreatedBy: "SYSTEM"},
+			},
 			wantErr: false,
 		},
+		{
+			name: "failed due to missing principal",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: context.Background(),
+				userReq: &req.CreateUpdateUserReq{
+					Email: mockUser.Email,
+				},
+			},
+			setup:   func() {},
+			wantErr: true,
+		},
 		{
 			name: "failed due to validation request",
 			fields: fields{
@@ -293,7 +588,7 @@ func TestAPIUsecaseImpl_CreateUser(t *testing.T) {
 				repo: mockRepo,
 			},
 			args: args{
-				ctx:     context.Background(),
+				ctx:     mockCtx,
 				userReq: &req.CreateUpdateUserReq{},
 			},
 			setup:   func() {},
@@ -306,13 +601,13 @@ func TestAPIUsecaseImpl_CreateUser(t *testing.T) {
 				repo: mockRepo,
 			},
 			args: args{
-				ctx: context.Background(),
+				ctx: mockCtx,
 				userReq: &req.CreateUpdateUserReq{
 					Email: mockUser.Email,
 				},
 			},
 			setup: func() {
-				mockRepo.On("TxBegin").Once().Return(nil, testutil.MockErr)
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(nil, testutil.MockErr)
 			},
 			wantErr: true,
 		},
@@ -323,15 +618,15 @@ func TestAPIUsecaseImpl_CreateUser(t *testing.T) {
 				repo: mockRepo,
 			},
 			args: args{
-				ctx: context.Background(),
+				ctx: mockCtx,
 				userReq: &req.CreateUpdateUserReq{
 					Email: mockUser.Email,
 				},
 			},
 			setup: func() {
-				mockRepo.On("TxBegin").Once().Return(mockTx, nil)
-				mockRepo.On("InsertUser", context.Background(), mockTx, mock.Anything).Once().Return(int64(0), testutil.MockErr)
-				mockRepo.On("TxEnd", mockTx, testutil.MockErr).Once().Return(nil)
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("InsertUser", testutil.TracedCtx, mockTx, mock.Anything).Once().Return(int64(0), testutil.MockErr)
+				mockRepo.On("TxEnd", mockTx, testutil.MatchError(testutil.MockErr)).Once().Return(nil)
 			},
 			wantErr: true,
 		},
@@ -342,14 +637,14 @@ func TestAPIUsecaseImpl_CreateUser(t *testing.T) {
 				repo: mockRepo,
 			},
 			args: args{
-				ctx: context.Background(),
+				ctx: mockCtx,
 				userReq: &req.CreateUpdateUserReq{
 					Email: mockUser.Email,
 				},
 			},
 			setup: func() {
-				mockRepo.On("TxBegin").Once().Return(mockTx, nil)
-				mockRepo.On("InsertUser", context.Background(), mockTx, mock.Anything).Once().Return(mockUser.ID, nil)
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("InsertUser", testutil.TracedCtx, mockTx, mock.Anything).Once().Return(mockUser.ID, nil)
 				mockRepo.On("TxEnd", mockTx, nil).Once().Return(testutil.MockErr)
 			},
 			wantErr: false,
@@ -365,8 +660,13 @@ func TestAPIUsecaseImpl_CreateUser(t *testing.T) {
 
 			tt.setup()
 
-			if err := u.CreateUser(tt.args.ctx, tt.args.userReq); (err != nil) != tt.wantErr {
+			got, err := u.CreateUser(tt.args.ctx, tt.args.userReq)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("APIUsecaseImpl.CreateUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("APIUsecaseImpl.CreateUser() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -374,6 +674,7 @@ func TestAPIUsecaseImpl_CreateUser(t *testing.T) {
 
 func TestAPIUsecaseImpl_UpdateUser(t *testing.T) {
 	mockUser := randomutil.RandomUser()
+	mockCtx := jwt.NewContext(context.Background(), &jwt.Claims{Email: "SYSTEM"})
 
 	mockTx := &sqlx.Tx{}
 
@@ -391,6 +692,7 @@ func TestAPIUsecaseImpl_UpdateUser(t *testing.T) {
 		fields  fields
 		args    args
 		setup   func()
+		want    *resp.UserResponse
 		wantErr bool
 	}{
 		{
@@ -400,20 +702,44 @@ func TestAPIUsecaseImpl_UpdateUser(t *testing.T) {
 				repo: mockRepo,
 			},
 			args: args{
-				ctx: context.Background(),
+				ctx: mockCtx,
 				id:  mockUser.ID,
 				userReq: &req.CreateUpdateUserReq{
 					Email: mockUser.Email,
 				},
 			},
 			setup: func() {
-				mockRepo.On("GetUserByID", context.Background(), mockUser.ID).Once().Return(mockUser, nil)
-				mockRepo.On("TxBegin").Once().Return(mockTx, nil)
-				mockRepo.On("UpdateUser", context.Background(), mockTx, mock.Anything).Once().Return(nil)
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockUser.ID).Once().Return(mockUser, nil)
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("UpdateUser", testutil.TracedCtx, mockTx, mock.MatchedBy(func(user *model.User) bool {
+					return user.UpdatedBy.String == "SYSTEM" && user.UpdatedAt == null.TimeFrom(mockNow)
+				})).Once().Return(nil)
 				mockRepo.On("TxEnd", mockTx, nil).Once().Return(nil)
 			},
+			want: &resp.UserResponse{
+				ID:              mockUser.ID,
+				Email:           mockUser.Email,
+				CreatedResponse: resp.CreatedResponse{CreatedAt: mockUser.CreatedAt, CreatedBy: mockUser.CreatedBy},
+				UpdatedResponse: resp.UpdatedResponse{UpdatedAt: null.TimeFrom(mockNow), UpdatedBy: null.StringFrom("SYSTEM")},
+			},
 			wantErr: false,
 		},
+		{
+			name: "failed due to missing principal",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  mockUser.ID,
+				userReq: &req.CreateUpdateUserReq{
+					Email: mockUser.Email,
+				},
+			},
+			setup:   func() {},
+			wantErr: true,
+		},
 		{
 			name: "failed due to validation request",
 			fields: fields{
@@ -421,7 +747,7 @@ func TestAPIUsecaseImpl_UpdateUser(t *testing.T) {
 				repo: mockRepo,
 			},
 			args: args{
-				ctx:     context.Background(),
+				ctx:     mockCtx,
 				id:      mockUser.ID,
 				userReq: &req.CreateUpdateUserReq{},
 			},
@@ -435,14 +761,34 @@ func TestAPIUsecaseImpl_UpdateUser(t *testing.T) {
 				repo: mockRepo,
 			},
 			args: args{
-				ctx: context.Background(),
+				ctx: mockCtx,
+				id:  mockUser.ID,
+				userReq: &req.CreateUpdateUserReq{
+					Email: mockUser.Email,
+				},
+			},
+			setup: func() {
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockUser.ID).Once().Return(nil, apperror.ErrNotFound)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed due to user is deleted",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: mockCtx,
 				id:  mockUser.ID,
 				userReq: &req.CreateUpdateUserReq{
 					Email: mockUser.Email,
 				},
 			},
 			setup: func() {
-				mockRepo.On("GetUserByID", context.Background(), mockUser.ID).Once().Return(nil, apperror.ErrNotFound)
+				mockDeletedUser := *mockUser
+				mockDeletedUser.DeletedAt = null.TimeFrom(mockUser.CreatedAt)
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockUser.ID).Once().Return(&mockDeletedUser, nil)
 			},
 			wantErr: true,
 		},
@@ -453,14 +799,14 @@ func TestAPIUsecaseImpl_UpdateUser(t *testing.T) {
 				repo: mockRepo,
 			},
 			args: args{
-				ctx: context.Background(),
+				ctx: mockCtx,
 				id:  mockUser.ID,
 				userReq: &req.CreateUpdateUserReq{
 					Email: mockUser.Email,
 				},
 			},
 			setup: func() {
-				mockRepo.On("GetUserByID", context.Background(), mockUser.ID).Once().Return(nil, testutil.MockErr)
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockUser.ID).Once().Return(nil, testutil.MockErr)
 			},
 			wantErr: true,
 		},
@@ -471,15 +817,15 @@ func TestAPIUsecaseImpl_UpdateUser(t *testing.T) {
 				repo: mockRepo,
 			},
 			args: args{
-				ctx: context.Background(),
+				ctx: mockCtx,
 				id:  mockUser.ID,
 				userReq: &req.CreateUpdateUserReq{
 					Email: mockUser.Email,
 				},
 			},
 			setup: func() {
-				mockRepo.On("GetUserByID", context.Background(), mockUser.ID).Once().Return(mockUser, nil)
-				mockRepo.On("TxBegin").Once().Return(nil, testutil.MockErr)
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockUser.ID).Once().Return(mockUser, nil)
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(nil, testutil.MockErr)
 			},
 			wantErr: true,
 		},
@@ -490,40 +836,40 @@ func TestAPIUsecaseImpl_UpdateUser(t *testing.T) {
 				repo: mockRepo,
 			},
 			args: args{
-				ctx: context.Background(),
+				ctx: mockCtx,
 				id:  mockUser.ID,
 				userReq: &req.CreateUpdateUserReq{
 					Email: mockUser.Email,
 				},
 			},
 			setup: func() {
-				mockRepo.On("GetUserByID", context.Background(), mockUser.ID).Once().Return(mockUser, nil)
-				mockRepo.On("TxBegin").Once().Return(mockTx, nil)
-				mockRepo.On("UpdateUser", context.Background(), mockTx, mock.Anything).Once().Return(testutil.MockErr)
-				mockRepo.On("TxEnd", mockTx, testutil.MockErr).Once().Return(nil)
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockUser.ID).Once().Return(mockUser, nil)
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("UpdateUser", testutil.TracedCtx, mockTx, mock.Anything).Once().Return(testutil.MockErr)
+				mockRepo.On("TxEnd", mockTx, testutil.MatchError(testutil.MockErr)).Once().Return(nil)
 			},
 			wantErr: true,
 		},
 		{
-			name: "failed due to TxEnd error",
+			name: "failed due to commit error returns no user",
 			fields: fields{
 				cfg:  mockCfg,
 				repo: mockRepo,
 			},
 			args: args{
-				ctx: context.Background(),
+				ctx: mockCtx,
 				id:  mockUser.ID,
 				userReq: &req.CreateUpdateUserReq{
 					Email: mockUser.Email,
 				},
 			},
 			setup: func() {
-				mockRepo.On("GetUserByID", context.Background(), mockUser.ID).Once().Return(mockUser, nil)
-				mockRepo.On("TxBegin").Once().Return(mockTx, nil)
-				mockRepo.On("UpdateUser", context.Background(), mockTx, mock.Anything).Once().Return(nil)
+				mockRepo.On("GetUserByID", testutil.TracedCtx, mockUser.ID).Once().Return(mockUser, nil)
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("UpdateUser", testutil.TracedCtx, mockTx, mock.Anything).Once().Return(nil)
 				mockRepo.On("TxEnd", mockTx, nil).Once().Return(testutil.MockErr)
 			},
-			wantErr: false,
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
@@ -536,8 +882,273 @@ func TestAPIUsecaseImpl_UpdateUser(t *testing.T) {
 
 			tt.setup()
 
-			if err := u.UpdateUser(tt.args.ctx, tt.args.id, tt.args.userReq); (err != nil) != tt.wantErr {
+			got, err := u.UpdateUser(tt.args.ctx, tt.args.id, tt.args.userReq)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("APIUsecaseImpl.UpdateUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && got != nil {
+				t.Errorf("APIUsecaseImpl.UpdateUser() = %v, want no user on error", got)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("APIUsecaseImpl.UpdateUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIUsecaseImpl_DeleteUser(t *testing.T) {
+	mockUser := randomutil.RandomUser()
+	mockCtx := jwt.NewContext(context.Background(), &jwt.Claims{Email: "SYSTEM"})
+
+	mockTx := &sqlx.Tx{}
+
+	type fields struct {
+		cfg  *config.Config
+		repo repo.SQLRepo
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		setup   func()
+		wantErr bool
+	}{
+		{
+			name: "success delete user",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: mockCtx,
+				id:  mockUser.ID,
+			},
+			setup: func() {
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("DeleteUser", testutil.TracedCtx, mockTx, mock.MatchedBy(func(user *model.User) bool {
+					return user.ID == mockUser.ID && user.DeletedBy.String == "SYSTEM" && user.DeletedAt == null.TimeFrom(mockNow)
+				})).Once().Return(nil)
+				mockRepo.On("TxEnd", mockTx, nil).Once().Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed due to missing principal",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  mockUser.ID,
+			},
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name: "failed due to TxBegin error",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: mockCtx,
+				id:  mockUser.ID,
+			},
+			setup: func() {
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(nil, testutil.MockErr)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed due to user not found",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: mockCtx,
+				id:  mockUser.ID,
+			},
+			setup: func() {
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("DeleteUser", testutil.TracedCtx, mockTx, mock.Anything).Once().Return(apperror.ErrNotFound)
+				mockRepo.On("TxEnd", mockTx, testutil.MatchError(apperror.ErrNotFound)).Once().Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed due to DeleteUser error",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: mockCtx,
+				id:  mockUser.ID,
+			},
+			setup: func() {
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("DeleteUser", testutil.TracedCtx, mockTx, mock.Anything).Once().Return(testutil.MockErr)
+				mockRepo.On("TxEnd", mockTx, testutil.MatchError(testutil.MockErr)).Once().Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed due to TxEnd error",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: mockCtx,
+				id:  mockUser.ID,
+			},
+			setup: func() {
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("DeleteUser", testutil.TracedCtx, mockTx, mock.Anything).Once().Return(nil)
+				mockRepo.On("TxEnd", mockTx, nil).Once().Return(testutil.MockErr)
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &APIUsecaseImpl{
+				cfg:       tt.fields.cfg,
+				appLogger: mockLogger,
+				repo:      tt.fields.repo,
+			}
+
+			tt.setup()
+
+			if err := u.DeleteUser(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("APIUsecaseImpl.DeleteUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAPIUsecaseImpl_RestoreUser(t *testing.T) {
+	mockUser := randomutil.RandomUser()
+	mockCtx := jwt.NewContext(context.Background(), &jwt.Claims{Email: "SYSTEM"})
+
+	mockTx := &sqlx.Tx{}
+
+	type fields struct {
+		cfg  *config.Config
+		repo repo.SQLRepo
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		setup   func()
+		wantErr bool
+	}{
+		{
+			name: "success restore user",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: mockCtx,
+				id:  mockUser.ID,
+			},
+			setup: func() {
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("RestoreUser", testutil.TracedCtx, mockTx, mock.MatchedBy(func(user *model.User) bool {
+					return user.ID == mockUser.ID && user.UpdatedBy.String == "SYSTEM" && user.UpdatedAt == null.TimeFrom(mockNow)
+				})).Once().Return(nil)
+				mockRepo.On("TxEnd", mockTx, nil).Once().Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed due to missing principal",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  mockUser.ID,
+			},
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name: "failed due to TxBegin error",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: mockCtx,
+				id:  mockUser.ID,
+			},
+			setup: func() {
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(nil, testutil.MockErr)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed due to deleted user not found",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: mockCtx,
+				id:  mockUser.ID,
+			},
+			setup: func() {
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("RestoreUser", testutil.TracedCtx, mockTx, mock.Anything).Once().Return(apperror.ErrNotFound)
+				mockRepo.On("TxEnd", mockTx, testutil.MatchError(apperror.ErrNotFound)).Once().Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed due to RestoreUser error",
+			fields: fields{
+				cfg:  mockCfg,
+				repo: mockRepo,
+			},
+			args: args{
+				ctx: mockCtx,
+				id:  mockUser.ID,
+			},
+			setup: func() {
+				mockRepo.On("TxBegin", testutil.TracedCtx).Once().Return(mockTx, nil)
+				mockRepo.On("RestoreUser", testutil.TracedCtx, mockTx, mock.Anything).Once().Return(testutil.MockErr)
+				mockRepo.On("TxEnd", mockTx, testutil.MatchError(testutil.MockErr)).Once().Return(nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &APIUsecaseImpl{
+				cfg:       tt.fields.cfg,
+				appLogger: mockLogger,
+				repo:      tt.fields.repo,
+			}
+
+			tt.setup()
+
+			if err := u.RestoreUser(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("APIUsecaseImpl.RestoreUser() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
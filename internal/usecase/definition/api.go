@@ -9,7 +9,9 @@ import (
 
 type APIUsecase interface {
 	GetUser(ctx context.Context, filter req.UserFilter) (*resp.ListResponse, error)
-	GetUserByID(ctx context.Context, id int64) (*resp.UserResponse, error)
-	CreateUser(ctx context.Context, request *req.CreateUpdateUserReq) error
-	UpdateUser(ctx context.Context, id int64, request *req.CreateUpdateUserReq) error
+	GetUserByID(ctx context.Context, id int64, filter req.UserDetailFilter) (*resp.UserResponse, error)
+	CreateUser(ctx context.Context, request *req.CreateUpdateUserReq) (*resp.UserResponse, error)
+	UpdateUser(ctx context.Context, id int64, request *req.CreateUpdateUserReq) (*resp.UserResponse, error)
+	DeleteUser(ctx context.Context, id int64) error
+	RestoreUser(ctx context.Context, id int64) error
 }
@@ -18,16 +18,46 @@ type APIUsecase struct {
 }
 
 // CreateUser provides a mock function with given fields: ctx, _a1
-func (_m *APIUsecase) CreateUser(ctx context.Context, _a1 *request.CreateUpdateUserReq) error {
+func (_m *APIUsecase) CreateUser(ctx context.Context, _a1 *request.CreateUpdateUserReq) (*response.UserResponse, error) {
 	ret := _m.Called(ctx, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUser")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *request.CreateUpdateUserReq) error); ok {
+	var r0 *response.UserResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *request.CreateUpdateUserReq) (*response.UserResponse, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *request.CreateUpdateUserReq) *response.UserResponse); ok {
 		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.UserResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *request.CreateUpdateUserReq) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteUser provides a mock function with given fields: ctx, id
+func (_m *APIUsecase) DeleteUser(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -65,9 +95,9 @@ func (_m *APIUsecase) GetUser(ctx context.Context, filter request.UserFilter) (*
 	return r0, r1
 }
 
-// GetUserByID provides a mock function with given fields: ctx, id
-func (_m *APIUsecase) GetUserByID(ctx context.Context, id int64) (*response.UserResponse, error) {
-	ret := _m.Called(ctx, id)
+// GetUserByID provides a mock function with given fields: ctx, id, filter
+func (_m *APIUsecase) GetUserByID(ctx context.Context, id int64, filter request.UserDetailFilter) (*response.UserResponse, error) {
+	ret := _m.Called(ctx, id, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByID")
@@ -75,19 +105,19 @@ func (_m *APIUsecase) GetUserByID(ctx context.Context, id int64) (*response.User
 
 	var r0 *response.UserResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) (*response.UserResponse, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, request.UserDetailFilter) (*response.UserResponse, error)); ok {
+		return rf(ctx, id, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) *response.UserResponse); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, request.UserDetailFilter) *response.UserResponse); ok {
+		r0 = rf(ctx, id, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*response.UserResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, request.UserDetailFilter) error); ok {
+		r1 = rf(ctx, id, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -95,22 +125,52 @@ func (_m *APIUsecase) GetUserByID(ctx context.Context, id int64) (*response.User
 	return r0, r1
 }
 
+// RestoreUser provides a mock function with given fields: ctx, id
+func (_m *APIUsecase) RestoreUser(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateUser provides a mock function with given fields: ctx, id, _a2
-func (_m *APIUsecase) UpdateUser(ctx context.Context, id int64, _a2 *request.CreateUpdateUserReq) error {
+func (_m *APIUsecase) UpdateUser(ctx context.Context, id int64, _a2 *request.CreateUpdateUserReq) (*response.UserResponse, error) {
 	ret := _m.Called(ctx, id, _a2)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUser")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, *request.CreateUpdateUserReq) error); ok {
+	var r0 *response.UserResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *request.CreateUpdateUserReq) (*response.UserResponse, error)); ok {
+		return rf(ctx, id, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *request.CreateUpdateUserReq) *response.UserResponse); ok {
 		r0 = rf(ctx, id, _a2)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.UserResponse)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *request.CreateUpdateUserReq) error); ok {
+		r1 = rf(ctx, id, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewAPIUsecase creates a new instance of APIUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -1,11 +1,13 @@
 package usecase
 
 import (
+	"errors"
 	"time"
 
 	"github.com/raflynagachi/go-rest-api-starter/config"
 	repo "github.com/raflynagachi/go-rest-api-starter/internal/repository/definition"
 	uc "github.com/raflynagachi/go-rest-api-starter/internal/usecase/definition"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/cursor"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
 )
 
@@ -13,6 +15,7 @@ type APIUsecaseImpl struct {
 	cfg       *config.Config
 	appLogger *logger.Logger
 	repo      repo.SQLRepo
+	cursors   *cursor.Signer
 }
 
 func New(cfg *config.Config, log *logger.Logger, sqlRepo repo.SQLRepo) uc.APIUsecase {
@@ -20,9 +23,31 @@ func New(cfg *config.Config, log *logger.Logger, sqlRepo repo.SQLRepo) uc.APIUse
 		cfg:       cfg,
 		appLogger: log,
 		repo:      sqlRepo,
+		cursors:   newCursorSigner(cfg, log),
 	}
 }
 
+// newCursorSigner signs pagination cursors with cursor_key, or with a random key when it is unset
+func newCursorSigner(cfg *config.Config, log *logger.Logger) *cursor.Signer {
+	signer, err := cursor.NewSigner(cfg.CursorKey)
+	if err == nil {
+		return signer
+	}
+
+	log.Warn("cursor_key is not set, pagination cursors are only valid until restart and on this instance")
+	signer, err = newRandomSigner()
+	if err != nil {
+		log.Error("failed to create cursor key, cursor pagination is unavailable", logger.ErrAttr(err))
+	}
+	return signer
+}
+
 var (
-	getTimeNow = time.Now()
+	getTimeNow      = time.Now
+	newRandomSigner = cursor.NewRandomSigner
+
+	ErrMissingPrincipal  = errors.New("missing authenticated principal")
+	ErrCursorUnavailable = errors.New("cursor pagination is unavailable")
+	ErrCursorWithSort    = errors.New("cursor cannot be combined with sort")
+	ErrCursorFilter      = errors.New("cursor belongs to another filter")
 )
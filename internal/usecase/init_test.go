@@ -1,15 +1,18 @@
 package usecase
 
 import (
+	"errors"
 	"io"
 	"log"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/raflynagachi/go-rest-api-starter/config"
 	repo "github.com/raflynagachi/go-rest-api-starter/internal/repository/definition"
 	"github.com/raflynagachi/go-rest-api-starter/internal/repository/definition/mocks"
 	uc "github.com/raflynagachi/go-rest-api-starter/internal/usecase/definition"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/cursor"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
 )
 
@@ -17,15 +20,23 @@ var (
 	mockRepo   = new(mocks.SQLRepo)
 	mockCfg    = &config.Config{}
 	mockLogger = logger.NewLogger()
+	mockNow    = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
 )
 
 func TestMain(m *testing.M) {
 	log.SetOutput(io.Discard)
+	getTimeNow = func() time.Time { return mockNow }
 	m.Run()
 }
 
 func TestNew(t *testing.T) {
 	mockRepo := new(mocks.SQLRepo)
+	mockCursorCfg := &config.Config{CursorKey: "secret"}
+	mockSigner, _ := cursor.NewSigner("secret")
+	mockRandomSigner, _ := cursor.NewSigner("random")
+
+	originalRandomSigner := newRandomSigner
+	defer func() { newRandomSigner = originalRandomSigner }()
 
 	type args struct {
 		cfg       *config.Config
@@ -33,17 +44,53 @@ func TestNew(t *testing.T) {
 		appLogger *logger.Logger
 	}
 	tests := []struct {
-		name string
-		args args
-		want uc.APIUsecase
+		name  string
+		args  args
+		setup func()
+		want  uc.APIUsecase
 	}{
 		{
 			name: "success",
+			args: args{
+				cfg:       mockCursorCfg,
+				sqlRepo:   mockRepo,
+				appLogger: mockLogger,
+			},
+			setup: func() {},
+			want: &APIUsecaseImpl{
+				cfg:       mockCursorCfg,
+				repo:      mockRepo,
+				appLogger: mockLogger,
+				cursors:   mockSigner,
+			},
+		},
+		{
+			name: "success with random cursor key",
 			args: args{
 				cfg:       mockCfg,
 				sqlRepo:   mockRepo,
 				appLogger: mockLogger,
 			},
+			setup: func() {
+				newRandomSigner = func() (*cursor.Signer, error) { return mockRandomSigner, nil }
+			},
+			want: &APIUsecaseImpl{
+				cfg:       mockCfg,
+				repo:      mockRepo,
+				appLogger: mockLogger,
+				cursors:   mockRandomSigner,
+			},
+		},
+		{
+			name: "success without cursors when the random key fails",
+			args: args{
+				cfg:       mockCfg,
+				sqlRepo:   mockRepo,
+				appLogger: mockLogger,
+			},
+			setup: func() {
+				newRandomSigner = func() (*cursor.Signer, error) { return nil, errors.New("no entropy") }
+			},
 			want: &APIUsecaseImpl{
 				cfg:       mockCfg,
 				repo:      mockRepo,
@@ -53,6 +100,7 @@ func TestNew(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
 			if got := New(tt.args.cfg, tt.args.appLogger, tt.args.sqlRepo); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("New() = %v, want %v", got, tt.want)
 			}
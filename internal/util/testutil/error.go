@@ -4,9 +4,20 @@ import (
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/raflynagachi/go-rest-api-starter/pkg/database"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/response"
+	"github.com/stretchr/testify/mock"
 )
 
 var (
 	MockErr          = errors.New("mock error")
 	MockErrDuplicate = &pq.Error{Code: database.ERR_PQ_CODE_DUPLICATE}
 )
+
+// MatchError matches an error wrapping target, including through a response.ErrResponse,
+// e.g. the error a usecase returns after wrapping a repository error
+func MatchError(target error) interface{} {
+	return mock.MatchedBy(func(err error) bool {
+		_, cause := response.FindErrResponse(err)
+		return errors.Is(err, target) || errors.Is(cause, target)
+	})
+}
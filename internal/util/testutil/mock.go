@@ -1,17 +1,25 @@
 package testutil
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
+	"github.com/stretchr/testify/mock"
 )
 
 var (
 	sqlmockNew = func() (*sql.DB, sqlmock.Sqlmock, error) {
 		return sqlmock.New()
 	}
+
+	// TracedCtx matches a context carrying a span, e.g. the context a traced usecase passes to the repository
+	TracedCtx = mock.MatchedBy(func(ctx context.Context) bool {
+		return tracing.SpanFromContext(ctx) != nil
+	})
 )
 
 // InitMockDB create mock DB
@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migration scripts into the binary
+package migrations
+
+import "embed"
+
+// FS holds every {version}_{name}.{up|down}.sql script of this directory
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,82 @@
+// Package cursor encodes pagination positions into opaque tokens signed with HMAC-SHA256,
+// so clients can pass them back but can not forge or alter them.
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrEmptyKey      = errors.New("cursor key must not be empty")
+	ErrInvalidCursor = errors.New("cursor is invalid")
+
+	randRead = rand.Read
+)
+
+// Signer encodes and decodes cursors with a single key
+type Signer struct {
+	key []byte
+}
+
+// NewSigner creates a signer for key
+func NewSigner(key string) (*Signer, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+	return &Signer{key: []byte(key)}, nil
+}
+
+// NewRandomSigner creates a signer with a random key, its cursors are only valid for this process
+func NewRandomSigner() (*Signer, error) {
+	key := make([]byte, sha256.Size)
+	if _, err := randRead(key); err != nil {
+		return nil, errors.Wrap(err, "rand.Read")
+	}
+	return &Signer{key: key}, nil
+}
+
+// Encode returns the token of v as base64url(json(v)).base64url(signature)
+func (s *Signer) Encode(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Wrap(err, "json.Marshal")
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded)), nil
+}
+
+// Decode verifies token and stores its value in v
+func (s *Signer) Decode(token string, v interface{}) error {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidCursor
+	}
+
+	gotSignature, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(gotSignature, s.sign(encoded)) {
+		return ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+func (s *Signer) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,84 @@
+package cursor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type position struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+}
+
+func TestSigner(t *testing.T) {
+	signer, err := NewSigner("secret")
+	require.NoError(t, err)
+
+	want := position{CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 123456000, time.UTC), ID: 42}
+	token, err := signer.Encode(want)
+	require.NoError(t, err)
+
+	encoded, signature, _ := strings.Cut(token, ".")
+	otherSigner, err := NewSigner("other")
+	require.NoError(t, err)
+	forged, err := otherSigner.Encode(position{ID: 1})
+	require.NoError(t, err)
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name    string
+		signer  *Signer
+		token   string
+		want    position
+		wantErr error
+	}{
+		{name: "success", signer: signer, token: token, want: want},
+		{name: "failed due to other key", signer: otherSigner, token: token, wantErr: ErrInvalidCursor},
+		{name: "failed due to altered payload", signer: signer, token: forgedPayload + "." + signature, wantErr: ErrInvalidCursor},
+		{name: "failed due to missing signature", signer: signer, token: encoded, wantErr: ErrInvalidCursor},
+		{name: "failed due to invalid encoding", signer: signer, token: "!!." + signature, wantErr: ErrInvalidCursor},
+		{name: "failed due to empty token", signer: signer, token: "", wantErr: ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got position
+			err := tt.signer.Decode(tt.token, &got)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.CreatedAt.Equal(got.CreatedAt))
+			assert.Equal(t, tt.want.ID, got.ID)
+		})
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	_, err := NewSigner("")
+	assert.ErrorIs(t, err, ErrEmptyKey)
+}
+
+func TestNewRandomSigner(t *testing.T) {
+	first, err := NewRandomSigner()
+	require.NoError(t, err)
+	second, err := NewRandomSigner()
+	require.NoError(t, err)
+
+	token, err := first.Encode(position{ID: 1})
+	require.NoError(t, err)
+	assert.ErrorIs(t, second.Decode(token, &position{}), ErrInvalidCursor)
+
+	original := randRead
+	defer func() { randRead = original }()
+	randRead = func(b []byte) (int, error) { return 0, errors.New("no entropy") }
+
+	_, err = NewRandomSigner()
+	assert.Error(t, err)
+}
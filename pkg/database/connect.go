@@ -2,6 +2,9 @@ package database
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
@@ -14,18 +17,70 @@ var (
 )
 
 func ConnectDB(cfg *config.Database) (*sqlx.DB, error) {
-	connStr := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name,
-	)
-
-	db, err := sqlxConnect("postgres", connStr)
+	db, err := sqlxConnect("postgres", DSN(cfg))
 	if err != nil {
 		return nil, errors.Wrap(err, "sqlx.Connect")
 	}
 
-	db.SetMaxIdleConns(5)
-	db.SetMaxOpenConns(5)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime.Std())
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime.Std())
 
 	return db, nil
 }
+
+// DSN builds the key/value connection string, empty options are left to the driver defaults
+func DSN(cfg *config.Database) string {
+	params := []struct {
+		key   string
+		value string
+	}{
+		{"host", cfg.Host},
+		{"port", strconv.Itoa(cfg.Port)},
+		{"user", cfg.User},
+		{"password", cfg.Password},
+		{"dbname", cfg.Name},
+		{"sslmode", cfg.SSLMode},
+		{"sslrootcert", cfg.SSLRootCert},
+		{"sslcert", cfg.SSLCert},
+		{"sslkey", cfg.SSLKey},
+		{"connect_timeout", seconds(cfg.ConnectTimeout.Std())},
+		{"application_name", cfg.ApplicationName},
+		// unknown keys are sent by lib/pq as run-time parameters
+		{"statement_timeout", milliseconds(cfg.StatementTimeout.Std())},
+		{"search_path", cfg.SearchPath},
+	}
+
+	parts := make([]string, 0, len(params))
+	for _, param := range params {
+		if param.value == "" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", param.key, quoteDSNValue(param.value)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteDSNValue quotes values containing spaces, quotes or backslashes
+func quoteDSNValue(value string) string {
+	if !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// seconds rounds d up to whole seconds, since connect_timeout has a one second resolution
+func seconds(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
+
+func milliseconds(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(d.Milliseconds(), 10)
+}
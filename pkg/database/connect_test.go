@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
@@ -27,11 +28,13 @@ func TestConnectDB(t *testing.T) {
 		{
 			name: "success connect",
 			cfg: &config.Database{
-				Host:     "localhost",
-				Port:     5432,
-				User:     "user",
-				Password: "password",
-				Name:     "dbname",
+				Host:         "localhost",
+				Port:         5432,
+				User:         "user",
+				Password:     "password",
+				Name:         "dbname",
+				MaxOpenConns: 10,
+				MaxIdleConns: 2,
 			},
 			setup: func() {
 				sqlxConnect = func(driverName, dataSourceName string) (*sqlx.DB, error) {
@@ -75,7 +78,68 @@ func TestConnectDB(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, db)
+				assert.Equal(t, tt.cfg.MaxOpenConns, db.Stats().MaxOpenConnections)
 			}
 		})
 	}
 }
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Database
+		want string
+	}{
+		{
+			name: "success with required options",
+			cfg: &config.Database{
+				Host:     "localhost",
+				Port:     5432,
+				User:     "user",
+				Password: "password",
+				Name:     "dbname",
+				SSLMode:  "disable",
+			},
+			want: "host=localhost port=5432 user=user password=password dbname=dbname sslmode=disable",
+		},
+		{
+			name: "success with every option",
+			cfg: &config.Database{
+				Host:             "db.internal",
+				Port:             5433,
+				User:             "app",
+				Password:         "secret",
+				Name:             "app",
+				SSLMode:          "verify-full",
+				SSLRootCert:      "/etc/ssl/ca.pem",
+				SSLCert:          "/etc/ssl/client.pem",
+				SSLKey:           "/etc/ssl/client.key",
+				ConnectTimeout:   config.Duration(1500 * time.Millisecond),
+				ApplicationName:  "go-rest-api-starter",
+				StatementTimeout: config.Duration(30 * time.Second),
+				SearchPath:       "app,public",
+			},
+			want: "host=db.internal port=5433 user=app password=secret dbname=app sslmode=verify-full " +
+				"sslrootcert=/etc/ssl/ca.pem sslcert=/etc/ssl/client.pem sslkey=/etc/ssl/client.key " +
+				"connect_timeout=2 application_name=go-rest-api-starter statement_timeout=30000 search_path=app,public",
+		},
+		{
+			name: "success with quoted values",
+			cfg: &config.Database{
+				Host:       "localhost",
+				Port:       5432,
+				User:       "user",
+				Password:   `p@ss 'w\rd`,
+				Name:       "dbname",
+				SearchPath: "app, public",
+			},
+			want: `host=localhost port=5432 user=user password='p@ss \'w\\rd' dbname=dbname search_path='app, public'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DSN(tt.cfg))
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+)
+
+// DefaultTable is compatible with the schema table created by golang-migrate,
+// so databases migrated by the migrate/migrate docker image keep their state
+const DefaultTable = "schema_migrations"
+
+var (
+	ErrDirty          = errors.New("database is dirty, fix the schema manually then force a version")
+	ErrUnknownVersion = errors.New("unknown migration version")
+	ErrNoDownScript   = errors.New("migration has no down script")
+	ErrInvalidSteps   = errors.New("steps must be greater than zero")
+)
+
+// Status reports whether a migration has been applied
+type Status struct {
+	Version uint64
+	Name    string
+	Applied bool
+}
+
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+	table      string
+	lockID     int64
+	appLogger  *logger.Logger
+}
+
+type Option func(*Migrator)
+
+// WithTable sets the schema table name. The default is DefaultTable.
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// New creates a Migrator running the migrations found in fsys
+func New(db *sqlx.DB, fsys fs.FS, log *logger.Logger, opts ...Option) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, errors.Wrap(err, "New.Load")
+	}
+
+	m := &Migrator{
+		db:         db,
+		migrations: migrations,
+		table:      DefaultTable,
+		appLogger:  log,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.lockID = advisoryLockID(m.table)
+
+	return m, nil
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.Goto(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down reverts the last n applied migrations
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return ErrInvalidSteps
+	}
+
+	return m.withLock(ctx, func(conn *sqlx.Conn) error {
+		current, err := m.checkedVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		idx := m.indexOf(current) - steps
+		var target uint64
+		if idx >= 0 {
+			target = m.migrations[idx].Version
+		}
+
+		return m.migrate(ctx, conn, current, target)
+	})
+}
+
+// Goto migrates up or down to the given version, version 0 reverts every migration
+func (m *Migrator) Goto(ctx context.Context, version uint64) error {
+	if version != 0 && m.indexOf(version) < 0 {
+		return errors.Wrapf(ErrUnknownVersion, "version %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *sqlx.Conn) error {
+		current, err := m.checkedVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		return m.migrate(ctx, conn, current, version)
+	})
+}
+
+// Force sets the schema version and clears the dirty flag without running any migration
+func (m *Migrator) Force(ctx context.Context, version uint64) error {
+	if version != 0 && m.indexOf(version) < 0 {
+		return errors.Wrapf(ErrUnknownVersion, "version %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *sqlx.Conn) error {
+		tx, err := conn.BeginTxx(ctx, nil)
+		if err != nil {
+			return errors.Wrap(err, "Migrator.Force.BeginTxx")
+		}
+
+		if err := m.setVersion(ctx, tx, version); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "Migrator.Force.setVersion")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return errors.Wrap(err, "Migrator.Force.Commit")
+		}
+
+		m.appLogger.InfoContext(ctx, "migration version forced", logger.Uint64Attr("version", version))
+		return nil
+	})
+}
+
+// Version returns the current schema version, 0 means no migration has been applied
+func (m *Migrator) Version(ctx context.Context) (version uint64, dirty bool, err error) {
+	err = m.withLock(ctx, func(conn *sqlx.Conn) error {
+		version, dirty, err = m.version(ctx, conn)
+		return err
+	})
+	return version, dirty, err
+}
+
+// Status lists every known migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	current, _, err := m.Version(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Migrator.Status.Version")
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		statuses = append(statuses, Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: migration.Version <= current,
+		})
+	}
+	return statuses, nil
+}
+
+// migrate runs the migrations between current and target, each one in its own transaction
+func (m *Migrator) migrate(ctx context.Context, conn *sqlx.Conn, current, target uint64) error {
+	if target >= current {
+		for _, migration := range m.migrations {
+			if migration.Version <= current || migration.Version > target {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration.Up, migration.Version); err != nil {
+				return errors.Wrapf(err, "Migrator.migrate up %d_%s", migration.Version, migration.Name)
+			}
+			m.appLogger.InfoContext(ctx, "migration applied",
+				logger.Uint64Attr("version", migration.Version), logger.StringAttr("name", migration.Name))
+		}
+		return nil
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version > current || migration.Version <= target {
+			continue
+		}
+		if migration.Down == "" {
+			return errors.Wrapf(ErrNoDownScript, "version %d", migration.Version)
+		}
+
+		var previous uint64
+		if i > 0 {
+			previous = m.migrations[i-1].Version
+		}
+		if err := m.apply(ctx, conn, migration.Down, previous); err != nil {
+			return errors.Wrapf(err, "Migrator.migrate down %d_%s", migration.Version, migration.Name)
+		}
+		m.appLogger.InfoContext(ctx, "migration reverted",
+			logger.Uint64Attr("version", migration.Version), logger.StringAttr("name", migration.Name))
+	}
+	return nil
+}
+
+// apply runs the script and records the resulting version atomically
+func (m *Migrator) apply(ctx context.Context, conn *sqlx.Conn, script string, version uint64) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "BeginTxx")
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "ExecContext")
+	}
+
+	if err := m.setVersion(ctx, tx, version); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "setVersion")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "Commit")
+	}
+	return nil
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sqlx.Conn) error) (err error) {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Migrator.withLock.Connx")
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", m.lockID); err != nil {
+		return errors.Wrap(err, "Migrator.withLock.pg_advisory_lock")
+	}
+	defer func() {
+		// the lock is released with the session anyway, so an unlock failure only needs reporting
+		if _, unlockErr := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", m.lockID); unlockErr != nil && err == nil {
+			err = errors.Wrap(unlockErr, "Migrator.withLock.pg_advisory_unlock")
+		}
+	}()
+
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)", pq.QuoteIdentifier(m.table))
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return errors.Wrap(err, "Migrator.withLock.createTable")
+	}
+
+	return fn(conn)
+}
+
+// checkedVersion returns the current version, failing when it is dirty or unknown
+func (m *Migrator) checkedVersion(ctx context.Context, conn *sqlx.Conn) (uint64, error) {
+	current, dirty, err := m.version(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, errors.Wrapf(ErrDirty, "version %d", current)
+	}
+	if current != 0 && m.indexOf(current) < 0 {
+		return 0, errors.Wrapf(ErrUnknownVersion, "applied version %d", current)
+	}
+	return current, nil
+}
+
+func (m *Migrator) version(ctx context.Context, conn *sqlx.Conn) (uint64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+
+	query := fmt.Sprintf("SELECT version, dirty FROM %s LIMIT 1", pq.QuoteIdentifier(m.table))
+	err := conn.QueryRowxContext(ctx, query).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Migrator.version.Scan")
+	}
+
+	return uint64(version), dirty, nil
+}
+
+func (m *Migrator) setVersion(ctx context.Context, tx *sqlx.Tx, version uint64) error {
+	table := pq.QuoteIdentifier(m.table)
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", table)); err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (version, dirty) VALUES ($1, $2)", table), int64(version), false)
+	return err
+}
+
+// indexOf returns the index of version in the sorted migrations, -1 when not found
+func (m *Migrator) indexOf(version uint64) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// advisoryLockID derives a stable postgres advisory lock key from the schema table name
+func advisoryLockID(table string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(table))
+	return int64(h.Sum64())
+}
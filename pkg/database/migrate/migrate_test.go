@@ -0,0 +1,316 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mockLogger = logger.NewLogger()
+	mockErr    = errors.New("mock error")
+
+	mockFS = fstest.MapFS{
+		"1_init.up.sql":       {Data: []byte("CREATE TABLE users ();")},
+		"1_init.down.sql":     {Data: []byte("DROP TABLE users;")},
+		"2_add_name.up.sql":   {Data: []byte("ALTER TABLE users ADD name TEXT;")},
+		"2_add_name.down.sql": {Data: []byte("ALTER TABLE users DROP name;")},
+		"3_seed.up.sql":       {Data: []byte("INSERT INTO users DEFAULT VALUES;")},
+	}
+)
+
+func newMockMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	db, mockSql, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	m, err := New(sqlx.NewDb(db, "sqlmock"), mockFS, mockLogger)
+	require.NoError(t, err)
+	return m, mockSql
+}
+
+func expectLock(mockSql sqlmock.Sqlmock, version int64, dirty bool) {
+	mockSql.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mockSql.ExpectExec("CREATE TABLE IF NOT EXISTS \"schema_migrations\"").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := mockSql.NewRows([]string{"version", "dirty"})
+	if version > 0 {
+		rows.AddRow(version, dirty)
+	}
+	mockSql.ExpectQuery("SELECT version, dirty FROM \"schema_migrations\"").WillReturnRows(rows)
+}
+
+func expectUnlock(mockSql sqlmock.Sqlmock) {
+	mockSql.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectApply(mockSql sqlmock.Sqlmock, script string, version int64) {
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec(regexp.QuoteMeta(script)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mockSql.ExpectExec("DELETE FROM \"schema_migrations\"").WillReturnResult(sqlmock.NewResult(0, 1))
+	if version > 0 {
+		mockSql.ExpectExec("INSERT INTO \"schema_migrations\"").WithArgs(version, false).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mockSql.ExpectCommit()
+}
+
+func TestNew(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	m, err := New(sqlx.NewDb(db, "sqlmock"), mockFS, mockLogger, WithTable("app_migrations"))
+	require.NoError(t, err)
+	assert.Equal(t, "app_migrations", m.table)
+	assert.Equal(t, advisoryLockID("app_migrations"), m.lockID)
+	assert.Len(t, m.migrations, 3)
+
+	_, err = New(sqlx.NewDb(db, "sqlmock"), fstest.MapFS{"init.sql": {}}, mockLogger)
+	assert.Error(t, err)
+}
+
+func TestMigrator_Up(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(mockSql sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "success apply every migration",
+			setup: func(mockSql sqlmock.Sqlmock) {
+				expectLock(mockSql, 0, false)
+				expectApply(mockSql, "CREATE TABLE users ();", 1)
+				expectApply(mockSql, "ALTER TABLE users ADD name TEXT;", 2)
+				expectApply(mockSql, "INSERT INTO users DEFAULT VALUES;", 3)
+				expectUnlock(mockSql)
+			},
+		},
+		{
+			name: "success apply pending migrations only",
+			setup: func(mockSql sqlmock.Sqlmock) {
+				expectLock(mockSql, 2, false)
+				expectApply(mockSql, "INSERT INTO users DEFAULT VALUES;", 3)
+				expectUnlock(mockSql)
+			},
+		},
+		{
+			name: "success nothing to apply",
+			setup: func(mockSql sqlmock.Sqlmock) {
+				expectLock(mockSql, 3, false)
+				expectUnlock(mockSql)
+			},
+		},
+		{
+			name: "failed due to dirty database",
+			setup: func(mockSql sqlmock.Sqlmock) {
+				expectLock(mockSql, 2, true)
+				expectUnlock(mockSql)
+			},
+			wantErr: ErrDirty,
+		},
+		{
+			name: "failed due to unknown applied version",
+			setup: func(mockSql sqlmock.Sqlmock) {
+				expectLock(mockSql, 99, false)
+				expectUnlock(mockSql)
+			},
+			wantErr: ErrUnknownVersion,
+		},
+		{
+			name: "failed due to script error rolls back",
+			setup: func(mockSql sqlmock.Sqlmock) {
+				expectLock(mockSql, 1, false)
+				mockSql.ExpectBegin()
+				mockSql.ExpectExec(regexp.QuoteMeta("ALTER TABLE users ADD name TEXT;")).WillReturnError(mockErr)
+				mockSql.ExpectRollback()
+				expectUnlock(mockSql)
+			},
+			wantErr: mockErr,
+		},
+		{
+			name: "failed due to lock error",
+			setup: func(mockSql sqlmock.Sqlmock) {
+				mockSql.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WillReturnError(mockErr)
+			},
+			wantErr: mockErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mockSql := newMockMigrator(t)
+			tt.setup(mockSql)
+
+			err := m.Up(context.Background())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockSql.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   int
+		setup   func(mockSql sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name:  "success revert one step",
+			steps: 1,
+			setup: func(mockSql sqlmock.Sqlmock) {
+				expectLock(mockSql, 2, false)
+				expectApply(mockSql, "ALTER TABLE users DROP name;", 1)
+				expectUnlock(mockSql)
+			},
+		},
+		{
+			name:  "success revert more steps than applied",
+			steps: 5,
+			setup: func(mockSql sqlmock.Sqlmock) {
+				expectLock(mockSql, 2, false)
+				expectApply(mockSql, "ALTER TABLE users DROP name;", 1)
+				expectApply(mockSql, "DROP TABLE users;", 0)
+				expectUnlock(mockSql)
+			},
+		},
+		{
+			name:  "failed due to missing down script",
+			steps: 1,
+			setup: func(mockSql sqlmock.Sqlmock) {
+				expectLock(mockSql, 3, false)
+				expectUnlock(mockSql)
+			},
+			wantErr: ErrNoDownScript,
+		},
+		{
+			name:    "failed due to invalid steps",
+			steps:   0,
+			setup:   func(mockSql sqlmock.Sqlmock) {},
+			wantErr: ErrInvalidSteps,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mockSql := newMockMigrator(t)
+			tt.setup(mockSql)
+
+			err := m.Down(context.Background(), tt.steps)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockSql.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Goto(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uint64
+		setup   func(mockSql sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name:    "success migrate up to version",
+			version: 2,
+			setup: func(mockSql sqlmock.Sqlmock) {
+				expectLock(mockSql, 0, false)
+				expectApply(mockSql, "CREATE TABLE users ();", 1)
+				expectApply(mockSql, "ALTER TABLE users ADD name TEXT;", 2)
+				expectUnlock(mockSql)
+			},
+		},
+		{
+			name:    "success migrate down to zero",
+			version: 0,
+			setup: func(mockSql sqlmock.Sqlmock) {
+				expectLock(mockSql, 2, false)
+				expectApply(mockSql, "ALTER TABLE users DROP name;", 1)
+				expectApply(mockSql, "DROP TABLE users;", 0)
+				expectUnlock(mockSql)
+			},
+		},
+		{
+			name:    "failed due to unknown version",
+			version: 42,
+			setup:   func(mockSql sqlmock.Sqlmock) {},
+			wantErr: ErrUnknownVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mockSql := newMockMigrator(t)
+			tt.setup(mockSql)
+
+			err := m.Goto(context.Background(), tt.version)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockSql.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Force(t *testing.T) {
+	t.Run("success clear dirty version", func(t *testing.T) {
+		m, mockSql := newMockMigrator(t)
+		mockSql.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mockSql.ExpectExec("CREATE TABLE IF NOT EXISTS").WillReturnResult(sqlmock.NewResult(0, 0))
+		mockSql.ExpectBegin()
+		mockSql.ExpectExec("DELETE FROM \"schema_migrations\"").WillReturnResult(sqlmock.NewResult(0, 1))
+		mockSql.ExpectExec("INSERT INTO \"schema_migrations\"").WithArgs(int64(1), false).WillReturnResult(sqlmock.NewResult(0, 1))
+		mockSql.ExpectCommit()
+		expectUnlock(mockSql)
+
+		assert.NoError(t, m.Force(context.Background(), 1))
+		assert.NoError(t, mockSql.ExpectationsWereMet())
+	})
+
+	t.Run("failed due to unknown version", func(t *testing.T) {
+		m, _ := newMockMigrator(t)
+		assert.ErrorIs(t, m.Force(context.Background(), 42), ErrUnknownVersion)
+	})
+}
+
+func TestMigrator_Status(t *testing.T) {
+	m, mockSql := newMockMigrator(t)
+	expectLock(mockSql, 2, false)
+	expectUnlock(mockSql)
+
+	got, err := m.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Status{
+		{Version: 1, Name: "init", Applied: true},
+		{Version: 2, Name: "add_name", Applied: true},
+		{Version: 3, Name: "seed", Applied: false},
+	}, got)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+
+	mockSql.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mockSql.ExpectExec("CREATE TABLE IF NOT EXISTS").WillReturnResult(sqlmock.NewResult(0, 0))
+	mockSql.ExpectQuery("SELECT version, dirty").WillReturnError(sql.ErrConnDone)
+	expectUnlock(mockSql)
+
+	_, err = m.Status(context.Background())
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+}
@@ -0,0 +1,99 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	upSuffix   = ".up.sql"
+	downSuffix = ".down.sql"
+)
+
+// Migration is a single versioned schema change read from {version}_{name}.{up|down}.sql
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load reads every migration in the root of fsys, sorted by version ascending
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "Load.ReadDir")
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		version, name, up, err := parseFilename(entry.Name())
+		if err != nil {
+			return nil, errors.Wrap(err, "Load.parseFilename")
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, errors.Wrap(err, "Load.ReadFile")
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, name)
+		}
+
+		if up {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// parseFilename parses {version}_{name}.up.sql and {version}_{name}.down.sql
+func parseFilename(filename string) (version uint64, name string, up bool, err error) {
+	var base string
+	switch {
+	case strings.HasSuffix(filename, upSuffix):
+		base, up = strings.TrimSuffix(filename, upSuffix), true
+	case strings.HasSuffix(filename, downSuffix):
+		base = strings.TrimSuffix(filename, downSuffix)
+	default:
+		return 0, "", false, fmt.Errorf("invalid migration filename %q: missing .up.sql or .down.sql suffix", filename)
+	}
+
+	versionStr, name, _ := strings.Cut(base, "_")
+	version, err = strconv.ParseUint(versionStr, 10, 64)
+	if err != nil || version == 0 {
+		return 0, "", false, fmt.Errorf("invalid migration filename %q: version must be a positive number", filename)
+	}
+
+	return version, name, up, nil
+}
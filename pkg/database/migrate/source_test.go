@@ -0,0 +1,104 @@
+package migrate
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr bool
+	}{
+		{
+			name: "success sorted by version",
+			fsys: fstest.MapFS{
+				"20240801_add_name.up.sql":   {Data: []byte("ALTER TABLE users ADD name TEXT;")},
+				"20240801_add_name.down.sql": {Data: []byte("ALTER TABLE users DROP name;")},
+				"20240730_init.up.sql":       {Data: []byte("CREATE TABLE users ();")},
+				"20240730_init.down.sql":     {Data: []byte("DROP TABLE users;")},
+				"README.md":                  {Data: []byte("ignored")},
+				"seed":                       {Mode: fs.ModeDir},
+			},
+			want: []Migration{
+				{Version: 20240730, Name: "init", Up: "CREATE TABLE users ();", Down: "DROP TABLE users;"},
+				{Version: 20240801, Name: "add_name", Up: "ALTER TABLE users ADD name TEXT;", Down: "ALTER TABLE users DROP name;"},
+			},
+		},
+		{
+			name: "success without down script",
+			fsys: fstest.MapFS{
+				"1_init.up.sql": {Data: []byte("CREATE TABLE users ();")},
+			},
+			want: []Migration{
+				{Version: 1, Name: "init", Up: "CREATE TABLE users ();"},
+			},
+		},
+		{
+			name: "failed due to missing up script",
+			fsys: fstest.MapFS{
+				"1_init.down.sql": {Data: []byte("DROP TABLE users;")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed due to conflicting names",
+			fsys: fstest.MapFS{
+				"1_init.up.sql":    {Data: []byte("CREATE TABLE users ();")},
+				"1_other.down.sql": {Data: []byte("DROP TABLE users;")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed due to invalid filename",
+			fsys: fstest.MapFS{
+				"init.sql": {Data: []byte("CREATE TABLE users ();")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		filename    string
+		wantVersion uint64
+		wantName    string
+		wantUp      bool
+		wantErr     bool
+	}{
+		{filename: "20240730_init.up.sql", wantVersion: 20240730, wantName: "init", wantUp: true},
+		{filename: "20240730_create_users.down.sql", wantVersion: 20240730, wantName: "create_users"},
+		{filename: "3.up.sql", wantVersion: 3, wantUp: true},
+		{filename: "0_init.up.sql", wantErr: true},
+		{filename: "v1_init.up.sql", wantErr: true},
+		{filename: "1_init.sql", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			version, name, up, err := parseFilename(tt.filename)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFilename() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantVersion, version)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantUp, up)
+		})
+	}
+}
@@ -29,6 +29,15 @@ func QueryPagination(page, limit int) (string, error) {
 	return query, nil
 }
 
+// QueryLimit creates a SQL LIMIT clause for keyset pagination.
+// It validates that the limit is > 0.
+func QueryLimit(limit int) (string, error) {
+	if limit <= 0 {
+		return "", fmt.Errorf("limit must be > 0")
+	}
+	return fmt.Sprintf("LIMIT %d", limit), nil
+}
+
 // BatchSelectContext executes the provided query in batches and collects the results.
 func BatchSelectContext(ctx context.Context, db *sqlx.DB, query string, ids []int64, maxBatch int, dest interface{}) error {
 	destValue := reflect.ValueOf(dest)
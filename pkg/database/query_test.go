@@ -41,6 +41,30 @@ func TestQueryPagination(t *testing.T) {
 	}
 }
 
+func TestQueryLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		want    string
+		wantErr bool
+	}{
+		{"success", 10, "LIMIT 10", false},
+		{"failed due to zero limit", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QueryLimit(tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("QueryLimit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestBatchSelectContext(t *testing.T) {
 	type User struct {
 		ID   int64  `db:"id"`
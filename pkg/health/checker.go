@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// Pinger is implemented by *sql.DB and *sqlx.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// PingChecker checks a database connection with PingContext
+func PingChecker(pinger Pinger) Checker {
+	return CheckFunc(pinger.PingContext)
+}
+
+// TCPChecker checks that addr accepts TCP connections, e.g. a Redis server
+func TCPChecker(addr string) Checker {
+	return CheckFunc(func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
+
+// DiskChecker checks that the filesystem of path has at least minFreeBytes available
+func DiskChecker(path string, minFreeBytes uint64) Checker {
+	return CheckFunc(func(ctx context.Context) error {
+		free, err := diskFree(path)
+		if err != nil {
+			return err
+		}
+		if free < minFreeBytes {
+			return fmt.Errorf("%s has %d bytes free, want at least %d", path, free, minFreeBytes)
+		}
+		return nil
+	})
+}
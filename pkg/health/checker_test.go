@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPingChecker(t *testing.T) {
+	db, mockSql, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mockSql.ExpectPing()
+	assert.NoError(t, PingChecker(db).Check(context.Background()))
+
+	mockSql.ExpectPing().WillReturnError(mockErr)
+	assert.ErrorIs(t, PingChecker(db).Check(context.Background()), mockErr)
+}
+
+func TestTCPChecker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+
+	assert.NoError(t, TCPChecker(addr).Check(context.Background()))
+
+	listener.Close()
+	assert.Error(t, TCPChecker(addr).Check(context.Background()))
+}
+
+func TestDiskChecker(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, DiskChecker(dir, 1).Check(context.Background()))
+	assert.Error(t, DiskChecker(dir, math.MaxUint64).Check(context.Background()))
+	assert.Error(t, DiskChecker(dir+"/missing", 1).Check(context.Background()))
+}
@@ -0,0 +1,220 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+
+	DefaultTimeout = 2 * time.Second
+)
+
+var (
+	ErrShuttingDown = errors.New("server is shutting down")
+
+	timeNow = time.Now
+)
+
+// Checker reports the health of a single dependency, a nil error means healthy
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckFunc adapts a function to a Checker
+type CheckFunc func(ctx context.Context) error
+
+func (f CheckFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// Report aggregates the results of every check of a probe
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// Healthy reports whether every check passed
+func (r Report) Healthy() bool {
+	return r.Status == StatusOK
+}
+
+type check struct {
+	name     string
+	checker  Checker
+	timeout  time.Duration
+	cacheTTL time.Duration
+	liveness bool
+
+	mu     sync.Mutex
+	result *Result
+}
+
+type CheckOption func(*check)
+
+// WithTimeout sets the check timeout. The default is the registry timeout.
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(c *check) {
+		c.timeout = timeout
+	}
+}
+
+// WithCacheTTL sets how long a result is reused. The default is the registry cache TTL.
+func WithCacheTTL(ttl time.Duration) CheckOption {
+	return func(c *check) {
+		c.cacheTTL = ttl
+	}
+}
+
+// WithLiveness includes the check in the liveness probe. By default checks only affect readiness.
+func WithLiveness() CheckOption {
+	return func(c *check) {
+		c.liveness = true
+	}
+}
+
+type Registry struct {
+	timeout      time.Duration
+	cacheTTL     time.Duration
+	shuttingDown atomic.Bool
+
+	mu     sync.RWMutex
+	checks []*check
+}
+
+// NewRegistry creates an empty registry.
+// A zero timeout falls back to DefaultTimeout, a zero cacheTTL disables caching.
+func NewRegistry(timeout, cacheTTL time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if cacheTTL < 0 {
+		cacheTTL = 0
+	}
+
+	return &Registry{
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// Register adds a named check, registering the same name again replaces it
+func (r *Registry) Register(name string, checker Checker, opts ...CheckOption) {
+	c := &check{
+		name:     name,
+		checker:  checker,
+		timeout:  r.timeout,
+		cacheTTL: r.cacheTTL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.checks {
+		if r.checks[i].name == name {
+			r.checks[i] = c
+			return
+		}
+	}
+	r.checks = append(r.checks, c)
+}
+
+// SetShuttingDown makes readiness fail from now on, so load balancers stop sending traffic
+func (r *Registry) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// Liveness runs the checks registered WithLiveness
+func (r *Registry) Liveness(ctx context.Context) Report {
+	return r.run(ctx, true)
+}
+
+// Readiness runs every check and fails while shutting down
+func (r *Registry) Readiness(ctx context.Context) Report {
+	if r.shuttingDown.Load() {
+		return Report{
+			Status: StatusFail,
+			Checks: map[string]Result{
+				"shutdown": {Status: StatusFail, Error: ErrShuttingDown.Error(), CheckedAt: timeNow()},
+			},
+		}
+	}
+	return r.run(ctx, false)
+}
+
+// run executes the checks concurrently
+func (r *Registry) run(ctx context.Context, livenessOnly bool) Report {
+	r.mu.RLock()
+	checks := make([]*check, 0, len(r.checks))
+	for _, c := range r.checks {
+		if !livenessOnly || c.liveness {
+			checks = append(checks, c)
+		}
+	}
+	r.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			results[i] = c.run(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// run returns the cached result when it is still fresh, otherwise runs the checker with its timeout.
+// The lock also collapses concurrent probes into a single call to the dependency.
+func (c *check) run(ctx context.Context) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := timeNow()
+	if c.result != nil && now.Sub(c.result.CheckedAt) < c.cacheTTL {
+		return *c.result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.checker.Check(ctx)
+	result := Result{
+		Status:     StatusOK,
+		DurationMs: timeNow().Sub(now).Milliseconds(),
+		CheckedAt:  now,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+
+	c.result = &result
+	return result
+}
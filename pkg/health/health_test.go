@@ -0,0 +1,142 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	mockErr = errors.New("mock error")
+)
+
+func countingChecker(calls *int32, err error) Checker {
+	return CheckFunc(func(ctx context.Context) error {
+		atomic.AddInt32(calls, 1)
+		return err
+	})
+}
+
+func TestRegistry_Readiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(r *Registry)
+		wantStatus string
+		wantChecks map[string]string
+	}{
+		{
+			name:       "success without checks",
+			setup:      func(r *Registry) {},
+			wantStatus: StatusOK,
+			wantChecks: map[string]string{},
+		},
+		{
+			name: "success with every check passing",
+			setup: func(r *Registry) {
+				r.Register("database", CheckFunc(func(ctx context.Context) error { return nil }))
+				r.Register("redis", CheckFunc(func(ctx context.Context) error { return nil }), WithLiveness())
+			},
+			wantStatus: StatusOK,
+			wantChecks: map[string]string{"database": StatusOK, "redis": StatusOK},
+		},
+		{
+			name: "failed due to a failing check",
+			setup: func(r *Registry) {
+				r.Register("database", CheckFunc(func(ctx context.Context) error { return mockErr }))
+				r.Register("redis", CheckFunc(func(ctx context.Context) error { return nil }))
+			},
+			wantStatus: StatusFail,
+			wantChecks: map[string]string{"database": StatusFail, "redis": StatusOK},
+		},
+		{
+			name: "failed due to check timeout",
+			setup: func(r *Registry) {
+				r.Register("slow", CheckFunc(func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}), WithTimeout(10*time.Millisecond))
+			},
+			wantStatus: StatusFail,
+			wantChecks: map[string]string{"slow": StatusFail},
+		},
+		{
+			name: "failed due to shutting down",
+			setup: func(r *Registry) {
+				r.Register("database", CheckFunc(func(ctx context.Context) error { return nil }))
+				r.SetShuttingDown()
+			},
+			wantStatus: StatusFail,
+			wantChecks: map[string]string{"shutdown": StatusFail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry(time.Second, 0)
+			tt.setup(r)
+
+			report := r.Readiness(context.Background())
+
+			assert.Equal(t, tt.wantStatus, report.Status)
+			assert.Equal(t, tt.wantStatus == StatusOK, report.Healthy())
+			gotChecks := map[string]string{}
+			for name, result := range report.Checks {
+				gotChecks[name] = result.Status
+				if result.Status == StatusFail {
+					assert.NotEmpty(t, result.Error)
+				}
+			}
+			assert.Equal(t, tt.wantChecks, gotChecks)
+		})
+	}
+}
+
+func TestRegistry_Liveness(t *testing.T) {
+	r := NewRegistry(time.Second, 0)
+	r.Register("database", CheckFunc(func(ctx context.Context) error { return mockErr }))
+	r.Register("deadlock", CheckFunc(func(ctx context.Context) error { return nil }), WithLiveness())
+	r.SetShuttingDown()
+
+	report := r.Liveness(context.Background())
+
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Len(t, report.Checks, 1)
+	assert.Contains(t, report.Checks, "deadlock")
+}
+
+func TestRegistry_Cache(t *testing.T) {
+	tmpTimeNow := timeNow
+	defer func() {
+		timeNow = tmpTimeNow
+	}()
+	now := time.Date(2024, 7, 30, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	var calls int32
+	r := NewRegistry(time.Second, 5*time.Second)
+	r.Register("database", countingChecker(&calls, nil))
+	r.Register("uncached", countingChecker(&calls, nil), WithCacheTTL(0))
+
+	r.Readiness(context.Background())
+	r.Readiness(context.Background())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls), "database should be cached, uncached should run twice")
+
+	now = now.Add(5 * time.Second)
+	r.Readiness(context.Background())
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls), "database cache should have expired")
+}
+
+func TestRegistry_RegisterReplaces(t *testing.T) {
+	r := NewRegistry(0, -1)
+	r.Register("database", CheckFunc(func(ctx context.Context) error { return mockErr }))
+	r.Register("database", CheckFunc(func(ctx context.Context) error { return nil }))
+
+	assert.Len(t, r.checks, 1)
+	assert.Equal(t, DefaultTimeout, r.timeout)
+	assert.Equal(t, time.Duration(0), r.cacheTTL)
+	assert.True(t, r.Readiness(context.Background()).Healthy())
+}
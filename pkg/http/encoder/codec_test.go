@@ -0,0 +1,111 @@
+package encoder
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/guregu/null/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type codecItem struct {
+	ID      int64       `json:"id"`
+	Email   string      `json:"email"`
+	Deleted null.String `json:"deleted_by"`
+	Tags    []string    `json:"tags,omitempty"`
+	Owner   *codecOwner `json:"owner,omitempty"`
+}
+
+type codecOwner struct {
+	Name string `json:"name"`
+}
+
+func TestXML_Encode(t *testing.T) {
+	var buf bytes.Buffer
+	err := XML.Encode(&buf, map[string]interface{}{
+		"data":     []codecItem{{ID: 1, Email: "a&b@mail.com", Tags: []string{"x"}}},
+		"id[in]":   true,
+		"trace_id": nil,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<response><data><item><id>1</id><email>a&amp;b@mail.com</email><deleted_by></deleted_by><tags><item>x</item></tags></item></data>`+
+		`<item key="id[in]">true</item><trace_id></trace_id></response>`, buf.String())
+}
+
+func TestMessagePack_Encode(t *testing.T) {
+	value := struct {
+		A int           `json:"a"`
+		B []interface{} `json:"b"`
+		C string        `json:"c"`
+		D int           `json:"d"`
+		E int           `json:"e"`
+		F float64       `json:"f"`
+		G int64         `json:"g"`
+	}{A: 1, B: []interface{}{true, nil}, C: "hi", D: -5, E: 300, F: 1.5, G: -1 << 40}
+
+	var buf bytes.Buffer
+	require.NoError(t, MessagePack.Encode(&buf, value))
+
+	assert.Equal(t, []byte{
+		0x87,
+		0xa1, 'a', 0x01,
+		0xa1, 'b', 0x92, 0xc3, 0xc0,
+		0xa1, 'c', 0xa2, 'h', 'i',
+		0xa1, 'd', 0xfb,
+		0xa1, 'e', 0xd1, 0x01, 0x2c,
+		0xa1, 'f', 0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0,
+		0xa1, 'g', 0xd3, 0xff, 0xff, 0xff, 0, 0, 0, 0, 0,
+	}, buf.Bytes())
+
+	buf.Reset()
+	require.NoError(t, MessagePack.Encode(&buf, strings.Repeat("x", 40)))
+	assert.Equal(t, []byte{0xd9, 40}, buf.Bytes()[:2])
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWriteMsgpackHeader_Error(t *testing.T) {
+	for _, n := range []int{1, 40, 300, 70000} {
+		// a failed flush makes every later write of the bufio.Writer fail
+		bw := bufio.NewWriter(failingWriter{})
+		_ = bw.WriteByte(0)
+		require.Error(t, bw.Flush())
+
+		assert.EqualError(t, writeMsgpackHeader(bw, n, 0xa0, 32, 0xd9, 0xda, 0xdb), "write failed", "n = %d", n)
+	}
+}
+
+func TestCSV_Encode(t *testing.T) {
+	response := map[string]interface{}{
+		"code": 200,
+		"data": map[string]interface{}{
+			"data": []codecItem{
+				{ID: 1, Email: "a@mail.com", Tags: []string{"x", "y"}},
+				{ID: 2, Email: "b,c@mail.com", Deleted: null.StringFrom("admin"), Owner: &codecOwner{Name: "Bob"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, CSV.Encode(&buf, response))
+	assert.Equal(t, "id,email,deleted_by,tags,owner.name\n"+
+		"1,a@mail.com,,\"[\"\"x\"\",\"\"y\"\"]\",\n"+
+		"2,\"b,c@mail.com\",admin,,Bob\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, CSV.Encode(&buf, []codecItem{}))
+	assert.Empty(t, buf.String())
+
+	assert.ErrorIs(t, CSV.Encode(&buf, codecItem{ID: 1}), ErrUnsupportedValue)
+	assert.ErrorIs(t, CSV.Encode(&buf, []int{1, 2}), ErrUnsupportedValue)
+}
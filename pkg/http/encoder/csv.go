@@ -0,0 +1,142 @@
+package encoder
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// CSV encodes lists of objects, the list is looked up through nested "data" keys
+// so a response of a list endpoint gives one row per element. Nested objects become
+// dotted columns, nested arrays are written as JSON and nulls as empty cells.
+var CSV Encoder = csvEncoder{}
+
+const csvListKey = "data"
+
+type csvEncoder struct{}
+
+func (csvEncoder) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (csvEncoder) Encode(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+
+	list, ok := findList(tree)
+	if !ok {
+		return ErrUnsupportedValue
+	}
+
+	rows := make([]map[string]string, 0, len(list))
+	columns := []string{}
+	seen := map[string]bool{}
+	for _, item := range list {
+		obj, ok := item.(object)
+		if !ok {
+			return ErrUnsupportedValue
+		}
+
+		row := map[string]string{}
+		keys, err := flattenRow(row, nil, "", obj)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	cw := csv.NewWriter(w)
+	if len(columns) > 0 {
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, column := range columns {
+			record[i] = row[column]
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// findList descends through the "data" keys until it reaches a list
+func findList(tree interface{}) ([]interface{}, bool) {
+	for {
+		switch value := tree.(type) {
+		case []interface{}:
+			return value, true
+		case object:
+			data, ok := value.get(csvListKey)
+			if !ok {
+				return nil, false
+			}
+			tree = data
+		default:
+			return nil, false
+		}
+	}
+}
+
+// flattenRow stores the cells of obj in row and appends their columns to keys
+func flattenRow(row map[string]string, keys []string, prefix string, obj object) ([]string, error) {
+	for _, m := range obj {
+		key := prefix + m.key
+		if nested, ok := m.value.(object); ok {
+			var err error
+			keys, err = flattenRow(row, keys, key+".", nested)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		keys = append(keys, key)
+		switch value := m.value.(type) {
+		case nil:
+			row[key] = ""
+		case string, json.Number, bool:
+			row[key] = fmt.Sprint(value)
+		default:
+			data, err := json.Marshal(toJSONValue(value))
+			if err != nil {
+				return nil, err
+			}
+			row[key] = string(data)
+		}
+	}
+	return keys, nil
+}
+
+// toJSONValue turns a tree back into values encoding/json writes with their key order lost
+func toJSONValue(tree interface{}) interface{} {
+	switch value := tree.(type) {
+	case object:
+		m := make(map[string]interface{}, len(value))
+		for _, member := range value {
+			m[member.key] = toJSONValue(member.value)
+		}
+		return m
+	case []interface{}:
+		arr := make([]interface{}, len(value))
+		for i, item := range value {
+			arr[i] = toJSONValue(item)
+		}
+		return arr
+	default:
+		return value
+	}
+}
@@ -1,24 +1,160 @@
 package encoder
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
+// DefaultMaxBodyBytes is the body limit of DecodeJson unless SetMaxBodyBytes changes it
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+var (
+	maxBodyBytes = DefaultMaxBodyBytes
+
+	ErrBodyTooLarge         = errors.New("request body too large")
+	ErrUnsupportedMediaType = errors.New("unsupported media type, expected application/json")
+	ErrEmptyBody            = errors.New("request body must not be empty")
+	ErrMalformedJSON        = errors.New("request body contains malformed JSON")
+	ErrInvalidFieldType     = errors.New("request body contains an invalid value for field")
+	ErrUnknownField         = errors.New("request body contains unknown field")
+	ErrMultipleValues       = errors.New("request body must contain a single JSON value")
+)
+
+// SetMaxBodyBytes sets the body limit of DecodeJson for the whole process.
+// The limit is not synchronized, so it can not change while requests are decoded.
+func SetMaxBodyBytes(n int64) {
+	maxBodyBytes = n
+}
+
+// DecodeError is a request body rejected by DecodeJson, Status is the HTTP status to answer with
+type DecodeError struct {
+	Status int
+	Err    error
+	Field  string
+	Offset int64
+	Detail string
+}
+
+func (e *DecodeError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Err.Error())
+	if e.Field != "" {
+		fmt.Fprintf(&b, " %q", e.Field)
+	}
+	if e.Offset > 0 {
+		fmt.Fprintf(&b, " at offset %d", e.Offset)
+	}
+	if e.Detail != "" {
+		b.WriteString(": " + e.Detail)
+	}
+	return b.String()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// JSON encodes values with encoding/json
+var JSON Encoder = jsonEncoder{}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (jsonEncoder) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// EncodeJson writes data as JSON, keeping the Content-Type if it has been set by the caller
 func EncodeJson(w http.ResponseWriter, data interface{}) error {
-	w.Header().Set("Content-Type", "application/json")
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
 	return json.NewEncoder(w).Encode(data)
 }
 
+// DecodeJson strictly decodes a single JSON value of at most the body limit into data,
+// rejecting other content types and unknown fields with a *DecodeError
 func DecodeJson(r *http.Request, data interface{}) error {
-	var bodyBytes []byte
-	if r.Body != nil {
-		bodyBytes, _ = io.ReadAll(r.Body)
-		r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	if err := checkContentType(r.Header.Get("Content-Type")); err != nil {
+		return err
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return &DecodeError{Status: http.StatusBadRequest, Err: ErrEmptyBody}
+	}
+
+	body := http.MaxBytesReader(nil, r.Body, maxBodyBytes)
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(data); err != nil {
+		return newDecodeError(err, dec.InputOffset())
 	}
 
-	body := io.NopCloser(bytes.NewBuffer(bodyBytes))
-	return json.NewDecoder(body).Decode(data)
+	// anything but whitespace after the value is a second value or garbage
+	_, err := dec.Token()
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil
+	case errors.As(err, &maxBytesErr):
+		return newDecodeError(err, dec.InputOffset())
+	default:
+		return &DecodeError{Status: http.StatusBadRequest, Err: ErrMultipleValues, Offset: dec.InputOffset()}
+	}
+}
+
+// checkContentType accepts application/json and the +json media types
+func checkContentType(contentType string) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+	return &DecodeError{Status: http.StatusUnsupportedMediaType, Err: ErrUnsupportedMediaType, Detail: strconv.Quote(contentType)}
+}
+
+// newDecodeError maps the errors of json.Decoder to a *DecodeError
+func newDecodeError(err error, offset int64) *DecodeError {
+	var (
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		maxBytesErr *http.MaxBytesError
+	)
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return &DecodeError{
+			Status: http.StatusRequestEntityTooLarge,
+			Err:    ErrBodyTooLarge,
+			Detail: fmt.Sprintf("limit is %d bytes", maxBytesErr.Limit),
+		}
+	case errors.Is(err, io.EOF):
+		return &DecodeError{Status: http.StatusBadRequest, Err: ErrEmptyBody}
+	case errors.As(err, &syntaxErr):
+		return &DecodeError{Status: http.StatusBadRequest, Err: ErrMalformedJSON, Offset: syntaxErr.Offset}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &DecodeError{Status: http.StatusBadRequest, Err: ErrMalformedJSON, Offset: offset}
+	case errors.As(err, &typeErr):
+		return &DecodeError{
+			Status: http.StatusBadRequest,
+			Err:    ErrInvalidFieldType,
+			Field:  typeErr.Field,
+			Offset: typeErr.Offset,
+			Detail: fmt.Sprintf("expected %s but got %s", typeErr.Type, typeErr.Value),
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for unknown fields
+		field, _ := strconv.Unquote(strings.TrimPrefix(err.Error(), "json: unknown field "))
+		return &DecodeError{Status: http.StatusBadRequest, Err: ErrUnknownField, Field: field, Offset: offset}
+	default:
+		return &DecodeError{Status: http.StatusBadRequest, Err: ErrMalformedJSON, Offset: offset, Detail: err.Error()}
+	}
 }
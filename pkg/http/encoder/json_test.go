@@ -37,6 +37,18 @@ func TestEncodeJson(t *testing.T) {
 	}
 }
 
+func TestEncodeJson_KeepContentType(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("Content-Type", "application/problem+json")
+
+	err := EncodeJson(recorder, map[string]string{"key": "value"})
+	if err != nil {
+		t.Fatalf("EncodeJson() error = %v", err)
+	}
+
+	assert.Equal(t, "application/problem+json", recorder.Header().Get("Content-Type"))
+}
+
 func TestDecodeJson(t *testing.T) {
 	t.Run("success with body", func(t *testing.T) {
 		data := map[string]string{"key": "value"}
@@ -67,3 +79,136 @@ func TestDecodeJson(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestDecodeJson_Strict(t *testing.T) {
+	type payload struct {
+		Email string `json:"email"`
+		Age   int    `json:"age"`
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		maxBytes    int64
+		want        payload
+		wantErr     error
+		wantStatus  int
+		wantField   string
+	}{
+		{
+			name:        "success with charset",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"email":"a@mail.com","age":20}` + "\n",
+			want:        payload{Email: "a@mail.com", Age: 20},
+		},
+		{
+			name:        "success with json suffix",
+			contentType: "application/merge-patch+json",
+			body:        `{"age":20}`,
+			want:        payload{Age: 20},
+		},
+		{
+			name:        "failed due to missing content type",
+			contentType: "",
+			body:        `{"age":20}`,
+			wantErr:     ErrUnsupportedMediaType,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "failed due to form content type",
+			contentType: "application/x-www-form-urlencoded",
+			body:        `age=20`,
+			wantErr:     ErrUnsupportedMediaType,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "failed due to body too large",
+			contentType: "application/json",
+			body:        `{"email":"a@mail.com"}`,
+			maxBytes:    10,
+			wantErr:     ErrBodyTooLarge,
+			wantStatus:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "failed due to unknown field",
+			contentType: "application/json",
+			body:        `{"email":"a@mail.com","admin":true}`,
+			wantErr:     ErrUnknownField,
+			wantStatus:  http.StatusBadRequest,
+			wantField:   "admin",
+		},
+		{
+			name:        "failed due to invalid field type",
+			contentType: "application/json",
+			body:        `{"age":"twenty"}`,
+			wantErr:     ErrInvalidFieldType,
+			wantStatus:  http.StatusBadRequest,
+			wantField:   "age",
+		},
+		{
+			name:        "failed due to malformed json",
+			contentType: "application/json",
+			body:        `{"age":}`,
+			wantErr:     ErrMalformedJSON,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "failed due to truncated json",
+			contentType: "application/json",
+			body:        `{"age":20`,
+			wantErr:     ErrMalformedJSON,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "failed due to multiple values",
+			contentType: "application/json",
+			body:        `{"age":20}{"age":21}`,
+			wantErr:     ErrMultipleValues,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "failed due to trailing garbage",
+			contentType: "application/json",
+			body:        `{"age":20} garbage`,
+			wantErr:     ErrMultipleValues,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "failed due to empty body",
+			contentType: "application/json",
+			body:        "  ",
+			wantErr:     ErrEmptyBody,
+			wantStatus:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.maxBytes > 0 {
+				SetMaxBodyBytes(tt.maxBytes)
+				defer SetMaxBodyBytes(DefaultMaxBodyBytes)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			var got payload
+			err := DecodeJson(req, &got)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			var decodeErr *DecodeError
+			if assert.ErrorAs(t, err, &decodeErr) {
+				assert.Equal(t, tt.wantStatus, decodeErr.Status)
+				assert.Equal(t, tt.wantField, decodeErr.Field)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package encoder
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// MessagePack encodes the JSON representation of values, integers use the smallest format holding them
+var MessagePack Encoder = msgpackEncoder{}
+
+type msgpackEncoder struct{}
+
+func (msgpackEncoder) ContentType() string {
+	return "application/msgpack"
+}
+
+func (msgpackEncoder) Encode(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := encodeMsgpack(bw, tree); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func encodeMsgpack(w *bufio.Writer, value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		return w.WriteByte(0xc0)
+	case bool:
+		if value {
+			return w.WriteByte(0xc3)
+		}
+		return w.WriteByte(0xc2)
+	case json.Number:
+		return encodeMsgpackNumber(w, value)
+	case string:
+		if err := writeMsgpackHeader(w, len(value), 0xa0, 32, 0xd9, 0xda, 0xdb); err != nil {
+			return err
+		}
+		_, err := w.WriteString(value)
+		return err
+	case []interface{}:
+		if err := writeMsgpackHeader(w, len(value), 0x90, 16, 0, 0xdc, 0xdd); err != nil {
+			return err
+		}
+		for _, item := range value {
+			if err := encodeMsgpack(w, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case object:
+		if err := writeMsgpackHeader(w, len(value), 0x80, 16, 0, 0xde, 0xdf); err != nil {
+			return err
+		}
+		for _, m := range value {
+			if err := encodeMsgpack(w, m.key); err != nil {
+				return err
+			}
+			if err := encodeMsgpack(w, m.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unexpected value %T", value)
+	}
+}
+
+func encodeMsgpackNumber(w *bufio.Writer, number json.Number) error {
+	var buf [9]byte
+
+	if n, err := number.Int64(); err == nil {
+		switch {
+		case n >= 0 && n <= math.MaxInt8:
+			return w.WriteByte(byte(n))
+		case n < 0 && n >= -32:
+			return w.WriteByte(byte(int8(n)))
+		case n >= math.MinInt8 && n <= math.MaxInt8:
+			buf[0], buf[1] = 0xd0, byte(int8(n))
+			_, err = w.Write(buf[:2])
+		case n >= math.MinInt16 && n <= math.MaxInt16:
+			buf[0] = 0xd1
+			binary.BigEndian.PutUint16(buf[1:], uint16(int16(n)))
+			_, err = w.Write(buf[:3])
+		case n >= math.MinInt32 && n <= math.MaxInt32:
+			buf[0] = 0xd2
+			binary.BigEndian.PutUint32(buf[1:], uint32(int32(n)))
+			_, err = w.Write(buf[:5])
+		default:
+			buf[0] = 0xd3
+			binary.BigEndian.PutUint64(buf[1:], uint64(n))
+			_, err = w.Write(buf[:9])
+		}
+		return err
+	}
+
+	f, err := number.Float64()
+	if err != nil {
+		return err
+	}
+	buf[0] = 0xcb
+	binary.BigEndian.PutUint64(buf[1:], math.Float64bits(f))
+	_, err = w.Write(buf[:9])
+	return err
+}
+
+// writeMsgpackHeader writes the fix format below fixLimit, or the 8, 16 or 32 bit format, a zero code8 has no 8 bit format
+func writeMsgpackHeader(w *bufio.Writer, n int, fix byte, fixLimit int, code8, code16, code32 byte) error {
+	var buf [5]byte
+	var err error
+	switch {
+	case n < fixLimit:
+		err = w.WriteByte(fix | byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		buf[0], buf[1] = code8, byte(n)
+		_, err = w.Write(buf[:2])
+	case n <= math.MaxUint16:
+		buf[0] = code16
+		binary.BigEndian.PutUint16(buf[1:], uint16(n))
+		_, err = w.Write(buf[:3])
+	default:
+		buf[0] = code32
+		binary.BigEndian.PutUint32(buf[1:], uint32(n))
+		_, err = w.Write(buf[:5])
+	}
+	return err
+}
@@ -0,0 +1,141 @@
+package encoder
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrUnsupportedValue is returned by encoders that cannot represent a value, e.g. CSV for a single object
+var ErrUnsupportedValue = errors.New("value cannot be encoded in the media type")
+
+// Encoder writes values in one media type
+type Encoder interface {
+	// ContentType is the Content-Type header of the encoded body, e.g. "text/csv; charset=utf-8"
+	ContentType() string
+	Encode(w io.Writer, v interface{}) error
+}
+
+// Registry picks the encoder of a request by its Accept header
+type Registry struct {
+	mu       sync.RWMutex
+	encoders []Encoder
+}
+
+// NewRegistry returns a registry preferring the encoders in the given order
+func NewRegistry(encoders ...Encoder) *Registry {
+	r := &Registry{}
+	for _, e := range encoders {
+		r.Register(e)
+	}
+	return r
+}
+
+// Default has JSON, XML, MessagePack and CSV, JSON is used when the client has no preference
+var Default = NewRegistry(JSON, XML, MessagePack, CSV)
+
+// Register adds a codec to the default registry, replacing the codec of the same media type.
+// It is safe for concurrent use, but requests negotiated before the call do not see the codec.
+func Register(e Encoder) {
+	Default.Register(e)
+}
+
+// Register adds an encoder, replacing the one of the same media type
+func (r *Registry) Register(e Encoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mediaType := baseMediaType(e.ContentType())
+	for i, registered := range r.encoders {
+		if baseMediaType(registered.ContentType()) == mediaType {
+			r.encoders[i] = e
+			return
+		}
+	}
+	r.encoders = append(r.encoders, e)
+}
+
+// Negotiate returns the encoder with the highest quality in accept, the first registered one wins ties.
+// An empty accept takes the first encoder, false means no encoder is acceptable.
+func (r *Registry) Negotiate(accept string) (Encoder, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.encoders) == 0 {
+		return nil, false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return r.encoders[0], true
+	}
+
+	ranges := parseAccept(accept)
+	var (
+		best        Encoder
+		bestQuality float64
+	)
+	for _, e := range r.encoders {
+		if q := quality(ranges, baseMediaType(e.ContentType())); q > bestQuality {
+			best, bestQuality = e, q
+		}
+	}
+	return best, best != nil
+}
+
+// mediaRange is one element of an Accept header, e.g. "text/*;q=0.5"
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+func parseAccept(accept string) []mediaRange {
+	ranges := []mediaRange{}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			q, err = strconv.ParseFloat(value, 64)
+			if err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: q})
+	}
+	return ranges
+}
+
+// quality returns the quality of the most specific range matching mediaType, zero when none does
+func quality(ranges []mediaRange, mediaType string) float64 {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+
+	q, specificity := 0.0, 0
+	for _, r := range ranges {
+		s := 0
+		switch r.mediaType {
+		case mediaType:
+			s = 3
+		case mainType + "/*":
+			s = 2
+		case "*/*":
+			s = 1
+		}
+		if s > specificity {
+			q, specificity = r.quality, s
+		}
+	}
+	return q
+}
+
+func baseMediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mediaType
+}
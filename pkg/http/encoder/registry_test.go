@@ -0,0 +1,66 @@
+package encoder
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// yamlEncoder stands for a codec registered by a service
+type yamlEncoder struct{ name string }
+
+func (yamlEncoder) ContentType() string { return "application/yaml" }
+
+func (yamlEncoder) Encode(io.Writer, interface{}) error { return nil }
+
+func TestRegistry_Negotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   Encoder
+		wantOK bool
+	}{
+		{"success without accept", "", JSON, true},
+		{"success with any type", "*/*", JSON, true},
+		{"success with exact type", "application/xml", XML, true},
+		{"success with parameters", "application/msgpack; charset=binary", MessagePack, true},
+		{"success with highest quality", "application/json;q=0.4, text/csv;q=0.9, application/xml;q=0.5", CSV, true},
+		{"success with type wildcard", "text/*", CSV, true},
+		{"success with most specific range", "application/*;q=0.9, application/json;q=0.1", XML, true},
+		{"success excluding a type", "application/json;q=0, */*", XML, true},
+		{"success skipping invalid ranges", "application/json;q=2, ;;, text/csv", CSV, true},
+		{"failed due to unknown type", "image/png", nil, false},
+		{"failed due to excluded types", "application/*;q=0, text/csv;q=0", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Default.Negotiate(tt.accept)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry(JSON)
+
+	_, ok := r.Negotiate("application/yaml")
+	assert.False(t, ok)
+
+	r.Register(yamlEncoder{name: "first"})
+	got, ok := r.Negotiate("application/yaml")
+	assert.True(t, ok)
+	assert.Equal(t, yamlEncoder{name: "first"}, got)
+
+	r.Register(yamlEncoder{name: "second"})
+	got, _ = r.Negotiate("application/yaml")
+	assert.Equal(t, yamlEncoder{name: "second"}, got, "a codec replaces the one of its media type")
+
+	got, _ = r.Negotiate("")
+	assert.Equal(t, JSON, got, "registering keeps the preferred encoder")
+
+	_, ok = NewRegistry().Negotiate("*/*")
+	assert.False(t, ok)
+}
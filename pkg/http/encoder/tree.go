@@ -0,0 +1,82 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// member is a key of an object in the JSON representation of a value
+type member struct {
+	key   string
+	value interface{}
+}
+
+// object keeps the keys of a JSON object in their encoded order
+type object []member
+
+// toTree returns the JSON representation of v as object, []interface{}, string, json.Number, bool or nil,
+// so every media type follows the json tags and marshalers of the value
+func toTree(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeTree(dec)
+}
+
+func decodeTree(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		obj := object{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeTree(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{key: key.(string), value: value})
+		}
+		_, err = dec.Token()
+		return obj, err
+	case '[':
+		arr := []interface{}{}
+		for dec.More() {
+			value, err := decodeTree(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err = dec.Token()
+		return arr, err
+	default:
+		return nil, fmt.Errorf("unexpected delimiter %s", delim)
+	}
+}
+
+// get returns the value of key
+func (o object) get(key string) (interface{}, bool) {
+	for _, m := range o {
+		if m.key == key {
+			return m.value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,82 @@
+package encoder
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+const (
+	xmlRootName = "response"
+	xmlItemName = "item"
+)
+
+// XML encodes the JSON representation of values, objects become elements named after their keys
+// and array elements are <item>, keys which are no valid names are written as <item key="...">
+var XML Encoder = xmlEncoder{}
+
+// xmlName matches the element names written as is, a conservative subset of the XML name production
+var xmlName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+type xmlEncoder struct{}
+
+func (xmlEncoder) ContentType() string {
+	return "application/xml; charset=utf-8"
+}
+
+func (xmlEncoder) Encode(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	if err := encodeXMLElement(enc, xmlRootName, tree); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+func encodeXMLElement(enc *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !xmlName.MatchString(name) {
+		start = xml.StartElement{
+			Name: xml.Name{Local: xmlItemName},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}},
+		}
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch value := value.(type) {
+	case object:
+		for _, m := range value {
+			if err := encodeXMLElement(enc, m.key, m.value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if err := encodeXMLElement(enc, xmlItemName, item); err != nil {
+				return err
+			}
+		}
+	case nil:
+	case string, json.Number, bool:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(value))); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unexpected value %T", value)
+	}
+
+	return enc.EncodeToken(start.End())
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+)
+
+// DefaultMaxBodyBytes caps each logged body when AccessLogOptions.MaxBodyBytes is zero
+const DefaultMaxBodyBytes = 2048
+
+// AccessLogOptions configures the access log, bodies are not logged by default
+type AccessLogOptions struct {
+	RequestBody  bool
+	ResponseBody bool
+	MaxBodyBytes int
+	// TrustProxy reads the client IP from X-Forwarded-For and X-Real-IP, only enable it behind a proxy
+	TrustProxy bool
+	// SkipPaths are not logged unless they fail with a 5xx, e.g. probes and /metrics
+	SkipPaths []string
+}
+
+// AccessLog logs one record per request once the response is written
+type AccessLog struct {
+	appLogger *logger.Logger
+	opts      AccessLogOptions
+	skipPaths map[string]bool
+}
+
+// NewAccessLog creates the access log middleware writing to log
+func NewAccessLog(log *logger.Logger, opts AccessLogOptions) *AccessLog {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	skipPaths := make(map[string]bool, len(opts.SkipPaths))
+	for _, path := range opts.SkipPaths {
+		skipPaths[path] = true
+	}
+
+	return &AccessLog{
+		appLogger: log,
+		opts:      opts,
+		skipPaths: skipPaths,
+	}
+}
+
+// Middleware logs the method, route pattern, status, bytes, latency, client and request ID of every request.
+// 5xx are logged as errors and 4xx as warnings, along with the error recorded by response.WriteFromError.
+func (a *AccessLog) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx := NewRouteContext(r.Context())
+		r = r.WithContext(ctx)
+
+		var requestBody *cappedBuffer
+		if a.opts.RequestBody && r.Body != nil && r.Body != http.NoBody {
+			requestBody = newCappedBuffer(a.opts.MaxBodyBytes)
+			r.Body = &teeReadCloser{ReadCloser: r.Body, w: requestBody}
+		}
+
+		rw := newResponseWriter(w)
+		if a.opts.ResponseBody {
+			rw.body = newCappedBuffer(a.opts.MaxBodyBytes)
+		}
+
+		// a panicking handler is logged with the 500 written by Recovery further out
+		completed := false
+		defer func() {
+			if !completed && !rw.wroteHeader {
+				rw.status = http.StatusInternalServerError
+			}
+			a.log(ctx, r, rw, start, requestBody)
+		}()
+
+		next.ServeHTTP(rw, r)
+		completed = true
+	})
+}
+
+// log writes the record of a request
+func (a *AccessLog) log(ctx context.Context, r *http.Request, rw *responseWriter, start time.Time, requestBody *cappedBuffer) {
+	if a.skipPaths[r.URL.Path] && rw.status < http.StatusInternalServerError {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("route", RoutePattern(ctx)),
+		slog.Int("status", rw.status),
+		slog.Int("bytes", rw.bytes),
+		slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		slog.String("client_ip", clientIP(r, a.opts.TrustProxy)),
+		slog.String("user_agent", r.UserAgent()),
+	}
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	if rw.err != nil {
+		attrs = append(attrs, slog.String("error", rw.err.Error()))
+	}
+	if requestBody != nil {
+		attrs = append(attrs, slog.String("request_body", requestBody.String()), slog.Bool("request_body_truncated", requestBody.truncated))
+	}
+	if rw.body != nil {
+		attrs = append(attrs, slog.String("response_body", rw.body.String()), slog.Bool("response_body_truncated", rw.body.truncated))
+	}
+
+	a.appLogger.LogAttrs(ctx, accessLogLevel(rw.status), "http request", attrs...)
+}
+
+func accessLogLevel(status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// clientIP returns the remote host, or the first forwarded address when the proxy is trusted
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			ip, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(ip)
+		}
+		if ip := r.Header.Get("X-Real-IP"); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// teeReadCloser copies what the handler reads from the request body, the body is never read ahead
+type teeReadCloser struct {
+	io.ReadCloser
+	w io.Writer
+}
+
+func (t *teeReadCloser) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	if n > 0 {
+		t.w.Write(p[:n])
+	}
+	return n, err
+}
@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog_Middleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    AccessLogOptions
+		request func() *http.Request
+		handler http.HandlerFunc
+		want    map[string]any
+		wantNot []string
+		wantLog bool
+	}{
+		{
+			name: "success log matched request",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/users/42", http.NoBody)
+				r.RemoteAddr = "10.0.0.1:52100"
+				r.Header.Set("User-Agent", "curl/8.0")
+				r.Header.Set("X-Forwarded-For", "203.0.113.7")
+				return r.WithContext(NewRequestIDContext(r.Context(), "req-1"))
+			},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				SetRoutePattern(r.Context(), "/users/:id")
+				w.Write([]byte(`{"code":200}`))
+			},
+			want: map[string]any{
+				"level":      "INFO",
+				"msg":        "http request",
+				"method":     "GET",
+				"path":       "/users/42",
+				"route":      "/users/:id",
+				"status":     float64(200),
+				"bytes":      float64(12),
+				"client_ip":  "10.0.0.1",
+				"user_agent": "curl/8.0",
+				"request_id": "req-1",
+			},
+			wantNot: []string{"request_body", "response_body", "error"},
+			wantLog: true,
+		},
+		{
+			name: "success trust proxy client ip",
+			opts: AccessLogOptions{TrustProxy: true},
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+				r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+				return r
+			},
+			handler: func(w http.ResponseWriter, r *http.Request) {},
+			want:    map[string]any{"client_ip": "203.0.113.7", "route": RouteUnmatched},
+			wantLog: true,
+		},
+		{
+			name: "success log capped bodies",
+			opts: AccessLogOptions{RequestBody: true, ResponseBody: true, MaxBodyBytes: 5},
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"email":"a@b.c"}`))
+			},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte("ok"))
+			},
+			want: map[string]any{
+				"status":                  float64(201),
+				"request_body":            `{"ema`,
+				"request_body_truncated":  true,
+				"response_body":           "ok",
+				"response_body_truncated": false,
+			},
+			wantLog: true,
+		},
+		{
+			name: "failed request logs recorded error",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+			},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				response.WriteFromError(w, r, response.WrapErrInternalServer(errors.New("connection refused")), slog.Default())
+			},
+			want:    map[string]any{"level": "ERROR", "status": float64(500), "error": "connection refused"},
+			wantLog: true,
+		},
+		{
+			name: "client error logged as warning",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/missing", http.NoBody)
+			},
+			handler: http.NotFound,
+			want:    map[string]any{"level": "WARN", "status": float64(404)},
+			wantLog: true,
+		},
+		{
+			name: "skip path",
+			opts: AccessLogOptions{SkipPaths: []string{"/healthz"}},
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/healthz", http.NoBody)
+			},
+			handler: func(w http.ResponseWriter, r *http.Request) {},
+			wantLog: false,
+		},
+		{
+			name: "skip path still logs server errors",
+			opts: AccessLogOptions{SkipPaths: []string{"/readyz"}},
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+			},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			want:    map[string]any{"status": float64(503)},
+			wantLog: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+			NewAccessLog(log, tt.opts).Middleware(tt.handler).ServeHTTP(httptest.NewRecorder(), tt.request())
+
+			if !tt.wantLog {
+				assert.Empty(t, buf.String())
+				return
+			}
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			require.Len(t, lines, 1, "one record per request")
+			got := map[string]any{}
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+
+			for key, want := range tt.want {
+				assert.Equal(t, want, got[key], key)
+			}
+			for _, key := range tt.wantNot {
+				assert.NotContains(t, got, key)
+			}
+			assert.Contains(t, got, "latency_ms")
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     map[string]string
+		trustProxy bool
+		want       string
+	}{
+		{name: "remote host", remoteAddr: "10.0.0.1:1234", want: "10.0.0.1"},
+		{name: "remote addr without port", remoteAddr: "10.0.0.1", want: "10.0.0.1"},
+		{name: "forwarded header ignored", remoteAddr: "10.0.0.1:1234", header: map[string]string{"X-Forwarded-For": "203.0.113.7"}, want: "10.0.0.1"},
+		{name: "forwarded header trusted", remoteAddr: "10.0.0.1:1234", header: map[string]string{"X-Forwarded-For": "203.0.113.7"}, trustProxy: true, want: "203.0.113.7"},
+		{name: "real ip trusted", remoteAddr: "10.0.0.1:1234", header: map[string]string{"X-Real-IP": "203.0.113.8"}, trustProxy: true, want: "203.0.113.8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			r.RemoteAddr = tt.remoteAddr
+			for key, val := range tt.header {
+				r.Header.Set(key, val)
+			}
+			assert.Equal(t, tt.want, clientIP(r, tt.trustProxy))
+		})
+	}
+}
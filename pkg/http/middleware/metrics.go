@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
+)
+
+// HTTPMetrics instruments requests labeled by method, route pattern and status
+type HTTPMetrics struct {
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+	inFlight *metrics.GaugeVec
+}
+
+// knownMethods bounds the method label, other methods are reported as OTHER
+var knownMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// NewHTTPMetrics creates the HTTP metrics and registers them into registry
+func NewHTTPMetrics(registry *metrics.Registry) (*HTTPMetrics, error) {
+	m := &HTTPMetrics{
+		requests: metrics.NewCounterVec("http_requests_total", "Total number of HTTP requests.", "method", "route", "status"),
+		duration: metrics.NewHistogramVec("http_request_duration_seconds", "HTTP request latency in seconds.", nil, "method", "route", "status"),
+		inFlight: metrics.NewGaugeVec("http_requests_in_flight", "Number of HTTP requests being served.", "method", "route"),
+	}
+
+	for _, c := range []metrics.Collector{m.requests, m.duration, m.inFlight} {
+		if err := registry.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Middleware records the metrics of every request.
+// It must wrap the router, the route pattern is only known once the request is matched.
+func (m *HTTPMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx := NewRouteContext(r.Context())
+		method := r.Method
+		if !knownMethods[method] {
+			method = "OTHER"
+		}
+
+		var inFlight *metrics.Gauge
+		OnRouteMatch(ctx, func(pattern string) {
+			inFlight = m.inFlight.WithLabelValues(method, pattern)
+			inFlight.Inc()
+		})
+
+		// a panicking handler is measured with the 500 written by Recovery further out
+		rw := newResponseWriter(w)
+		completed := false
+		defer func() {
+			if inFlight != nil {
+				inFlight.Dec()
+			}
+			if !completed && !rw.wroteHeader {
+				rw.status = http.StatusInternalServerError
+			}
+
+			route := RoutePattern(ctx)
+			status := strconv.Itoa(rw.status)
+			m.requests.WithLabelValues(method, route, status).Inc()
+			m.duration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+		}()
+
+		next.ServeHTTP(rw, r.WithContext(ctx))
+		completed = true
+	})
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMetrics_Middleware(t *testing.T) {
+	registry := metrics.NewRegistry()
+	httpMetrics, err := NewHTTPMetrics(registry)
+	require.NoError(t, err)
+
+	var inFlightDuringRequest float64
+	handler := httpMetrics.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/42" {
+			SetRoutePattern(r.Context(), "/users/:id")
+			inFlightDuringRequest = httpMetrics.inFlight.WithLabelValues(http.MethodGet, "/users/:id").Value()
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", http.NoBody))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/43", http.NoBody))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BREW", "/coffee", http.NoBody))
+
+	assert.Equal(t, float64(1), inFlightDuringRequest)
+	assert.Equal(t, float64(0), httpMetrics.inFlight.WithLabelValues(http.MethodGet, "/users/:id").Value())
+
+	var sb strings.Builder
+	require.NoError(t, registry.WriteText(&sb))
+	output := sb.String()
+
+	assert.Contains(t, output, `http_requests_total{method="GET",route="/users/:id",status="200"} 1`)
+	assert.Contains(t, output, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, output, `http_requests_total{method="OTHER",route="unmatched",status="404"} 1`)
+	assert.Contains(t, output, `http_request_duration_seconds_count{method="GET",route="/users/:id",status="200"} 1`)
+	assert.NotContains(t, output, "/users/42")
+}
+
+func TestNewHTTPMetrics_AlreadyRegistered(t *testing.T) {
+	registry := metrics.NewRegistry()
+	_, err := NewHTTPMetrics(registry)
+	require.NoError(t, err)
+
+	_, err = NewHTTPMetrics(registry)
+	assert.ErrorIs(t, err, metrics.ErrAlreadyRegistered)
+}
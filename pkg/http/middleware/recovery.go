@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/response"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
+)
+
+// ErrPanic wraps the value of a recovered panic
+var ErrPanic = errors.New("panic recovered")
+
+// Recovery turns handler panics into a 500 response instead of a reset connection
+type Recovery struct {
+	appLogger *logger.Logger
+	panics    *metrics.CounterVec
+}
+
+// NewRecovery creates the recovery middleware and registers its panic counter into registry
+func NewRecovery(log *logger.Logger, registry *metrics.Registry) (*Recovery, error) {
+	rc := &Recovery{
+		appLogger: log,
+		panics:    metrics.NewCounterVec("http_panics_recovered_total", "Total number of recovered handler panics.", "method", "route"),
+	}
+	if err := registry.Register(rc.panics); err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
+
+// Middleware recovers panics of the next handlers, logs the stack trace and responds with a 500.
+// It must run inside RequestID and Tracing so the log and the span carry the request IDs.
+// http.ErrAbortHandler is re-panicked as it is the documented way to abort a response.
+func (rc *Recovery) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := NewRouteContext(r.Context())
+		r = r.WithContext(ctx)
+		rw := newResponseWriter(w)
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			err := panicError(rec)
+			route := RoutePattern(ctx)
+			method := r.Method
+			if !knownMethods[method] {
+				method = "OTHER"
+			}
+			rc.panics.WithLabelValues(method, route).Inc()
+
+			if span := tracing.SpanFromContext(ctx); span != nil {
+				span.RecordError(err)
+			}
+
+			rc.appLogger.ErrorContext(ctx, "panic recovered",
+				slog.String("panic", fmt.Sprint(rec)),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("route", route),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("stack", string(debug.Stack())),
+			)
+
+			// the status line is already sent, the client gets a truncated response
+			if rw.wroteHeader {
+				return
+			}
+			response.WriteFromError(rw, r, response.WrapErrInternalServer(err), rc.appLogger)
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// panicError converts a recovered value into an error wrapping ErrPanic and the panicked error, if any
+func panicError(rec any) error {
+	if err, ok := rec.(error); ok {
+		return fmt.Errorf("%w: %w", ErrPanic, err)
+	}
+	return fmt.Errorf("%w: %v", ErrPanic, rec)
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/response"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/metrics"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing/tracetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery_Middleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   http.HandlerFunc
+		wantCode  int
+		wantBody  bool
+		wantPanic string
+	}{
+		{
+			name: "recover string panic",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				SetRoutePattern(r.Context(), "/users/:id")
+				panic("translation failed")
+			},
+			wantCode:  http.StatusInternalServerError,
+			wantBody:  true,
+			wantPanic: "translation failed",
+		},
+		{
+			name: "recover error panic",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				SetRoutePattern(r.Context(), "/users/:id")
+				panic(errors.New("nil map"))
+			},
+			wantCode:  http.StatusInternalServerError,
+			wantBody:  true,
+			wantPanic: "nil map",
+		},
+		{
+			name: "recover panic after the header is written",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				SetRoutePattern(r.Context(), "/users/:id")
+				w.WriteHeader(http.StatusOK)
+				panic("late panic")
+			},
+			wantCode:  http.StatusOK,
+			wantPanic: "late panic",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(slog.NewJSONHandler(&buf, nil))
+			registry := metrics.NewRegistry()
+			recovery, err := NewRecovery(log, registry)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/users/42", http.NoBody)
+			req = req.WithContext(NewRequestIDContext(req.Context(), "req-1"))
+			recovery.Middleware(tt.handler).ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.wantCode, recorder.Code)
+			if tt.wantBody {
+				body := response.ErrResponse{}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				assert.Equal(t, http.StatusInternalServerError, body.Code)
+				assert.Equal(t, "internal server error", body.Message)
+			}
+
+			var panicLog map[string]any
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				entry := map[string]any{}
+				require.NoError(t, json.Unmarshal([]byte(line), &entry))
+				if entry["msg"] == "panic recovered" {
+					panicLog = entry
+				}
+			}
+			require.NotNil(t, panicLog, "panic must be logged")
+			assert.Equal(t, tt.wantPanic, panicLog["panic"])
+			assert.Equal(t, "/users/:id", panicLog["route"])
+			assert.Contains(t, panicLog["stack"], "runtime/debug.Stack")
+
+			var sb strings.Builder
+			require.NoError(t, registry.WriteText(&sb))
+			assert.Contains(t, sb.String(), `http_panics_recovered_total{method="GET",route="/users/:id"} 1`)
+		})
+	}
+}
+
+func TestRecovery_AbortHandler(t *testing.T) {
+	recovery, err := NewRecovery(slog.Default(), metrics.NewRegistry())
+	require.NoError(t, err)
+
+	handler := recovery.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	})
+}
+
+func TestRecovery_MarksSpan(t *testing.T) {
+	recovery, err := NewRecovery(slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil)), metrics.NewRegistry())
+	require.NoError(t, err)
+
+	recorder := tracetest.NewRecorder()
+	tracer := tracing.NewTracer("test-service", recorder, nil)
+	ctx, span := tracer.Start(context.Background(), "GET /users/:id")
+
+	handler := recovery.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody).WithContext(ctx))
+	span.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	require.Len(t, recorder.Spans(), 1)
+	assert.Equal(t, tracing.StatusError, recorder.Spans()[0].Status)
+	assert.Contains(t, recorder.Spans()[0].StatusMessage, "boom")
+}
+
+func TestNewRecovery_AlreadyRegistered(t *testing.T) {
+	registry := metrics.NewRegistry()
+	_, err := NewRecovery(slog.Default(), registry)
+	require.NoError(t, err)
+
+	_, err = NewRecovery(slog.Default(), registry)
+	assert.ErrorIs(t, err, metrics.ErrAlreadyRegistered)
+}
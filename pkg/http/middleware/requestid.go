@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+)
+
+const (
+	HeaderRequestID = "X-Request-ID"
+
+	// requestIDMaxLength limits the accepted incoming request ID to keep logs sane
+	requestIDMaxLength = 128
+)
+
+type requestIDKey struct{}
+
+var (
+	generateRequestID = newRequestID
+)
+
+func init() {
+	logger.RegisterContextKey(requestIDKey{}, "request_id")
+}
+
+// RequestID accepts the incoming X-Request-ID or generates a new one,
+// stores it in the request context and echoes it on the response
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = generateRequestID()
+		}
+
+		w.Header().Set(HeaderRequestID, requestID)
+		next.ServeHTTP(w, r.WithContext(NewRequestIDContext(r.Context(), requestID)))
+	})
+}
+
+// NewRequestIDContext returns a new context carrying the request ID
+func NewRequestIDContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok
+}
+
+// validRequestID only accepts visible ASCII characters to avoid log and header injection
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > requestIDMaxLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] <= ' ' || requestID[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID generates a random 128-bit hex encoded request ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
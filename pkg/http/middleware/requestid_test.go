@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	tmpGenerateRequestID := generateRequestID
+	defer func() {
+		generateRequestID = tmpGenerateRequestID
+	}()
+	generateRequestID = func() string {
+		return "generated-id"
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "success with incoming request id",
+			header: "incoming-id",
+			want:   "incoming-id",
+		},
+		{
+			name:   "success with generated request id",
+			header: "",
+			want:   "generated-id",
+		},
+		{
+			name:   "replace request id with invalid characters",
+			header: "bad id\r\n",
+			want:   "generated-id",
+		},
+		{
+			name:   "replace too long request id",
+			header: strings.Repeat("a", requestIDMaxLength+1),
+			want:   "generated-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRequestID string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRequestID, _ = RequestIDFromContext(r.Context())
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+			if tt.header != "" {
+				request.Header.Set(HeaderRequestID, tt.header)
+			}
+			recorder := httptest.NewRecorder()
+
+			RequestID(next).ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.want, gotRequestID)
+			assert.Equal(t, tt.want, recorder.Header().Get(HeaderRequestID))
+		})
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	id1 := newRequestID()
+	id2 := newRequestID()
+
+	assert.Len(t, id1, 32)
+	assert.NotEqual(t, id1, id2)
+	assert.True(t, validRequestID(id1))
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+)
+
+// RouteUnmatched is the route pattern of requests not matching any route, e.g. 404 and 405
+const RouteUnmatched = "unmatched"
+
+type routeKey struct{}
+
+// routeInfo is filled by the router once the request is matched,
+// so middlewares running before routing can read the pattern afterwards
+type routeInfo struct {
+	pattern string
+	onMatch []func(pattern string)
+}
+
+// NewRouteContext returns a context able to record the matched route pattern
+func NewRouteContext(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(routeKey{}).(*routeInfo); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, routeKey{}, &routeInfo{})
+}
+
+// SetRoutePattern records the matched route pattern, e.g. /users/:id, and runs the OnRouteMatch callbacks
+func SetRoutePattern(ctx context.Context, pattern string) {
+	info, ok := ctx.Value(routeKey{}).(*routeInfo)
+	if !ok {
+		return
+	}
+
+	info.pattern = pattern
+	for _, fn := range info.onMatch {
+		fn(pattern)
+	}
+}
+
+// OnRouteMatch registers fn to be called with the route pattern once the request is matched
+func OnRouteMatch(ctx context.Context, fn func(pattern string)) {
+	if info, ok := ctx.Value(routeKey{}).(*routeInfo); ok {
+		info.onMatch = append(info.onMatch, fn)
+	}
+}
+
+// RoutePattern returns the matched route pattern or RouteUnmatched
+func RoutePattern(ctx context.Context) string {
+	info, ok := ctx.Value(routeKey{}).(*routeInfo)
+	if !ok || info.pattern == "" {
+		return RouteUnmatched
+	}
+	return info.pattern
+}
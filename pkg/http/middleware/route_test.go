@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutePattern(t *testing.T) {
+	t.Run("success with matched route", func(t *testing.T) {
+		ctx := NewRouteContext(context.Background())
+		matched := []string{}
+		OnRouteMatch(ctx, func(pattern string) {
+			matched = append(matched, pattern)
+		})
+
+		SetRoutePattern(ctx, "/users/:id")
+
+		assert.Equal(t, "/users/:id", RoutePattern(ctx))
+		assert.Equal(t, []string{"/users/:id"}, matched)
+	})
+
+	t.Run("success reuse existing route context", func(t *testing.T) {
+		ctx := NewRouteContext(context.Background())
+		inner := NewRouteContext(ctx)
+
+		SetRoutePattern(inner, "/users")
+
+		assert.Equal(t, "/users", RoutePattern(ctx))
+	})
+
+	t.Run("unmatched route", func(t *testing.T) {
+		ctx := NewRouteContext(context.Background())
+		assert.Equal(t, RouteUnmatched, RoutePattern(ctx))
+	})
+
+	t.Run("without route context", func(t *testing.T) {
+		ctx := context.Background()
+		SetRoutePattern(ctx, "/users")
+		OnRouteMatch(ctx, func(pattern string) {
+			t.Fatal("OnRouteMatch() callback must not be called")
+		})
+
+		assert.Equal(t, RouteUnmatched, RoutePattern(ctx))
+	})
+}
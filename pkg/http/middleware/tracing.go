@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
+)
+
+// Tracing starts a server span per request from the default tracer,
+// continuing the trace of an incoming W3C traceparent header.
+// The span is named after the route pattern once the request is matched, e.g. GET /users/:id.
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := NewRouteContext(tracing.Extract(r.Context(), r.Header))
+		ctx, span := tracing.Start(ctx, r.Method,
+			tracing.WithSpanKind(tracing.SpanKindServer),
+			tracing.WithAttributes(
+				tracing.String("http.request.method", r.Method),
+				tracing.String("url.path", r.URL.Path),
+				tracing.String("user_agent.original", r.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		OnRouteMatch(ctx, func(pattern string) {
+			span.SetName(r.Method + " " + pattern)
+			span.SetAttributes(tracing.String("http.route", pattern))
+		})
+
+		rw := newResponseWriter(w)
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		span.SetAttributes(tracing.Int("http.response.status_code", rw.status))
+		if rw.status >= http.StatusInternalServerError {
+			span.SetStatus(tracing.StatusError, http.StatusText(rw.status))
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing/tracetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracing(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		handler     http.HandlerFunc
+		wantName    string
+		wantStatus  tracing.StatusCode
+		wantRemote  bool
+	}{
+		{
+			name: "success named after route pattern",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				SetRoutePattern(r.Context(), "/users/:id")
+				w.WriteHeader(http.StatusOK)
+			},
+			wantName:   "GET /users/:id",
+			wantStatus: tracing.StatusUnset,
+		},
+		{
+			name:        "success continue incoming trace",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			handler:     func(w http.ResponseWriter, r *http.Request) {},
+			wantName:    "GET",
+			wantStatus:  tracing.StatusUnset,
+			wantRemote:  true,
+		},
+		{
+			name: "failed request marks span as error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantName:   "GET",
+			wantStatus: tracing.StatusError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpTracer := tracing.Default()
+			defer tracing.SetDefault(tmpTracer)
+			recorder := tracetest.NewRecorder()
+			tracer := tracing.NewTracer("test-service", recorder, nil)
+			tracing.SetDefault(tracer)
+
+			var handlerSpan tracing.SpanContext
+			handler := Tracing(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerSpan = tracing.SpanContextFromContext(r.Context())
+				tt.handler(w, r)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/users/42", http.NoBody)
+			if tt.traceparent != "" {
+				req.Header.Set(tracing.HeaderTraceparent, tt.traceparent)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			require.NoError(t, tracer.Shutdown(context.Background()))
+
+			spans := recorder.Spans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tt.wantName, spans[0].Name)
+			assert.Equal(t, tracing.SpanKindServer, spans[0].Kind)
+			assert.Equal(t, tt.wantStatus, spans[0].Status)
+			assert.Equal(t, handlerSpan.SpanID, spans[0].SpanID)
+			if tt.wantRemote {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].TraceID.String())
+				assert.Equal(t, "00f067aa0ba902b7", spans[0].ParentSpanID.String())
+			} else {
+				assert.False(t, spans[0].ParentSpanID.IsValid())
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// responseWriter records the status code, the number of bytes written and the error of the request
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+	err         error
+	body        *cappedBuffer // captured response body, nil unless enabled by the access log
+}
+
+func newResponseWriter(w http.ResponseWriter) *responseWriter {
+	if rw, ok := w.(*responseWriter); ok {
+		return rw
+	}
+	return &responseWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	if w.body != nil {
+		w.body.Write(b[:n])
+	}
+	return n, err
+}
+
+// RecordError implements response.ErrorRecorder, the access log reports the error
+func (w *responseWriter) RecordError(err error) {
+	w.err = err
+}
+
+// Flush keeps streaming responses working through the wrapper
+func (w *responseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		flusher.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// cappedBuffer keeps the first max bytes written to it and reports whether more were discarded
+type cappedBuffer struct {
+	buf       []byte
+	max       int
+	truncated bool
+}
+
+func newCappedBuffer(max int) *cappedBuffer {
+	return &cappedBuffer{max: max}
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - len(b.buf); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf = append(b.buf, p[:room]...)
+		}
+		return len(p), nil
+	}
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+func (b *cappedBuffer) String() string {
+	return string(b.buf)
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseWriter(t *testing.T) {
+	tests := []struct {
+		name       string
+		write      func(w http.ResponseWriter)
+		wantStatus int
+		wantBytes  int
+	}{
+		{
+			name:       "implicit status on write",
+			write:      func(w http.ResponseWriter) { w.Write([]byte("pong")) },
+			wantStatus: http.StatusOK,
+			wantBytes:  4,
+		},
+		{
+			name: "explicit status",
+			write: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte("{}"))
+			},
+			wantStatus: http.StatusCreated,
+			wantBytes:  2,
+		},
+		{
+			name: "first status wins",
+			write: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusNotFound)
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "status after write is ignored",
+			write: func(w http.ResponseWriter) {
+				w.Write([]byte("a"))
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantStatus: http.StatusOK,
+			wantBytes:  1,
+		},
+		{
+			name:       "flush",
+			write:      func(w http.ResponseWriter) { w.(http.Flusher).Flush() },
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			rw := newResponseWriter(recorder)
+
+			tt.write(rw)
+
+			assert.Equal(t, tt.wantStatus, rw.status)
+			assert.Equal(t, tt.wantBytes, rw.bytes)
+			assert.Equal(t, recorder, rw.Unwrap())
+			assert.Same(t, rw, newResponseWriter(rw))
+		})
+	}
+}
+
+func TestCappedBuffer(t *testing.T) {
+	buf := newCappedBuffer(4)
+
+	n, err := buf.Write([]byte("ab"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.False(t, buf.truncated)
+
+	n, err = buf.Write([]byte("cdef"))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n, "discarded bytes are still reported as written")
+	assert.Equal(t, "abcd", buf.String())
+	assert.True(t, buf.truncated)
+}
@@ -1,29 +1,61 @@
 package request
 
 import (
+	"encoding"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
+)
+
+// queryTag names the param of a field, e.g. `query:"limit,default=10"`, json is used without it
+const queryTag = "query"
 
-	"github.com/guregu/null/v5"
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
 )
 
-// PopulateStructFromQueryParams stores query params to struct
+// FieldError is a query param that could not be stored in its field
+type FieldError struct {
+	Field string
+	Param string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid value for query param %s: %v", e.Param, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// PopulateStructFromQueryParams stores query params to struct, the errors of all fields are joined
 func PopulateStructFromQueryParams(r *http.Request, dst interface{}) error {
-	return populateStructFromQueryParams(r, reflect.ValueOf(dst).Elem())
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Pointer || dstValue.IsNil() {
+		return fmt.Errorf("expected a pointer to struct but got %T", dst)
+	}
+
+	var errs []error
+	if err := populateStructFromQueryParams(r.URL.Query(), dstValue.Elem(), &errs); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
 }
 
 // populateStructFromQueryParams is helper function to recursively populate struct fields
-func populateStructFromQueryParams(r *http.Request, dstValue reflect.Value) error {
+func populateStructFromQueryParams(values url.Values, dstValue reflect.Value, errs *[]error) error {
 	if dstValue.Kind() != reflect.Struct {
 		return fmt.Errorf("expected a struct but got %s", dstValue.Kind())
 	}
 
-	values := r.URL.Query()
 	dstType := dstValue.Type()
-
 	for i := 0; i < dstType.NumField(); i++ {
 		field := dstType.Field(i)
 		fieldValue := dstValue.Field(i)
@@ -33,46 +65,158 @@ func populateStructFromQueryParams(r *http.Request, dstValue reflect.Value) erro
 			continue
 		}
 
-		// handle embedded structs except for specific type like time.Time
-		if fieldValue.Kind() == reflect.Struct &&
-			(field.Type != reflect.TypeOf(time.Time{}) ||
-				field.Type != reflect.TypeOf(null.String{})) {
-			if err := populateStructFromQueryParams(r, fieldValue); err != nil {
+		name, defaultValue, hasDefault := queryParamName(field)
+		if name == "-" {
+			continue
+		}
+
+		// handle nested structs except for types decoding themselves like time.Time and null.String
+		if fieldValue.Kind() == reflect.Struct && !isTextUnmarshaler(fieldValue.Type()) {
+			if err := populateStructFromQueryParams(values, fieldValue, errs); err != nil {
 				return err
 			}
+			continue
 		}
 
-		// handle fields with tags
-		queryTag := field.Tag.Get("json")
-		if queryTag == "" {
-			queryTag = field.Name
+		queryValues := nonEmpty(values[name])
+		if len(queryValues) == 0 {
+			if !hasDefault {
+				continue
+			}
+			queryValues = []string{defaultValue}
 		}
 
-		queryValue := values.Get(queryTag)
-		if queryValue == "" {
-			continue
+		if err := setQueryValue(fieldValue, queryValues); err != nil {
+			*errs = append(*errs, &FieldError{Field: field.Name, Param: name, Err: err})
+		}
+	}
+
+	return nil
+}
+
+// queryParamName returns the param name and default of a field
+func queryParamName(field reflect.StructField) (name, defaultValue string, hasDefault bool) {
+	tag, ok := field.Tag.Lookup(queryTag)
+	if !ok {
+		tag = field.Tag.Get("json")
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if ok {
+		// default is the last option so it may contain commas
+		if i := strings.Index(options, "default="); i >= 0 {
+			defaultValue, hasDefault = options[i+len("default="):], true
 		}
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, defaultValue, hasDefault
+}
 
-		// set the field value based on its type
-		switch fieldValue.Interface().(type) {
-		case string:
-			fieldValue.SetString(queryValue)
-		case int, int8, int16, int32, int64:
-			intValue, err := strconv.ParseInt(queryValue, 10, fieldValue.Type().Bits())
-			if err != nil {
-				return fmt.Errorf("invalid integer value for field %s: %v", field.Name, err)
+// setQueryValue sets a field from its query values, slices take every value and comma separated items
+func setQueryValue(fieldValue reflect.Value, queryValues []string) error {
+	fieldType := fieldValue.Type()
+
+	if fieldType.Kind() == reflect.Pointer && !isTextUnmarshaler(fieldType) {
+		elem := reflect.New(fieldType.Elem())
+		if err := setQueryValue(elem.Elem(), queryValues); err != nil {
+			return err
+		}
+		fieldValue.Set(elem)
+		return nil
+	}
+
+	if fieldType.Kind() == reflect.Slice && !isTextUnmarshaler(fieldType) {
+		items := []string{}
+		for _, queryValue := range queryValues {
+			for _, item := range strings.Split(queryValue, ",") {
+				items = append(items, strings.TrimSpace(item))
 			}
-			fieldValue.SetInt(intValue)
-		case time.Time:
-			timeValue, err := time.Parse(time.RFC3339, queryValue)
-			if err != nil {
-				return fmt.Errorf("invalid time value for field %s: %v", field.Name, err)
+		}
+
+		items = nonEmpty(items)
+		slice := reflect.MakeSlice(fieldType, len(items), len(items))
+		for i, item := range items {
+			if err := setValue(slice.Index(i), item); err != nil {
+				return err
 			}
-			fieldValue.Set(reflect.ValueOf(timeValue))
-		default:
-			return fmt.Errorf("unsupported kind %s for field %s", fieldValue.Kind(), field.Name)
 		}
+		fieldValue.Set(slice)
+		return nil
+	}
+
+	return setValue(fieldValue, queryValues[len(queryValues)-1])
+}
+
+// setValue sets a single value based on the field type
+func setValue(fieldValue reflect.Value, value string) error {
+	fieldType := fieldValue.Type()
+
+	if isTextUnmarshaler(fieldType) {
+		if fieldType.Kind() == reflect.Pointer {
+			fieldValue.Set(reflect.New(fieldType.Elem()))
+			return fieldValue.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+		}
+		return fieldValue.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	if fieldType == durationType {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetInt(int64(duration))
+		return nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		fieldValue.SetString(value)
+	case reflect.Bool:
+		boolValue, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetBool(boolValue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intValue, err := strconv.ParseInt(value, 10, fieldType.Bits())
+		if err != nil {
+			return err
+		}
+		fieldValue.SetInt(intValue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uintValue, err := strconv.ParseUint(value, 10, fieldType.Bits())
+		if err != nil {
+			return err
+		}
+		fieldValue.SetUint(uintValue)
+	case reflect.Float32, reflect.Float64:
+		floatValue, err := strconv.ParseFloat(value, fieldType.Bits())
+		if err != nil {
+			return err
+		}
+		fieldValue.SetFloat(floatValue)
+	case reflect.Pointer, reflect.Slice:
+		return setQueryValue(fieldValue, []string{value})
+	default:
+		return fmt.Errorf("unsupported type %s", fieldType)
 	}
 
 	return nil
 }
+
+// isTextUnmarshaler reports whether t or *t decodes itself from text
+func isTextUnmarshaler(t reflect.Type) bool {
+	return t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func nonEmpty(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
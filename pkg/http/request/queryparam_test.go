@@ -1,8 +1,10 @@
 package request
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"reflect"
 	"testing"
 	"time"
@@ -14,14 +16,17 @@ type TestStruct struct {
 	Name     string    `json:"name"`
 	Age      int       `json:"age"`
 	JoinedAt time.Time `json:"joined_at"`
+	Active   bool      `json:"active"`
+	IDs      []int64   `json:"id[in]"`
 	TestProfile
 }
 
 type TestProfile struct {
-	URL             string      `json:"url"`
-	email           string      // unexported test
-	Fullname        string      // missing json tag
-	UnsupportedNull null.String `json:"unsupported_null"`
+	URL         string            `json:"url"`
+	email       string            // unexported test
+	Fullname    string            // missing json tag
+	Nickname    null.String       `json:"nickname"`
+	Unsupported map[string]string `json:"unsupported"`
 }
 
 func TestPopulateStructFromQueryParams(t *testing.T) {
@@ -37,14 +42,20 @@ func TestPopulateStructFromQueryParams(t *testing.T) {
 				"name":      "Alice",
 				"age":       "30",
 				"joined_at": "2023-08-01T12:00:00Z",
+				"active":    "true",
+				"id[in]":    "1,2,3",
 				"url":       "www.example.com",
+				"nickname":  "ali",
 			},
 			expected: TestStruct{
 				Name:     "Alice",
 				Age:      30,
 				JoinedAt: time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC),
+				Active:   true,
+				IDs:      []int64{1, 2, 3},
 				TestProfile: TestProfile{
-					URL: "www.example.com",
+					URL:      "www.example.com",
+					Nickname: null.StringFrom("ali"),
 				},
 			},
 			expectError: false,
@@ -80,6 +91,28 @@ func TestPopulateStructFromQueryParams(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "failed due to invalid boolean value",
+			queryParams: map[string]string{
+				"name":   "Frank",
+				"active": "maybe",
+			},
+			expected: TestStruct{
+				Name: "Frank",
+			},
+			expectError: true,
+		},
+		{
+			name: "failed due to invalid integer list value",
+			queryParams: map[string]string{
+				"name":   "Grace",
+				"id[in]": "1,two",
+			},
+			expected: TestStruct{
+				Name: "Grace",
+			},
+			expectError: true,
+		},
 		{
 			name: "failed due to invalid time value",
 			queryParams: map[string]string{
@@ -96,8 +129,8 @@ func TestPopulateStructFromQueryParams(t *testing.T) {
 		{
 			name: "failed due to unsupported type",
 			queryParams: map[string]string{
-				"name":             "Eve",
-				"unsupported_null": "invalid-type",
+				"name":        "Eve",
+				"unsupported": "invalid-type",
 			},
 			expected: TestStruct{
 				Name: "Eve",
@@ -133,6 +166,92 @@ func TestPopulateStructFromQueryParams(t *testing.T) {
 	}
 }
 
+type TestTypes struct {
+	Count    uint8         `query:"count"`
+	Ratio    float64       `query:"ratio,default=0.5"`
+	Limit    *int          `query:"limit"`
+	Timeout  time.Duration `query:"timeout,default=1s"`
+	Addr     netip.Addr    `query:"addr"`
+	Since    null.Time     `query:"since"`
+	Age      null.Int      `query:"age"`
+	IDs      []int64       `query:"id"`
+	Tags     []string      `query:"tag,default=a,b"`
+	Ignored  string        `query:"-" json:"ignored"`
+	Fallback string        `json:"fallback,omitempty"`
+}
+
+func TestPopulateStructFromQueryParams_Types(t *testing.T) {
+	limit := 20
+
+	tests := []struct {
+		name       string
+		query      string
+		expected   TestTypes
+		wantParams []string
+	}{
+		{
+			name:  "success decode all types",
+			query: "count=7&ratio=1.25&limit=20&timeout=90s&addr=10.0.0.1&since=2024-05-01T10:00:00Z&age=30&id=1&id=2,3&tag=x&ignored=yes&fallback=json",
+			expected: TestTypes{
+				Count:    7,
+				Ratio:    1.25,
+				Limit:    &limit,
+				Timeout:  90 * time.Second,
+				Addr:     netip.MustParseAddr("10.0.0.1"),
+				Since:    null.TimeFrom(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)),
+				Age:      null.IntFrom(30),
+				IDs:      []int64{1, 2, 3},
+				Tags:     []string{"x"},
+				Fallback: "json",
+			},
+		},
+		{
+			name:  "success with defaults",
+			query: "ratio=&id=",
+			expected: TestTypes{
+				Ratio:   0.5,
+				Timeout: time.Second,
+				Tags:    []string{"a", "b"},
+			},
+		},
+		{
+			name:  "failed with every invalid field",
+			query: "count=300&limit=x&timeout=soon&addr=nowhere&id=1,two&ratio=2",
+			expected: TestTypes{
+				Ratio: 2,
+				Tags:  []string{"a", "b"},
+			},
+			wantParams: []string{"count", "limit", "timeout", "addr", "id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test?"+tt.query, nil)
+
+			var result TestTypes
+			err := PopulateStructFromQueryParams(req, &result)
+
+			var params []string
+			if err != nil {
+				for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+					var fieldErr *FieldError
+					if errors.As(err, &fieldErr) {
+						params = append(params, fieldErr.Param)
+					}
+				}
+			}
+			if !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("PopulateStructFromQueryParams() error params = %v, want %v (%v)", params, tt.wantParams, err)
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("PopulateStructFromQueryParams() result = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
 func encodeQueryParams(params map[string]string) string {
 	values := ""
 	for key, value := range params {
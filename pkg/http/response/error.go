@@ -1,19 +1,16 @@
 package response
 
 import (
-	"bytes"
-	"io"
 	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/encoder"
 	appValidator "github.com/raflynagachi/go-rest-api-starter/pkg/validator"
 )
 
-const ()
-
 type ErrResponse struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
@@ -21,13 +18,25 @@ type ErrResponse struct {
 }
 
 func (e ErrResponse) Error() string {
+	if e.Err == nil {
+		return http.StatusText(e.Code)
+	}
 	return e.Err.Error()
 }
 
 // WriteFromError writes a formatted error response
 func WriteFromError(w http.ResponseWriter, r *http.Request, e error, log *slog.Logger) {
 	errResp, lastError := findErrResponse(e)
-	errResp.Message = lastError.Error()
+	if lastError != nil {
+		errResp.Message = lastError.Error()
+	}
+
+	// decoding failures carry their own status, e.g. 413 for a body over the limit
+	var decodeErr *encoder.DecodeError
+	if errors.As(errResp.Err, &decodeErr) {
+		errResp.Code = decodeErr.Status
+		errResp.Message = decodeErr.Error()
+	}
 
 	if errResp.Code == http.StatusBadRequest {
 		if valErrs, ok := errResp.Err.(validator.ValidationErrors); ok {
@@ -42,34 +51,52 @@ func WriteFromError(w http.ResponseWriter, r *http.Request, e error, log *slog.L
 		}
 	}
 
-	var bodyBytes []byte
-	if r.Body != nil {
-		bodyBytes, _ = io.ReadAll(r.Body)
-		r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-	}
-	body := string(bodyBytes)
-
-	log.Error(
-		"error response",
-		slog.Int("code", errResp.Code),
-		slog.String("path", r.URL.Path),
-		slog.String("method", r.Method),
-		slog.String("request", body),
-		slog.String("error", errResp.Error()),
-	)
+	recordError(w, r, errResp, log)
 
 	if errResp.Code == http.StatusInternalServerError {
 		errResp.Err = errors.New("internal server error")
 		errResp.Message = errResp.Err.Error()
 	}
 
+	var payload interface{} = errResp
+	contentType := ContentTypeJSON
+	if wantsProblem(r) {
+		payload = NewProblemDetails(r, errResp)
+		contentType = ContentTypeProblemJSON
+	}
+
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(errResp.Code)
-	err := encodeJson(w, errResp)
+	err := encodeJson(w, payload)
 	if err != nil {
+		log.ErrorContext(r.Context(), "failed to encode error response", slog.String("error", err.Error()))
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 	}
 }
 
+// ErrorRecorder is implemented by response writers reporting the error of the request,
+// e.g. the access log writer, so the error is logged once within the access log record
+type ErrorRecorder interface {
+	RecordError(err error)
+}
+
+// recordError hands the error to the access log, or logs it when the writer does not record errors
+func recordError(w http.ResponseWriter, r *http.Request, errResp ErrResponse, log *slog.Logger) {
+	if recorder, ok := w.(ErrorRecorder); ok {
+		recorder.RecordError(errResp)
+		return
+	}
+
+	log.ErrorContext(
+		r.Context(),
+		"error response",
+		slog.Int("code", errResp.Code),
+		slog.String("path", r.URL.Path),
+		slog.String("method", r.Method),
+		slog.String("error", errResp.Error()),
+	)
+}
+
 func WrapErrBadRequest(err error) ErrResponse {
 	return ErrResponse{
 		Code: http.StatusBadRequest,
@@ -77,6 +104,20 @@ func WrapErrBadRequest(err error) ErrResponse {
 	}
 }
 
+func WrapErrUnauthorized(err error) ErrResponse {
+	return ErrResponse{
+		Code: http.StatusUnauthorized,
+		Err:  err,
+	}
+}
+
+func WrapErrForbidden(err error) ErrResponse {
+	return ErrResponse{
+		Code: http.StatusForbidden,
+		Err:  err,
+	}
+}
+
 func WrapErrNotFound(err error) ErrResponse {
 	return ErrResponse{
 		Code: http.StatusNotFound,
@@ -84,6 +125,13 @@ func WrapErrNotFound(err error) ErrResponse {
 	}
 }
 
+func WrapErrNotAcceptable(err error) ErrResponse {
+	return ErrResponse{
+		Code: http.StatusNotAcceptable,
+		Err:  err,
+	}
+}
+
 func WrapErrInternalServer(err error) ErrResponse {
 	return ErrResponse{
 		Code: http.StatusInternalServerError,
@@ -1,7 +1,9 @@
 package response
 
 import (
+	"bytes"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/encoder"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -66,6 +69,30 @@ func TestWriteFromError(t *testing.T) {
 			expectedCode: http.StatusBadRequest,
 			expectedBody: `{"code":400,"message":"Key: 'TestStruct.Name' Error:Field validation for 'Name' failed on the 'required' tag,Key: 'TestStruct.Age' Error:Field validation for 'Age' failed on the 'required' tag"}`,
 		},
+		{
+			name: "success with body too large",
+			err:  WrapErrBadRequest(&encoder.DecodeError{Status: http.StatusRequestEntityTooLarge, Err: encoder.ErrBodyTooLarge, Detail: "limit is 10 bytes"}),
+			setup: func() {
+				findErrResponse = FindErrResponse
+			},
+			expectedCode: http.StatusRequestEntityTooLarge,
+			expectedBody: `{"code":413,"message":"request body too large: limit is 10 bytes"}`,
+		},
+		{
+			name: "success with invalid field type",
+			err: errors.Wrap(WrapErrBadRequest(&encoder.DecodeError{
+				Status: http.StatusBadRequest,
+				Err:    encoder.ErrInvalidFieldType,
+				Field:  "age",
+				Offset: 12,
+				Detail: "expected int but got string",
+			}), "Handler.DecodeJson"),
+			setup: func() {
+				findErrResponse = FindErrResponse
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"code":400,"message":"request body contains an invalid value for field \"age\" at offset 12: expected int but got string"}`,
+		},
 		{
 			name: "success with internal server error",
 			err:  errors.New("internal server error"),
@@ -81,6 +108,15 @@ func TestWriteFromError(t *testing.T) {
 			expectedCode: http.StatusInternalServerError,
 			expectedBody: `{"code":500,"message":"internal server error"}`,
 		},
+		{
+			name: "success with non ErrResponse error",
+			err:  errors.New("some error"),
+			setup: func() {
+				findErrResponse = FindErrResponse
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"code":500,"message":"internal server error"}`,
+		},
 		{
 			name: "encoding error",
 			err:  errors.New("some error"),
@@ -127,6 +163,33 @@ func TestWriteFromError(t *testing.T) {
 	}
 }
 
+// errorRecordingWriter mimics the access log writer
+type errorRecordingWriter struct {
+	*httptest.ResponseRecorder
+	err error
+}
+
+func (w *errorRecordingWriter) RecordError(err error) {
+	w.err = err
+}
+
+func TestWriteFromError_RecordError(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+	request := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"password":"secret"}`))
+
+	w := &errorRecordingWriter{ResponseRecorder: httptest.NewRecorder()}
+	WriteFromError(w, request, WrapErrInternalServer(errors.New("connection refused")), log)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.EqualError(t, w.err, "connection refused")
+	assert.Empty(t, buf.String(), "the access log reports the error")
+
+	WriteFromError(httptest.NewRecorder(), request, WrapErrInternalServer(errors.New("connection refused")), log)
+	assert.Contains(t, buf.String(), "connection refused")
+	assert.NotContains(t, buf.String(), "secret", "request bodies are never logged")
+}
+
 func TestFindErrResponse(t *testing.T) {
 	mockWrappedErr := errors.New("wrapped error")
 	mockInnerErr := errors.New("inner error")
@@ -185,6 +248,7 @@ func TestFindErrResponse(t *testing.T) {
 
 func TestWrapErrFunctions(t *testing.T) {
 	mockBadReqErr := errors.New("bad request error")
+	mockUnauthorizedErr := errors.New("unauthorized error")
 	mockNotFoundErr := errors.New("not found error")
 	mockInternalErr := errors.New("internal server error")
 
@@ -202,6 +266,13 @@ func TestWrapErrFunctions(t *testing.T) {
 			expectedCode: http.StatusBadRequest,
 			expectedErr:  mockBadReqErr,
 		},
+		{
+			name:         "WrapErrUnauthorized",
+			wrapFunc:     WrapErrUnauthorized,
+			inputError:   mockUnauthorizedErr,
+			expectedCode: http.StatusUnauthorized,
+			expectedErr:  mockUnauthorizedErr,
+		},
 		{
 			name:         "WrapErrNotFound",
 			wrapFunc:     WrapErrNotFound,
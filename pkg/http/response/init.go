@@ -11,6 +11,7 @@ var (
 	mockLogger = slog.New(slog.NewJSONHandler(io.Discard, nil))
 
 	encodeJson      = encoder.EncodeJson
+	encoders        = encoder.Default
 	findErrResponse = FindErrResponse
 )
 
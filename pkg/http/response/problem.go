@@ -0,0 +1,118 @@
+package response
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/encoder"
+	appValidator "github.com/raflynagachi/go-rest-api-starter/pkg/validator"
+)
+
+const (
+	ContentTypeJSON        = "application/json"
+	ContentTypeProblemJSON = "application/problem+json"
+
+	// problemTypeDefault means the problem has no additional semantics beyond the HTTP status code
+	problemTypeDefault = "about:blank"
+)
+
+type ErrorFormat int
+
+const (
+	// ErrorFormatDefault writes ErrResponse unless the client accepts application/problem+json
+	ErrorFormatDefault ErrorFormat = iota
+	// ErrorFormatProblem always writes RFC 7807 problem details
+	ErrorFormatProblem
+)
+
+var (
+	errorFormat = ErrorFormatDefault
+
+	// errorFormats negotiates the error format, application/json wins ties such as */*
+	errorFormats = encoder.NewRegistry(jsonMediaType(ContentTypeJSON), jsonMediaType(ContentTypeProblemJSON))
+)
+
+// SetErrorFormat sets the error response format used by WriteFromError.
+// The format is a package variable read without locking, set it once at startup.
+func SetErrorFormat(format ErrorFormat) {
+	errorFormat = format
+}
+
+// ProblemDetails is the RFC 7807 error representation
+type ProblemDetails struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes a single invalid field of the request
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+// NewProblemDetails builds problem details from the error response and its request
+func NewProblemDetails(r *http.Request, errResp ErrResponse) ProblemDetails {
+	problem := ProblemDetails{
+		Type:     problemTypeDefault,
+		Title:    http.StatusText(errResp.Code),
+		Status:   errResp.Code,
+		Detail:   errResp.Message,
+		Instance: r.URL.Path,
+	}
+
+	if valErrs, ok := errResp.Err.(validator.ValidationErrors); ok {
+		problem.Errors = newFieldErrors(valErrs)
+		problem.Detail = fmt.Sprintf("request has %d invalid field(s)", len(valErrs))
+	}
+
+	return problem
+}
+
+func newFieldErrors(valErrs validator.ValidationErrors) []FieldError {
+	fieldErrs := make([]FieldError, 0, len(valErrs))
+	for _, fieldErr := range valErrs {
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:   fieldPath(fieldErr),
+			Tag:     fieldErr.Tag(),
+			Message: fieldErr.Translate(appValidator.GetTranslator()),
+		})
+	}
+	return fieldErrs
+}
+
+// fieldPath returns the field namespace without the root struct name, e.g. "address.city"
+func fieldPath(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fieldErr.Field()
+}
+
+// wantsProblem reports whether the error should be written as problem details
+func wantsProblem(r *http.Request) bool {
+	if errorFormat == ErrorFormatProblem {
+		return true
+	}
+	enc, ok := errorFormats.Negotiate(r.Header.Get("Accept"))
+	return ok && enc.ContentType() == ContentTypeProblemJSON
+}
+
+// jsonMediaType encodes JSON as the media type it is named after
+type jsonMediaType string
+
+func (t jsonMediaType) ContentType() string {
+	return string(t)
+}
+
+func (jsonMediaType) Encode(w io.Writer, v interface{}) error {
+	return encoder.JSON.Encode(w, v)
+}
@@ -0,0 +1,176 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	appValidator "github.com/raflynagachi/go-rest-api-starter/pkg/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type problemTestAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type problemTestStruct struct {
+	Email   string             `json:"email" validate:"required,email"`
+	Address problemTestAddress `json:"address"`
+}
+
+func TestNewProblemDetails(t *testing.T) {
+	valErrs := appValidator.Validate(problemTestStruct{Email: "invalid"})
+	require.Error(t, valErrs)
+
+	tests := []struct {
+		name    string
+		errResp ErrResponse
+		want    ProblemDetails
+	}{
+		{
+			name: "success with validation errors",
+			errResp: ErrResponse{
+				Code:    http.StatusBadRequest,
+				Message: "email must be a valid email address,city is a required field",
+				Err:     valErrs,
+			},
+			want: ProblemDetails{
+				Type:     "about:blank",
+				Title:    "Bad Request",
+				Status:   http.StatusBadRequest,
+				Detail:   "request has 2 invalid field(s)",
+				Instance: "/users",
+				Errors: []FieldError{
+					{Field: "email", Tag: "email", Message: "email must be a valid email address"},
+					{Field: "address.city", Tag: "required", Message: "city is a required field"},
+				},
+			},
+		},
+		{
+			name: "success with plain error",
+			errResp: ErrResponse{
+				Code:    http.StatusNotFound,
+				Message: "data not found",
+				Err:     errors.New("data not found"),
+			},
+			want: ProblemDetails{
+				Type:     "about:blank",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "data not found",
+				Instance: "/users",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/users", http.NoBody)
+			assert.Equal(t, tt.want, NewProblemDetails(request, tt.errResp))
+		})
+	}
+}
+
+func TestWriteFromError_ProblemDetails(t *testing.T) {
+	valErrs := appValidator.Validate(problemTestStruct{Email: "user@mail.com"})
+	require.Error(t, valErrs)
+
+	tests := []struct {
+		name            string
+		format          ErrorFormat
+		accept          string
+		err             error
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "problem details negotiated by accept header",
+			format:          ErrorFormatDefault,
+			accept:          "application/problem+json",
+			err:             WrapErrBadRequest(valErrs),
+			wantCode:        http.StatusBadRequest,
+			wantContentType: ContentTypeProblemJSON,
+			wantBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request has 1 invalid field(s)","instance":"/users",` +
+				`"errors":[{"field":"address.city","tag":"required","message":"city is a required field"}]}`,
+		},
+		{
+			name:            "problem details forced by error format",
+			format:          ErrorFormatProblem,
+			err:             WrapErrNotFound(errors.New("data not found")),
+			wantCode:        http.StatusNotFound,
+			wantContentType: ContentTypeProblemJSON,
+			wantBody:        `{"type":"about:blank","title":"Not Found","status":404,"detail":"data not found","instance":"/users"}`,
+		},
+		{
+			name:            "problem details hides internal error",
+			format:          ErrorFormatProblem,
+			err:             WrapErrInternalServer(errors.New("connection refused")),
+			wantCode:        http.StatusInternalServerError,
+			wantContentType: ContentTypeProblemJSON,
+			wantBody:        `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal server error","instance":"/users"}`,
+		},
+		{
+			name:            "default format",
+			format:          ErrorFormatDefault,
+			accept:          "application/json",
+			err:             WrapErrNotFound(errors.New("data not found")),
+			wantCode:        http.StatusNotFound,
+			wantContentType: ContentTypeJSON,
+			wantBody:        `{"code":404,"message":"data not found"}`,
+		},
+		{
+			name:            "problem details refused by zero quality",
+			format:          ErrorFormatDefault,
+			accept:          "application/problem+json;q=0, */*",
+			err:             WrapErrNotFound(errors.New("data not found")),
+			wantCode:        http.StatusNotFound,
+			wantContentType: ContentTypeJSON,
+			wantBody:        `{"code":404,"message":"data not found"}`,
+		},
+		{
+			name:            "default format for any media type",
+			format:          ErrorFormatDefault,
+			accept:          "*/*",
+			err:             WrapErrNotFound(errors.New("data not found")),
+			wantCode:        http.StatusNotFound,
+			wantContentType: ContentTypeJSON,
+			wantBody:        `{"code":404,"message":"data not found"}`,
+		},
+		{
+			name:            "problem details preferred by quality",
+			format:          ErrorFormatDefault,
+			accept:          "application/json;q=0.5, application/problem+json",
+			err:             WrapErrNotFound(errors.New("data not found")),
+			wantCode:        http.StatusNotFound,
+			wantContentType: ContentTypeProblemJSON,
+			wantBody:        `{"type":"about:blank","title":"Not Found","status":404,"detail":"data not found","instance":"/users"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpErrorFormat := errorFormat
+			defer func() {
+				errorFormat = tmpErrorFormat
+			}()
+			SetErrorFormat(tt.format)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users", http.NoBody)
+			if tt.accept != "" {
+				request.Header.Set("Accept", tt.accept)
+			}
+
+			WriteFromError(recorder, request, tt.err, mockLogger)
+
+			assert.Equal(t, tt.wantCode, recorder.Code)
+			assert.Equal(t, tt.wantContentType, recorder.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.wantBody, recorder.Body.String())
+			assert.True(t, json.Valid(recorder.Body.Bytes()))
+		})
+	}
+}
@@ -1,33 +1,65 @@
 package response
 
 import (
+	"bytes"
 	"log/slog"
 	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/encoder"
 )
 
+var ErrNotAcceptable = errors.New("none of the accepted media types can be produced")
+
 type Response struct {
 	Code int         `json:"code"`
 	Data interface{} `json:"data"`
 	Meta `json:"-"`
 }
 
-func WriteResponse(w http.ResponseWriter, response Response, log *slog.Logger) {
-	log.Info(
-		"send response",
-		slog.Int("code", response.Code),
-		slog.String("path", response.Meta.Path),
-		slog.String("method", response.Meta.Method),
-	)
+// WriteResponse writes response in the media type negotiated from the Accept header, 406 when none matches.
+// Requests are logged once by the access log middleware.
+func WriteResponse(w http.ResponseWriter, r *http.Request, response Response, log *slog.Logger) {
+	writeResponse(w, r, response, nil, log)
+}
 
-	w.WriteHeader(response.Code)
-	err := encodeJson(w, response)
+// writeResponse is WriteResponse setting header only once the response is going to be written
+func writeResponse(w http.ResponseWriter, r *http.Request, response Response, header http.Header, log *slog.Logger) {
+	enc, ok := encoders.Negotiate(r.Header.Get("Accept"))
+	if !ok {
+		WriteFromError(w, r, WrapErrNotAcceptable(ErrNotAcceptable), log)
+		return
+	}
+
+	// the body is encoded first so the status and headers still reflect a failure
+	var body bytes.Buffer
+	err := enc.Encode(&body, response)
+	if errors.Is(err, encoder.ErrUnsupportedValue) {
+		WriteFromError(w, r, WrapErrNotAcceptable(err), log)
+		return
+	}
 	if err != nil {
+		log.Error(
+			"failed to encode response",
+			slog.String("path", response.Meta.Path),
+			slog.String("method", response.Meta.Method),
+			slog.String("error", err.Error()),
+		)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
 	}
+
+	for key, values := range header {
+		w.Header()[key] = values
+	}
+	w.Header().Set("Content-Type", enc.ContentType())
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(response.Code)
+	_, _ = w.Write(body.Bytes())
 }
 
 func WriteOKResponse(w http.ResponseWriter, r *http.Request, data interface{}, logger *slog.Logger) {
-	WriteResponse(w, Response{
+	WriteResponse(w, r, Response{
 		Code: http.StatusOK,
 		Data: data,
 		Meta: Meta{
@@ -36,3 +68,20 @@ func WriteOKResponse(w http.ResponseWriter, r *http.Request, data interface{}, l
 		},
 	}, logger)
 }
+
+// WriteCreatedResponse writes 201 with the Location of the created resource
+func WriteCreatedResponse(w http.ResponseWriter, r *http.Request, location string, data interface{}, logger *slog.Logger) {
+	writeResponse(w, r, Response{
+		Code: http.StatusCreated,
+		Data: data,
+		Meta: Meta{
+			Path:   r.URL.Path,
+			Method: r.Method,
+		},
+	}, http.Header{"Location": {location}}, logger)
+}
+
+// WriteNoContent writes 204 without a body
+func WriteNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,21 +2,39 @@ package response
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/raflynagachi/go-rest-api-starter/pkg/http/encoder"
 	"github.com/stretchr/testify/assert"
 )
 
+// failingEncoder fails every encoding
+type failingEncoder struct{}
+
+func (failingEncoder) ContentType() string { return "application/json" }
+
+func (failingEncoder) Encode(io.Writer, interface{}) error { return errors.New("some error") }
+
 func TestWriteResponse(t *testing.T) {
+	listResponse := Response{
+		Code: http.StatusOK,
+		Data: map[string]interface{}{
+			"data": []map[string]interface{}{{"id": 1, "email": "a@mail.com"}},
+		},
+	}
+
 	tests := []struct {
-		name         string
-		response     Response
-		setup        func()
-		expectedCode int
-		expectedBody string
+		name                string
+		response            Response
+		accept              string
+		setup               func()
+		expectedCode        int
+		expectedContentType string
+		expectedBody        string
 	}{
 		{
 			name: "success write response",
@@ -24,43 +42,90 @@ func TestWriteResponse(t *testing.T) {
 				Code: http.StatusOK,
 				Data: map[string]string{"key": "value"},
 			},
-			setup:        func() {},
-			expectedCode: http.StatusOK,
-			expectedBody: `{"code":200,"data":{"key":"value"}}`,
+			setup:               func() {},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        `{"code":200,"data":{"key":"value"}}`,
+		},
+		{
+			name: "success write xml response",
+			response: Response{
+				Code: http.StatusCreated,
+				Data: map[string]string{"key": "value"},
+			},
+			accept:              "application/json;q=0.5, application/xml",
+			setup:               func() {},
+			expectedCode:        http.StatusCreated,
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        `<?xml version="1.0" encoding="UTF-8"?><response><code>201</code><data><key>value</key></data></response>`,
+		},
+		{
+			name:                "success write csv list response",
+			response:            listResponse,
+			accept:              "text/csv",
+			setup:               func() {},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "email,ida@mail.com,1",
+		},
+		{
+			name: "failed due to csv of a single object",
+			response: Response{
+				Code: http.StatusOK,
+				Data: map[string]string{"key": "value"},
+			},
+			accept:              "text/csv",
+			setup:               func() {},
+			expectedCode:        http.StatusNotAcceptable,
+			expectedContentType: ContentTypeJSON,
+			expectedBody:        `{"code":406,"message":"value cannot be encoded in the media type"}`,
+		},
+		{
+			name:                "failed due to not acceptable",
+			response:            listResponse,
+			accept:              "image/png",
+			setup:               func() {},
+			expectedCode:        http.StatusNotAcceptable,
+			expectedContentType: ContentTypeJSON,
+			expectedBody:        `{"code":406,"message":"none of the accepted media types can be produced"}`,
 		},
 		{
 			name: "failed due to encoding error",
 			response: Response{
-				Code: http.StatusInternalServerError,
+				Code: http.StatusOK,
 				Data: map[string]string{"invalid": "invalid"},
 			},
 			setup: func() {
-				encodeJson = func(w http.ResponseWriter, data interface{}) error {
-					return errors.New("some error")
-				}
+				encoders = encoder.NewRegistry(failingEncoder{})
 			},
-			expectedCode: http.StatusInternalServerError,
-			expectedBody: "internal server error",
+			expectedCode:        http.StatusInternalServerError,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "internal server error",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tmpEncodeJson := encodeJson
+			tmpEncoders := encoders
 			defer func() {
-				encodeJson = tmpEncodeJson
+				encoders = tmpEncoders
 			}()
 
 			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+			if tt.accept != "" {
+				request.Header.Set("Accept", tt.accept)
+			}
 
 			tt.setup()
 
-			WriteResponse(recorder, tt.response, mockLogger)
+			WriteResponse(recorder, request, tt.response, mockLogger)
 
 			body := recorder.Body.String()
 			body = strings.ReplaceAll(body, "\n", "")
 
 			assert.Equal(t, tt.expectedCode, recorder.Code)
+			assert.Equal(t, tt.expectedContentType, recorder.Header().Get("Content-Type"))
 			assert.Equal(t, tt.expectedBody, body)
 		})
 	}
@@ -96,3 +161,34 @@ func TestWriteOKResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteCreatedResponse(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/users", http.NoBody)
+
+	WriteCreatedResponse(recorder, request, "/users/1", map[string]int{"id": 1}, mockLogger)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "/users/1", recorder.Header().Get("Location"))
+	assert.JSONEq(t, `{"code":201,"data":{"id":1}}`, recorder.Body.String())
+}
+
+func TestWriteCreatedResponse_NotAcceptable(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/users", http.NoBody)
+	request.Header.Set("Accept", "image/png")
+
+	WriteCreatedResponse(recorder, request, "/users/1", map[string]int{"id": 1}, mockLogger)
+
+	assert.Equal(t, http.StatusNotAcceptable, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Location"))
+}
+
+func TestWriteNoContent(t *testing.T) {
+	recorder := httptest.NewRecorder()
+
+	WriteNoContent(recorder)
+
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+}
@@ -0,0 +1,16 @@
+package jwt
+
+import "context"
+
+type claimsCtxKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated claims
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsCtxKey{}, claims)
+}
+
+// FromContext returns the authenticated claims stored in ctx, if any
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsCtxKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
@@ -0,0 +1,29 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	t.Run("claims stored in context", func(t *testing.T) {
+		claims := &Claims{Subject: "1"}
+		got, ok := FromContext(NewContext(context.Background(), claims))
+		assert.True(t, ok)
+		assert.Equal(t, claims, got)
+	})
+
+	t.Run("no claims in context", func(t *testing.T) {
+		got, ok := FromContext(context.Background())
+		assert.False(t, ok)
+		assert.Nil(t, got)
+	})
+
+	t.Run("nil claims in context", func(t *testing.T) {
+		got, ok := FromContext(NewContext(context.Background(), nil))
+		assert.False(t, ok)
+		assert.Nil(t, got)
+	})
+}
@@ -0,0 +1,235 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+var (
+	ErrMalformedToken   = errors.New("token is malformed")
+	ErrUnsupportedAlg   = errors.New("token signing algorithm is not supported")
+	ErrInvalidSignature = errors.New("token signature is invalid")
+	ErrTokenExpired     = errors.New("token is expired")
+	ErrTokenNotValidYet = errors.New("token is not valid yet")
+	ErrEmptyKey         = errors.New("jwt key must not be empty")
+
+	timeNow = time.Now
+)
+
+// Claims holds the registered claims used by the service
+type Claims struct {
+	Subject   string   `json:"sub,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+}
+
+// Principal returns the identity of the token owner, preferring email over subject
+func (c *Claims) Principal() string {
+	if c.Email != "" {
+		return c.Email
+	}
+	return c.Subject
+}
+
+// HasRole reports whether the token grants the given role
+func (c *Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+// Verifier validates signed tokens against a single key.
+// The algorithm is derived from the key so a token can not pick its own.
+type Verifier struct {
+	alg       string
+	secret    []byte
+	publicKey *rsa.PublicKey
+	leeway    time.Duration
+}
+
+// NewVerifier creates a Verifier from the given key.
+// A PEM encoded RSA public key selects RS256, any other value is used as HS256 secret.
+func NewVerifier(key string) (*Verifier, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+
+	if !strings.HasPrefix(strings.TrimSpace(key), "-----BEGIN") {
+		return &Verifier{alg: AlgHS256, secret: []byte(key)}, nil
+	}
+
+	publicKey, err := parseRSAPublicKey([]byte(key))
+	if err != nil {
+		return nil, errors.Wrap(err, "parseRSAPublicKey")
+	}
+	return &Verifier{alg: AlgRS256, publicKey: publicKey}, nil
+}
+
+// WithLeeway sets the tolerated clock skew when validating exp and nbf
+func (v *Verifier) WithLeeway(leeway time.Duration) *Verifier {
+	v.leeway = leeway
+	return v
+}
+
+// Alg returns the signing algorithm accepted by the verifier
+func (v *Verifier) Alg() string {
+	return v.alg
+}
+
+// Verify checks the token signature and time based claims then returns its claims
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	h := header{}
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, ErrMalformedToken
+	}
+	if h.Alg != v.alg {
+		return nil, ErrUnsupportedAlg
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+	if err := v.verifySignature(signingInput, signature); err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	now := timeNow()
+	if claims.ExpiresAt != 0 && now.After(time.Unix(claims.ExpiresAt, 0).Add(v.leeway)) {
+		return nil, ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Before(time.Unix(claims.NotBefore, 0).Add(-v.leeway)) {
+		return nil, ErrTokenNotValidYet
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) verifySignature(signingInput string, signature []byte) error {
+	switch v.alg {
+	case AlgHS256:
+		if !hmac.Equal(signHMAC(signingInput, v.secret), signature) {
+			return ErrInvalidSignature
+		}
+	case AlgRS256:
+		hashed := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, hashed[:], signature); err != nil {
+			return ErrInvalidSignature
+		}
+	default:
+		return ErrUnsupportedAlg
+	}
+	return nil
+}
+
+// SignHS256 creates a token signed with the given secret
+func SignHS256(claims *Claims, secret string) (string, error) {
+	signingInput, err := encodeSigningInput(AlgHS256, claims)
+	if err != nil {
+		return "", err
+	}
+
+	signature := signHMAC(signingInput, []byte(secret))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// SignRS256 creates a token signed with the given RSA private key
+func SignRS256(claims *Claims, privateKey *rsa.PrivateKey) (string, error) {
+	signingInput, err := encodeSigningInput(AlgRS256, claims)
+	if err != nil {
+		return "", err
+	}
+
+	hashed := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", errors.Wrap(err, "rsa.SignPKCS1v15")
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func encodeSigningInput(alg string, claims *Claims) (string, error) {
+	headerBytes, err := json.Marshal(header{Alg: alg, Typ: "JWT"})
+	if err != nil {
+		return "", errors.Wrap(err, "json.Marshal")
+	}
+
+	claimsBytes, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.Wrap(err, "json.Marshal")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(headerBytes) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsBytes), nil
+}
+
+func signHMAC(signingInput string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, dst interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+func parseRSAPublicKey(b []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("invalid PEM block")
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("key is not a RSA public key")
+		}
+		return rsaKey, nil
+	}
+
+	return x509.ParsePKCS1PublicKey(block.Bytes)
+}
@@ -0,0 +1,209 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateRSAKey(t *testing.T) (*rsa.PrivateKey, string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return privateKey, string(publicPEM)
+}
+
+func TestNewVerifier(t *testing.T) {
+	_, publicPEM := generateRSAKey(t)
+
+	tests := []struct {
+		name    string
+		key     string
+		wantAlg string
+		wantErr bool
+	}{
+		{
+			name:    "success with secret",
+			key:     "secret",
+			wantAlg: AlgHS256,
+		},
+		{
+			name:    "success with RSA public key",
+			key:     publicPEM,
+			wantAlg: AlgRS256,
+		},
+		{
+			name:    "failed due to empty key",
+			key:     "",
+			wantErr: true,
+		},
+		{
+			name:    "failed due to invalid PEM",
+			key:     "-----BEGIN PUBLIC KEY-----\ninvalid\n-----END PUBLIC KEY-----",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewVerifier(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewVerifier() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				assert.Equal(t, tt.wantAlg, got.Alg())
+			}
+		})
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	tmpTimeNow := timeNow
+	defer func() {
+		timeNow = tmpTimeNow
+	}()
+
+	mockNow := time.Date(2024, 7, 30, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return mockNow }
+
+	privateKey, publicPEM := generateRSAKey(t)
+	otherKey, _ := generateRSAKey(t)
+
+	mockClaims := &Claims{
+		Subject:   "1",
+		Email:     "user@mail.com",
+		ExpiresAt: mockNow.Add(time.Hour).Unix(),
+	}
+
+	hsVerifier, err := NewVerifier("secret")
+	require.NoError(t, err)
+	rsVerifier, err := NewVerifier(publicPEM)
+	require.NoError(t, err)
+
+	sign := func(claims *Claims, secret string) string {
+		token, err := SignHS256(claims, secret)
+		require.NoError(t, err)
+		return token
+	}
+	signRS := func(claims *Claims, key *rsa.PrivateKey) string {
+		token, err := SignRS256(claims, key)
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		token    string
+		want     *Claims
+		wantErr  error
+	}{
+		{
+			name:     "success HS256",
+			verifier: hsVerifier,
+			token:    sign(mockClaims, "secret"),
+			want:     mockClaims,
+		},
+		{
+			name:     "success RS256",
+			verifier: rsVerifier,
+			token:    signRS(mockClaims, privateKey),
+			want:     mockClaims,
+		},
+		{
+			name:     "failed due to wrong secret",
+			verifier: hsVerifier,
+			token:    sign(mockClaims, "other"),
+			wantErr:  ErrInvalidSignature,
+		},
+		{
+			name:     "failed due to wrong RSA key",
+			verifier: rsVerifier,
+			token:    signRS(mockClaims, otherKey),
+			wantErr:  ErrInvalidSignature,
+		},
+		{
+			name:     "failed due to algorithm mismatch",
+			verifier: rsVerifier,
+			token:    sign(mockClaims, publicPEM),
+			wantErr:  ErrUnsupportedAlg,
+		},
+		{
+			name:     "failed due to expired token",
+			verifier: hsVerifier,
+			token:    sign(&Claims{Subject: "1", ExpiresAt: mockNow.Add(-time.Minute).Unix()}, "secret"),
+			wantErr:  ErrTokenExpired,
+		},
+		{
+			name:     "failed due to token not valid yet",
+			verifier: hsVerifier,
+			token:    sign(&Claims{Subject: "1", NotBefore: mockNow.Add(time.Minute).Unix()}, "secret"),
+			wantErr:  ErrTokenNotValidYet,
+		},
+		{
+			name:     "failed due to malformed token",
+			verifier: hsVerifier,
+			token:    "invalid",
+			wantErr:  ErrMalformedToken,
+		},
+		{
+			name:     "failed due to malformed signature",
+			verifier: hsVerifier,
+			token:    strings.Join(strings.Split(sign(mockClaims, "secret"), ".")[:2], ".") + ".!!",
+			wantErr:  ErrMalformedToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.verifier.Verify(tt.token)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVerifier_WithLeeway(t *testing.T) {
+	tmpTimeNow := timeNow
+	defer func() {
+		timeNow = tmpTimeNow
+	}()
+
+	mockNow := time.Date(2024, 7, 30, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return mockNow }
+
+	v, err := NewVerifier("secret")
+	require.NoError(t, err)
+
+	token, err := SignHS256(&Claims{Subject: "1", ExpiresAt: mockNow.Add(-time.Second).Unix()}, "secret")
+	require.NoError(t, err)
+
+	_, err = v.Verify(token)
+	assert.Equal(t, ErrTokenExpired, err)
+
+	_, err = v.WithLeeway(time.Minute).Verify(token)
+	assert.NoError(t, err)
+}
+
+func TestClaims_Principal(t *testing.T) {
+	assert.Equal(t, "user@mail.com", (&Claims{Subject: "1", Email: "user@mail.com"}).Principal())
+	assert.Equal(t, "1", (&Claims{Subject: "1"}).Principal())
+}
+
+func TestClaims_HasRole(t *testing.T) {
+	assert.True(t, (&Claims{Roles: []string{"user", "admin"}}).HasRole("admin"))
+	assert.False(t, (&Claims{Roles: []string{"user"}}).HasRole("admin"))
+	assert.False(t, (&Claims{}).HasRole("admin"))
+}
@@ -5,17 +5,51 @@ import (
 	"time"
 )
 
-// formatAttributes converts slog attributes to map string of interface{}
+// formatAttributes converts slog attributes to map string of interface{}, groups become nested maps
 func formatAttributes(r slog.Record) map[string]interface{} {
 	fields := make(map[string]interface{}, r.NumAttrs())
 	r.Attrs(func(a slog.Attr) bool {
-		fields[a.Key] = a.Value.Any()
+		addAttribute(fields, a)
 		return true
 	})
 
 	return fields
 }
 
+// addAttribute adds a to fields following the slog rules: empty attributes and groups are dropped
+// and a group without a key is inlined
+func addAttribute(fields map[string]interface{}, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		fields[a.Key] = a.Value.Any()
+		return
+	}
+
+	attrs := a.Value.Group()
+	if len(attrs) == 0 {
+		return
+	}
+
+	group := fields
+	if a.Key != "" {
+		nested, ok := fields[a.Key].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{}, len(attrs))
+		}
+		group = nested
+	}
+	for _, ga := range attrs {
+		addAttribute(group, ga)
+	}
+	if a.Key != "" && len(group) > 0 {
+		fields[a.Key] = group
+	}
+}
+
 // GroupAttr creates a group for attributes
 func GroupAttr(key string, attr ...any) Attr {
 	return slog.Group(key, attr...)
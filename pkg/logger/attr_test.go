@@ -3,6 +3,7 @@ package logger
 import (
 	"fmt"
 	"log/slog"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -134,3 +135,24 @@ func TestErrAttr(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatAttributes_Groups(t *testing.T) {
+	rec := createTestRecord(LevelInfo, "test message",
+		GroupAttr("user", StringAttr("name", "john"), GroupAttr("address", StringAttr("city", "jakarta"))),
+		GroupAttr("", StringAttr("inlined", "yes")),
+		GroupAttr("empty"),
+		slog.Attr{},
+	)
+
+	expected := map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":    "john",
+			"address": map[string]interface{}{"city": "jakarta"},
+		},
+		"inlined": "yes",
+	}
+
+	if result := formatAttributes(rec); !reflect.DeepEqual(result, expected) {
+		t.Errorf("formatAttributes() = %v, want %v", result, expected)
+	}
+}
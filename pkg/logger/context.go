@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+type contextKey struct {
+	attrKey string
+	value   func(ctx context.Context) any
+}
+
+var (
+	contextKeysMu sync.RWMutex
+	contextKeys   []contextKey
+)
+
+// RegisterContextKey registers a context key whose value is appended as attrKey
+// to every record logged with a context, e.g. logger.InfoContext(ctx, ...).
+// Registering the same attrKey again replaces the previous context key.
+func RegisterContextKey(key any, attrKey string) {
+	RegisterContextFunc(attrKey, func(ctx context.Context) any {
+		return ctx.Value(key)
+	})
+}
+
+// RegisterContextFunc registers fn whose non-nil result is appended as attrKey
+// to every record logged with a context, for values derived from the context, e.g. trace IDs.
+// Registering the same attrKey again replaces the previous function.
+func RegisterContextFunc(attrKey string, fn func(ctx context.Context) any) {
+	contextKeysMu.Lock()
+	defer contextKeysMu.Unlock()
+
+	for i := range contextKeys {
+		if contextKeys[i].attrKey == attrKey {
+			contextKeys[i].value = fn
+			return
+		}
+	}
+	contextKeys = append(contextKeys, contextKey{attrKey: attrKey, value: fn})
+}
+
+// contextAttributes adds the registered context values to fields without overriding record attributes
+func contextAttributes(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	if ctx == nil {
+		return fields
+	}
+
+	contextKeysMu.RLock()
+	defer contextKeysMu.RUnlock()
+
+	for _, k := range contextKeys {
+		if _, ok := fields[k.attrKey]; ok {
+			continue
+		}
+		if val := k.value(ctx); val != nil {
+			fields[k.attrKey] = val
+		}
+	}
+	return fields
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type testContextKey struct{}
+
+func resetContextKeys(t *testing.T) {
+	contextKeysMu.Lock()
+	tmpContextKeys := contextKeys
+	contextKeys = nil
+	contextKeysMu.Unlock()
+
+	t.Cleanup(func() {
+		contextKeysMu.Lock()
+		contextKeys = tmpContextKeys
+		contextKeysMu.Unlock()
+	})
+}
+
+func TestRegisterContextKey(t *testing.T) {
+	resetContextKeys(t)
+
+	RegisterContextKey(testContextKey{}, "request_id")
+	RegisterContextKey("other", "request_id")
+
+	if len(contextKeys) != 1 {
+		t.Fatalf("RegisterContextKey() registered %d keys, want 1", len(contextKeys))
+	}
+	ctx := context.WithValue(context.Background(), "other", "req-1")
+	if got := contextKeys[0].value(ctx); got != "req-1" {
+		t.Errorf("RegisterContextKey() value = %v, want %v", got, "req-1")
+	}
+}
+
+func TestRegisterContextFunc(t *testing.T) {
+	resetContextKeys(t)
+
+	RegisterContextFunc("trace_id", func(ctx context.Context) any { return nil })
+	RegisterContextFunc("trace_id", func(ctx context.Context) any { return "trace-1" })
+
+	if len(contextKeys) != 1 {
+		t.Fatalf("RegisterContextFunc() registered %d keys, want 1", len(contextKeys))
+	}
+
+	got := contextAttributes(context.Background(), map[string]interface{}{})
+	if got["trace_id"] != "trace-1" {
+		t.Errorf("contextAttributes() trace_id = %v, want %v", got["trace_id"], "trace-1")
+	}
+}
+
+func TestContextAttributes(t *testing.T) {
+	resetContextKeys(t)
+	RegisterContextKey(testContextKey{}, "request_id")
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		fields map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{
+			name:   "append registered value",
+			ctx:    context.WithValue(context.Background(), testContextKey{}, "req-1"),
+			fields: map[string]interface{}{"key": "value"},
+			want:   map[string]interface{}{"key": "value", "request_id": "req-1"},
+		},
+		{
+			name:   "keep record attribute",
+			ctx:    context.WithValue(context.Background(), testContextKey{}, "req-1"),
+			fields: map[string]interface{}{"request_id": "from-record"},
+			want:   map[string]interface{}{"request_id": "from-record"},
+		},
+		{
+			name:   "missing value",
+			ctx:    context.Background(),
+			fields: map[string]interface{}{},
+			want:   map[string]interface{}{},
+		},
+		{
+			name:   "nil context",
+			ctx:    nil,
+			fields: map[string]interface{}{},
+			want:   map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := contextAttributes(tt.ctx, tt.fields)
+			gotJson, _ := json.Marshal(got)
+			wantJson, _ := json.Marshal(tt.want)
+			if string(gotJson) != string(wantJson) {
+				t.Errorf("contextAttributes() = %s, want %s", gotJson, wantJson)
+			}
+		})
+	}
+}
+
+func TestHandlers_AppendContextAttributes(t *testing.T) {
+	resetContextKeys(t)
+	RegisterContextKey(testContextKey{}, "request_id")
+
+	tests := []struct {
+		name       string
+		newHandler func(buf *bytes.Buffer) slog.Handler
+	}{
+		{
+			name: "ContextHandler",
+			newHandler: func(buf *bytes.Buffer) slog.Handler {
+				return NewContextHandler(buf, ContextHandlerOptions{})
+			},
+		},
+		{
+			name: "PrettyHandler",
+			newHandler: func(buf *bytes.Buffer) slog.Handler {
+				return NewPrettyHandler(buf, PrettyHandlerOptions{})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(tt.newHandler(&buf))
+
+			ctx := context.WithValue(context.Background(), testContextKey{}, "req-1")
+			log.InfoContext(ctx, "test message")
+
+			if !strings.Contains(buf.String(), `"request_id"`) || !strings.Contains(buf.String(), `"req-1"`) {
+				t.Errorf("Handle() output = %q, want request_id attribute", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+)
+
+const (
+	FormatPretty = "pretty" // colored and indented, for a console
+	FormatText   = "text"   // the ContextHandler line
+	FormatJSON   = "json"   // the JSONHandler object
+)
+
+// levelAll leaves the filtering to the runtime levels
+const levelAll = slog.Level(math.MinInt)
+
+// Sink is one output of a logger with its own format and level
+type Sink struct {
+	Writer io.Writer
+	Format string       // FormatPretty, FormatText or FormatJSON, the default is FormatText
+	Level  slog.Leveler // writes the records at or above it, also below the runtime level, nil follows the runtime level
+}
+
+// NewSinkHandler creates the handler writing to sink
+func NewSinkHandler(sink Sink, addSource bool) slog.Handler {
+	opts := slog.HandlerOptions{AddSource: addSource, Level: sink.Level}
+	if opts.Level == nil {
+		opts.Level = levelAll
+	}
+
+	var h slog.Handler
+	switch sink.Format {
+	case FormatPretty:
+		h = NewPrettyHandler(sink.Writer, PrettyHandlerOptions{SlogOpts: opts})
+	case FormatJSON:
+		h = NewJSONHandler(sink.Writer, JSONHandlerOptions{SlogOpts: opts})
+	default:
+		h = NewContextHandler(sink.Writer, ContextHandlerOptions{SlogOpts: opts})
+	}
+
+	if sink.Level == nil {
+		return &runtimeLevelHandler{next: h}
+	}
+	return h
+}
+
+// runtimeLevelHandler skips the records a LevelHandler only passed on for the sinks with a lower level
+type runtimeLevelHandler struct {
+	next slog.Handler
+}
+
+func (h *runtimeLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return !belowRuntimeLevel(ctx) && h.next.Enabled(ctx, level)
+}
+
+func (h *runtimeLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *runtimeLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &runtimeLevelHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *runtimeLevelHandler) WithGroup(name string) slog.Handler {
+	return &runtimeLevelHandler{next: h.next.WithGroup(name)}
+}
+
+// FanoutHandler sends every record to each of its handlers that is enabled for it
+type FanoutHandler struct {
+	handlers []slog.Handler
+}
+
+// NewFanoutHandler creates a handler writing to every handler
+func NewFanoutHandler(handlers ...slog.Handler) *FanoutHandler {
+	return &FanoutHandler{handlers: handlers}
+}
+
+func (h *FanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle writes r to every enabled handler, a failing handler does not stop the others
+func (h *FanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *FanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler.WithAttrs(attrs))
+	}
+	return &FanoutHandler{handlers: handlers}
+}
+
+func (h *FanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler.WithGroup(name))
+	}
+	return &FanoutHandler{handlers: handlers}
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type failingHandler struct {
+	slog.Handler
+}
+
+func (h failingHandler) Handle(context.Context, slog.Record) error {
+	return errors.New("sink failed")
+}
+
+func TestFanoutHandler(t *testing.T) {
+	var console, file bytes.Buffer
+	log := NewLogger(
+		WithLevel(LevelDebug),
+		WithSinks(
+			Sink{Writer: &console, Format: FormatText, Level: LevelInfo},
+			Sink{Writer: &file, Format: FormatJSON},
+		),
+	)
+
+	log.With("service", "api").WithGroup("request").Debug("debug only in file", "id", "req-1")
+	log.Info("in both")
+
+	if got := console.String(); strings.Contains(got, "debug only in file") || !strings.Contains(got, "[INFO] in both") {
+		t.Errorf("console = %q, want only the info record as text", got)
+	}
+
+	lines := strings.Split(strings.TrimSpace(file.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("file = %q, want 2 JSON lines", file.String())
+	}
+
+	var first map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", lines[0], err)
+	}
+	if first[slog.LevelKey] != "DEBUG" || first[slog.MessageKey] != "debug only in file" || first["service"] != "api" {
+		t.Errorf("first line = %v", first)
+	}
+	if request, _ := first["request"].(map[string]interface{}); request["id"] != "req-1" {
+		t.Errorf("first line request = %v, want the grouped id", first["request"])
+	}
+	if _, ok := first[slog.TimeKey]; !ok {
+		t.Errorf("first line = %v, want a time", first)
+	}
+}
+
+func TestFanoutHandler_SinkBelowRuntimeLevel(t *testing.T) {
+	var console, file bytes.Buffer
+	log := NewLogger(
+		WithLevel(LevelInfo),
+		WithSinks(
+			Sink{Writer: &console, Format: FormatText},
+			Sink{Writer: &file, Format: FormatText, Level: LevelDebug},
+		),
+	)
+	repoLog := Named(log, "repository")
+
+	log.Debug("debug only in file")
+	repoLog.Debug("named debug only in file")
+	log.Info("in both")
+
+	if got := console.String(); strings.Contains(got, "debug only in file") || !strings.Contains(got, "[INFO] in both") {
+		t.Errorf("console = %q, want only the info record following the runtime level", got)
+	}
+	for _, msg := range []string{"[DEBUG] debug only in file", "[DEBUG] named debug only in file", "[INFO] in both"} {
+		if got := file.String(); !strings.Contains(got, msg) {
+			t.Errorf("file = %q, want %q below the runtime level", got, msg)
+		}
+	}
+}
+
+func TestFanoutHandler_Handle(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewFanoutHandler(
+		failingHandler{Handler: slog.NewTextHandler(&buf, nil)},
+		NewSinkHandler(Sink{Writer: &buf, Format: FormatText, Level: LevelError}, false),
+		NewSinkHandler(Sink{Writer: &buf, Format: FormatText}, false),
+	)
+
+	if !h.Enabled(context.Background(), LevelDebug) {
+		t.Errorf("Enabled(Debug) = false, want true when one handler is enabled")
+	}
+
+	err := h.Handle(context.Background(), createTestRecord(LevelInfo, "still written"))
+	if err == nil || err.Error() != "sink failed" {
+		t.Errorf("Handle() error = %v, want the failing sink error", err)
+	}
+	if got := buf.String(); strings.Count(got, "still written") != 1 {
+		t.Errorf("output = %q, want the record once from the enabled sink", got)
+	}
+}
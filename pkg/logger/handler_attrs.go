@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+)
+
+// groupOrAttrs holds either a group name from WithGroup or the attributes from WithAttrs
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// withGroupOrAttrs returns a copy of goas extended with goa, handlers derived from the same parent must not share it
+func withGroupOrAttrs(goas []groupOrAttrs, goa groupOrAttrs) []groupOrAttrs {
+	extended := make([]groupOrAttrs, len(goas), len(goas)+1)
+	copy(extended, goas)
+	return append(extended, goa)
+}
+
+// recordFields converts the record attributes nested under the bound groups, after the bound attributes.
+// A record attribute overrides a bound attribute with the same key and groups left empty are dropped.
+func recordFields(goas []groupOrAttrs, r slog.Record, addSource bool) map[string]interface{} {
+	fields := formatAttributes(r)
+
+	for i := len(goas) - 1; i >= 0; i-- {
+		if goas[i].group != "" {
+			if len(fields) > 0 {
+				fields = map[string]interface{}{goas[i].group: fields}
+			}
+			continue
+		}
+
+		bound := make(map[string]interface{}, len(goas[i].attrs))
+		for _, a := range goas[i].attrs {
+			addAttribute(bound, a)
+		}
+		for k, v := range bound {
+			if _, ok := fields[k]; !ok {
+				fields[k] = v
+			}
+		}
+	}
+
+	if addSource && r.PC != 0 {
+		if _, ok := fields[slog.SourceKey]; !ok {
+			fields[slog.SourceKey] = recordSource(r.PC)
+		}
+	}
+	return fields
+}
+
+// recordSource formats the caller of the log call as file:line
+func recordSource(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+}
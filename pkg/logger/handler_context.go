@@ -15,14 +15,16 @@ type ContextHandlerOptions struct {
 
 type ContextHandler struct {
 	slog.Handler
-	l *log.Logger
+	l         *log.Logger
+	goas      []groupOrAttrs
+	addSource bool
 }
 
 // Handle processes and formats a log record into a structured log message
 func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
 	levelStr := r.Level.String()
 
-	fields, err := jsonMarshal(formatAttributes(r))
+	fields, err := jsonMarshal(contextAttributes(ctx, recordFields(h.goas, r, h.addSource)))
 	if err != nil {
 		return err
 	}
@@ -40,7 +42,28 @@ func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
 // NewContextHandler creates a new ContextHandler with provided options
 func NewContextHandler(out io.Writer, opts ContextHandlerOptions) slog.Handler {
 	return &ContextHandler{
-		Handler: slog.NewJSONHandler(out, &opts.SlogOpts),
-		l:       log.New(out, "", 0),
+		Handler:   slog.NewJSONHandler(out, &opts.SlogOpts),
+		l:         log.New(out, "", 0),
+		addSource: opts.SlogOpts.AddSource,
 	}
 }
+
+// WithAttrs returns a copy of the handler that adds attrs to every record
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.goas = withGroupOrAttrs(h.goas, groupOrAttrs{attrs: attrs})
+	return &h2
+}
+
+// WithGroup returns a copy of the handler that nests the following attributes under name
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.goas = withGroupOrAttrs(h.goas, groupOrAttrs{group: name})
+	return &h2
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -59,3 +61,117 @@ func TestContextHandler_Handle(t *testing.T) {
 		}
 	})
 }
+
+// contextHandlerFields logs through a ContextHandler and returns the decoded fields of the line
+func contextHandlerFields(t *testing.T, opts ContextHandlerOptions, log func(l *slog.Logger)) map[string]interface{} {
+	t.Helper()
+
+	var buf bytes.Buffer
+	log(slog.New(NewContextHandler(&buf, opts)))
+
+	line := buf.String()
+	start := strings.Index(line, "{")
+	if start < 0 {
+		t.Fatalf("no fields in output %q", line)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(line[start:]), &fields); err != nil {
+		t.Fatalf("invalid fields in output %q: %v", line, err)
+	}
+	return fields
+}
+
+func TestContextHandler_WithAttrsWithGroup(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *slog.Logger)
+		want map[string]interface{}
+	}{
+		{
+			name: "bound attributes are kept",
+			log: func(l *slog.Logger) {
+				l.With("service", "api").With(slog.Int("version", 2)).Info("msg", "key", "value")
+			},
+			want: map[string]interface{}{"service": "api", "version": float64(2), "key": "value"},
+		},
+		{
+			name: "record attribute overrides bound attribute",
+			log: func(l *slog.Logger) {
+				l.With("key", "bound").Info("msg", "key", "record")
+			},
+			want: map[string]interface{}{"key": "record"},
+		},
+		{
+			name: "groups nest the following attributes",
+			log: func(l *slog.Logger) {
+				l.With("service", "api").WithGroup("request").With("id", "req-1").WithGroup("user").Info("msg", "id", 7)
+			},
+			want: map[string]interface{}{
+				"service": "api",
+				"request": map[string]interface{}{
+					"id":   "req-1",
+					"user": map[string]interface{}{"id": float64(7)},
+				},
+			},
+		},
+		{
+			name: "empty groups are dropped",
+			log: func(l *slog.Logger) {
+				l.With("service", "api").WithGroup("request").WithGroup("user").Info("msg")
+			},
+			want: map[string]interface{}{"service": "api"},
+		},
+		{
+			name: "nested group attributes",
+			log: func(l *slog.Logger) {
+				l.Info("msg", GroupAttr("user", StringAttr("name", "john"), GroupAttr("address", StringAttr("city", "jakarta"))))
+			},
+			want: map[string]interface{}{
+				"user": map[string]interface{}{
+					"name":    "john",
+					"address": map[string]interface{}{"city": "jakarta"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := contextHandlerFields(t, ContextHandlerOptions{}, tt.log)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("derived handlers do not share attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		base := slog.New(NewContextHandler(&buf, ContextHandlerOptions{})).With("base", true)
+		first := base.With("first", 1)
+		base.With("second", 2).Info("msg")
+		first.Info("msg")
+
+		if got := buf.String(); strings.Count(got, `"first"`) != 1 || strings.Count(got, `"second"`) != 1 {
+			t.Errorf("output = %q, want first and second once each", got)
+		}
+	})
+}
+
+func TestContextHandler_AddSource(t *testing.T) {
+	got := contextHandlerFields(t, ContextHandlerOptions{SlogOpts: slog.HandlerOptions{AddSource: true}}, func(l *slog.Logger) {
+		l.Info("msg")
+	})
+
+	source, _ := got[slog.SourceKey].(string)
+	if !strings.Contains(source, "handler_context_test.go:") {
+		t.Errorf("source = %q, want the caller file and line", source)
+	}
+
+	got = contextHandlerFields(t, ContextHandlerOptions{}, func(l *slog.Logger) {
+		l.Info("msg", "key", "value")
+	})
+	if _, ok := got[slog.SourceKey]; ok {
+		t.Errorf("source = %v, want none without AddSource", got[slog.SourceKey])
+	}
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+)
+
+type JSONHandlerOptions struct {
+	SlogOpts slog.HandlerOptions
+}
+
+// JSONHandler writes one JSON object per record with the time, level, message,
+// attributes and context values, e.g. for files read by log shippers
+type JSONHandler struct {
+	slog.Handler
+	l         *log.Logger
+	goas      []groupOrAttrs
+	addSource bool
+}
+
+// Handle processes and formats a log record into a JSON line
+func (h *JSONHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := contextAttributes(ctx, recordFields(h.goas, r, h.addSource))
+	fields[slog.TimeKey] = r.Time.Format("2006-01-02T15:04:05.000Z07:00")
+	fields[slog.LevelKey] = r.Level.String()
+	fields[slog.MessageKey] = r.Message
+
+	b, err := jsonMarshal(fields)
+	if err != nil {
+		return err
+	}
+
+	h.l.Println(string(b))
+	return nil
+}
+
+// NewJSONHandler creates a new JSONHandler with provided options
+func NewJSONHandler(out io.Writer, opts JSONHandlerOptions) slog.Handler {
+	return &JSONHandler{
+		Handler:   slog.NewJSONHandler(out, &opts.SlogOpts),
+		l:         log.New(out, "", 0),
+		addSource: opts.SlogOpts.AddSource,
+	}
+}
+
+// WithAttrs returns a copy of the handler that adds attrs to every record
+func (h *JSONHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.goas = withGroupOrAttrs(h.goas, groupOrAttrs{attrs: attrs})
+	return &h2
+}
+
+// WithGroup returns a copy of the handler that nests the following attributes under name
+func (h *JSONHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.goas = withGroupOrAttrs(h.goas, groupOrAttrs{group: name})
+	return &h2
+}
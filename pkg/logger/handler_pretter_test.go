@@ -88,3 +88,28 @@ func TestFormatLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestPrettyHandler_WithAttrsWithGroup(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewPrettyHandler(&buf, PrettyHandlerOptions{})
+
+	derived := handler.WithAttrs([]slog.Attr{slog.String("service", "api")}).WithGroup("request")
+	if _, ok := derived.(*PrettyHandler); !ok {
+		t.Fatalf("WithAttrs().WithGroup() = %T, want *logger.PrettyHandler", derived)
+	}
+
+	record := createTestRecord(slog.LevelInfo, "test message", slog.String("id", "req-1"))
+	if err := derived.Handle(context.Background(), record); err != nil {
+		t.Fatalf("Handle() returned an error: %v", err)
+	}
+
+	expectedFields, _ := json.MarshalIndent(map[string]interface{}{
+		"service": "api",
+		"request": map[string]interface{}{"id": "req-1"},
+	}, "", "  ")
+	expected := fmt.Sprintf("%s %s %s %s\n", record.Time.Format("[15:05:05.000]"), formatLevel(record.Level), record.Message, color.WhiteString(string(expectedFields)))
+
+	if output := buf.String(); output != expected {
+		t.Errorf("Handle() output = %q, want %q", output, expected)
+	}
+}
@@ -17,13 +17,15 @@ type PrettyHandlerOptions struct {
 
 type PrettyHandler struct {
 	slog.Handler
-	l *log.Logger
+	l         *log.Logger
+	goas      []groupOrAttrs
+	addSource bool
 }
 
 func (h *PrettyHandler) Handle(ctx context.Context, r slog.Record) error {
 	levelStr := formatLevel(r.Level)
 
-	b, err := jsonMarshalIndent(formatAttributes(r), "", "  ")
+	b, err := jsonMarshalIndent(contextAttributes(ctx, recordFields(h.goas, r, h.addSource)), "", "  ")
 	if err != nil {
 		return err
 	}
@@ -57,9 +59,30 @@ func formatLevel(level slog.Level) string {
 
 func NewPrettyHandler(out io.Writer, opts PrettyHandlerOptions) slog.Handler {
 	h := &PrettyHandler{
-		Handler: slog.NewJSONHandler(out, &opts.SlogOpts),
-		l:       log.New(out, "", 0),
+		Handler:   slog.NewJSONHandler(out, &opts.SlogOpts),
+		l:         log.New(out, "", 0),
+		addSource: opts.SlogOpts.AddSource,
 	}
 
 	return h
 }
+
+// WithAttrs returns a copy of the handler that adds attrs to every record
+func (h *PrettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.goas = withGroupOrAttrs(h.goas, groupOrAttrs{attrs: attrs})
+	return &h2
+}
+
+// WithGroup returns a copy of the handler that nests the following attributes under name
+func (h *PrettyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.goas = withGroupOrAttrs(h.goas, groupOrAttrs{group: name})
+	return &h2
+}
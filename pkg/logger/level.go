@@ -0,0 +1,221 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LoggerKey is the attribute holding the name of a logger created by Named
+const LoggerKey = "logger"
+
+// Levels holds the runtime log level of the loggers created by NewLogger,
+// a base level and overrides per logger name
+type Levels struct {
+	initial   Level
+	base      slog.LevelVar
+	mu        sync.Mutex // serializes override writes, reads load the map without locking
+	overrides atomic.Pointer[map[string]Level]
+}
+
+// NewLevels creates levels starting at level without overrides
+func NewLevels(level Level) *Levels {
+	l := &Levels{initial: level}
+	l.base.Set(level)
+	l.overrides.Store(&map[string]Level{})
+	return l
+}
+
+// Level returns the base level, Levels is a slog.Leveler
+func (l *Levels) Level() Level {
+	return l.base.Level()
+}
+
+// SetLevel sets the base level of every logger without an override
+func (l *Levels) SetLevel(level Level) {
+	l.base.Set(level)
+}
+
+// ToggleDebug switches the base level between debug and the initial level and returns the new level
+func (l *Levels) ToggleDebug() Level {
+	level := LevelDebug
+	if l.Level() == LevelDebug {
+		level = l.initial
+	}
+	l.SetLevel(level)
+	return level
+}
+
+// SetOverride sets the level of the logger name and of its dotted children, e.g. "repository" covers "repository.postgres"
+func (l *Levels) SetOverride(name string, level Level) {
+	l.updateOverrides(func(overrides map[string]Level) {
+		overrides[name] = level
+	})
+}
+
+// RemoveOverride returns the logger name to the base level
+func (l *Levels) RemoveOverride(name string) {
+	l.updateOverrides(func(overrides map[string]Level) {
+		delete(overrides, name)
+	})
+}
+
+// Overrides returns a copy of the overrides by logger name
+func (l *Levels) Overrides() map[string]Level {
+	current := *l.overrides.Load()
+	overrides := make(map[string]Level, len(current))
+	for name, level := range current {
+		overrides[name] = level
+	}
+	return overrides
+}
+
+// Reset restores the initial level and removes every override
+func (l *Levels) Reset() {
+	l.SetLevel(l.initial)
+	l.updateOverrides(func(overrides map[string]Level) {
+		clear(overrides)
+	})
+}
+
+// LevelFor returns the level of the logger name, the closest override or the base level
+func (l *Levels) LevelFor(name string) Level {
+	overrides := *l.overrides.Load()
+	for name != "" && len(overrides) > 0 {
+		if level, ok := overrides[name]; ok {
+			return level
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return l.Level()
+}
+
+func (l *Levels) updateOverrides(update func(overrides map[string]Level)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	overrides := l.Overrides()
+	update(overrides)
+	l.overrides.Store(&overrides)
+}
+
+// WatchSignals changes the levels on SIGUSR1, toggling debug, and on SIGUSR2, resetting them,
+// where the platform supports these signals. The returned function stops watching.
+func (l *Levels) WatchSignals(log *Logger) (stop func()) {
+	if len(levelSignals) == 0 {
+		return func() {}
+	}
+
+	sigs := make(chan os.Signal, 1)
+	for sig := range levelSignals {
+		signal.Notify(sigs, sig)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case sig := <-sigs:
+				levelSignals[sig](l)
+				log.Info("log level changed", slog.String("signal", sig.String()), slog.String("level", l.Level().String()))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+		wg.Wait()
+	}
+}
+
+// ParseLevel parses a level name such as debug, INFO or warn+2
+func ParseLevel(s string) (Level, error) {
+	var level Level
+	err := level.UnmarshalText([]byte(s))
+	return level, err
+}
+
+// LevelHandler drops the records below the runtime level of its logger name. Records below it
+// but at or above one of the floor levels, the levels of sinks, go on marked for these sinks only.
+type LevelHandler struct {
+	next   slog.Handler
+	levels *Levels
+	name   string
+	floors []slog.Leveler
+}
+
+// belowRuntimeLevelKey marks the context of a record passed on only for the floor levels
+type belowRuntimeLevelKey struct{}
+
+func belowRuntimeLevel(ctx context.Context) bool {
+	below, _ := ctx.Value(belowRuntimeLevelKey{}).(bool)
+	return below
+}
+
+// NewLevelHandler wraps next with the runtime levels
+func NewLevelHandler(next slog.Handler, levels *Levels) *LevelHandler {
+	return &LevelHandler{next: next, levels: levels}
+}
+
+// Handler returns the wrapped handler
+func (h *LevelHandler) Handler() slog.Handler {
+	return h.next
+}
+
+func (h *LevelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if level >= h.levels.LevelFor(h.name) {
+		return true
+	}
+	for _, floor := range h.floors {
+		if level >= floor.Level() {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *LevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.levels.LevelFor(h.name) {
+		ctx = context.WithValue(ctx, belowRuntimeLevelKey{}, true)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *LevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LevelHandler{next: h.next.WithAttrs(attrs), levels: h.levels, name: h.name, floors: h.floors}
+}
+
+func (h *LevelHandler) WithGroup(name string) slog.Handler {
+	return &LevelHandler{next: h.next.WithGroup(name), levels: h.levels, name: h.name, floors: h.floors}
+}
+
+// LevelsOf returns the runtime levels of a logger created by NewLogger
+func LevelsOf(l *Logger) (*Levels, bool) {
+	h, ok := l.Handler().(*LevelHandler)
+	if !ok {
+		return nil, false
+	}
+	return h.levels, true
+}
+
+// Named returns a logger whose level can be overridden by name, its records carry the name as "logger"
+func Named(l *Logger, name string) *Logger {
+	if h, ok := l.Handler().(*LevelHandler); ok {
+		l = slog.New(&LevelHandler{next: h.next, levels: h.levels, name: name, floors: h.floors})
+	}
+	return l.With(slog.String(LoggerKey, name))
+}
@@ -0,0 +1,14 @@
+//go:build linux || darwin || freebsd
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// levelSignals maps the signals watched by Levels.WatchSignals to their level change
+var levelSignals = map[os.Signal]func(l *Levels){
+	syscall.SIGUSR1: func(l *Levels) { l.ToggleDebug() },
+	syscall.SIGUSR2: (*Levels).Reset,
+}
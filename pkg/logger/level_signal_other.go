@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd)
+
+package logger
+
+import "os"
+
+// levelSignals is empty where SIGUSR1 and SIGUSR2 do not exist
+var levelSignals = map[os.Signal]func(l *Levels){}
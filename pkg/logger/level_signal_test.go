@@ -0,0 +1,38 @@
+//go:build linux || darwin || freebsd
+
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLevels_WatchSignals(t *testing.T) {
+	levels := NewLevels(LevelInfo)
+	stop := levels.WatchSignals(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+	defer stop()
+
+	waitLevel := func(want Level) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for levels.Level() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("Level() = %v, want %v", levels.Level(), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	waitLevel(LevelDebug)
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	waitLevel(LevelInfo)
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	levels := NewLevels(LevelInfo)
+
+	levels.SetOverride("repository", LevelDebug)
+	levels.SetOverride("repository.postgres.tx", LevelError)
+
+	tests := []struct {
+		name string
+		want Level
+	}{
+		{name: "", want: LevelInfo},
+		{name: "handler", want: LevelInfo},
+		{name: "repository", want: LevelDebug},
+		{name: "repository.postgres", want: LevelDebug},
+		{name: "repository.postgres.tx", want: LevelError},
+		{name: "repositoryx", want: LevelInfo},
+	}
+	for _, tt := range tests {
+		if got := levels.LevelFor(tt.name); got != tt.want {
+			t.Errorf("LevelFor(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	overrides := levels.Overrides()
+	overrides["handler"] = LevelError
+	if got := levels.LevelFor("handler"); got != LevelInfo {
+		t.Errorf("Overrides() is not a copy, LevelFor(handler) = %v", got)
+	}
+
+	levels.RemoveOverride("repository")
+	if got := levels.LevelFor("repository.postgres"); got != LevelInfo {
+		t.Errorf("LevelFor() after RemoveOverride = %v, want %v", got, LevelInfo)
+	}
+
+	if got := levels.ToggleDebug(); got != LevelDebug {
+		t.Errorf("ToggleDebug() = %v, want %v", got, LevelDebug)
+	}
+	if got := levels.ToggleDebug(); got != LevelInfo {
+		t.Errorf("ToggleDebug() again = %v, want %v", got, LevelInfo)
+	}
+
+	levels.SetLevel(LevelWarn)
+	levels.Reset()
+	if levels.Level() != LevelInfo || len(levels.Overrides()) != 0 {
+		t.Errorf("Reset() level = %v, overrides = %v", levels.Level(), levels.Overrides())
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	var buf bytes.Buffer
+	levels := NewLevels(LevelInfo)
+	log := slog.New(NewLevelHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelDebug}), levels))
+	repoLog := Named(log, "repository").With("component", "db")
+
+	log.Debug("root debug")
+	repoLog.Debug("repository debug")
+	levels.SetOverride("repository", LevelDebug)
+	log.Debug("root debug after override")
+	repoLog.Debug("repository debug after override")
+	levels.SetLevel(LevelDebug)
+	log.Debug("root debug after set level")
+
+	output := buf.String()
+	for _, msg := range []string{"repository debug after override", "root debug after set level", "logger=repository component=db"} {
+		if !strings.Contains(output, msg) {
+			t.Errorf("output = %q, want %q", output, msg)
+		}
+	}
+	for _, msg := range []string{`"root debug"`, `"repository debug"`, "root debug after override"} {
+		if strings.Contains(output, msg) {
+			t.Errorf("output = %q, want no %q", output, msg)
+		}
+	}
+
+	if got, ok := LevelsOf(repoLog); !ok || got != levels {
+		t.Errorf("LevelsOf() = %v, %v, want the shared levels", got, ok)
+	}
+	if _, ok := LevelsOf(slog.New(slog.NewTextHandler(&buf, nil))); ok {
+		t.Errorf("LevelsOf() of a foreign logger = true, want false")
+	}
+}
+
+func TestNewLogger_WithLevels(t *testing.T) {
+	levels := NewLevels(LevelWarn)
+	log := NewLogger(WithEnv("test"), WithLevels(levels))
+
+	if log.Enabled(context.Background(), LevelInfo) {
+		t.Errorf("Enabled(Info) = true, want false")
+	}
+	levels.SetLevel(LevelDebug)
+	if !log.Enabled(context.Background(), LevelDebug) {
+		t.Errorf("Enabled(Debug) after SetLevel = false, want true")
+	}
+}
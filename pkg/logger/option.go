@@ -4,11 +4,15 @@ type Options struct {
 	Level     Level
 	AddSource bool
 	Env       string
+	Redact    RedactOptions
+	Levels    *Levels
+	Sinks     []Sink
+	Sampling  *SampleOptions
 }
 
 type Option func(*Options)
 
-// WithLevel sets the log level. The default level is Info.
+// WithLevel sets the initial log level. The default level is Info.
 func WithLevel(level Level) Option {
 	return func(o *Options) {
 		o.Level = level
@@ -28,3 +32,31 @@ func WithEnv(env string) Option {
 		o.Env = env
 	}
 }
+
+// WithRedaction sets what is masked before output. The default is DefaultRedactOptions.
+func WithRedaction(opts RedactOptions) Option {
+	return func(o *Options) {
+		o.Redact = opts
+	}
+}
+
+// WithLevels shares runtime levels between loggers. By default every logger gets its own starting at the level option.
+func WithLevels(levels *Levels) Option {
+	return func(o *Options) {
+		o.Levels = levels
+	}
+}
+
+// WithSinks replaces the output of the environment with sinks, e.g. pretty to the console and JSON to a file
+func WithSinks(sinks ...Sink) Option {
+	return func(o *Options) {
+		o.Sinks = sinks
+	}
+}
+
+// WithSampling drops repeated records with the same level and message over opts. Records are not sampled by default.
+func WithSampling(opts SampleOptions) Option {
+	return func(o *Options) {
+		o.Sampling = &opts
+	}
+}
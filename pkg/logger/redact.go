@@ -0,0 +1,255 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces every masked value
+const RedactedValue = "[REDACTED]"
+
+// RedactOptions configures which values are masked before output
+type RedactOptions struct {
+	// Keys are attribute or JSON object keys masked at any depth, case-insensitive, e.g. password
+	Keys []string
+	// Paths are dotted attribute paths through groups and JSON string values, case-insensitive,
+	// e.g. user.email or request_body.user.email
+	Paths []string
+	// Patterns are masked inside every string value and message, e.g. emails and bearer tokens
+	Patterns []*regexp.Regexp
+}
+
+var (
+	// DefaultRedactOptions masks common secrets, bearer tokens, JWTs and emails
+	DefaultRedactOptions = RedactOptions{
+		Keys: []string{
+			"password", "passwd", "secret", "token", "access_token", "refresh_token",
+			"authorization", "cookie", "set-cookie", "api_key", "apikey", "jwt_key",
+		},
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9\-._~+/]+=*`),
+			regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+			regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+		},
+	}
+)
+
+// Merge returns the options of o extended with other
+func (o RedactOptions) Merge(other RedactOptions) RedactOptions {
+	return RedactOptions{
+		Keys:     append(append([]string(nil), o.Keys...), other.Keys...),
+		Paths:    append(append([]string(nil), o.Paths...), other.Paths...),
+		Patterns: append(append([]*regexp.Regexp(nil), o.Patterns...), other.Patterns...),
+	}
+}
+
+// redactor masks attribute values according to RedactOptions
+type redactor struct {
+	keys     map[string]bool
+	paths    map[string]bool
+	parents  map[string]bool // strict prefixes of paths, a JSON string value under one of them is decoded
+	patterns []*regexp.Regexp
+	jsonKeys *regexp.Regexp // "key": value pairs inside JSON text, also when truncated
+}
+
+func newRedactor(opts RedactOptions) *redactor {
+	r := &redactor{
+		keys:     make(map[string]bool, len(opts.Keys)),
+		paths:    make(map[string]bool, len(opts.Paths)),
+		parents:  make(map[string]bool),
+		patterns: opts.Patterns,
+	}
+
+	quotedKeys := make([]string, 0, len(opts.Keys))
+	for _, key := range opts.Keys {
+		key = strings.ToLower(key)
+		r.keys[key] = true
+		quotedKeys = append(quotedKeys, regexp.QuoteMeta(key))
+	}
+	if len(quotedKeys) > 0 {
+		r.jsonKeys = regexp.MustCompile(`(?i)("(?:` + strings.Join(quotedKeys, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+	}
+
+	for _, path := range opts.Paths {
+		path = strings.ToLower(path)
+		r.paths[path] = true
+		for i := strings.LastIndex(path, "."); i > 0; i = strings.LastIndex(path[:i], ".") {
+			r.parents[path[:i]] = true
+		}
+	}
+	return r
+}
+
+// attr masks a, path holds the enclosing group names
+func (r *redactor) attr(path []string, a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	attrPath := path
+	if a.Key != "" {
+		attrPath = append(path[:len(path):len(path)], a.Key)
+		if r.keys[strings.ToLower(a.Key)] || r.matchPath(attrPath) {
+			return slog.String(a.Key, RedactedValue)
+		}
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		attrs := make([]slog.Attr, 0, len(group))
+		for _, ga := range group {
+			attrs = append(attrs, r.attr(attrPath, ga))
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindString:
+		return slog.String(a.Key, r.string(attrPath, a.Value.String()))
+	case slog.KindAny:
+		return slog.Any(a.Key, r.any(attrPath, a.Value.Any()))
+	default:
+		return a
+	}
+}
+
+// any masks arbitrary values through their JSON form, e.g. a model with a CreatedBy email
+func (r *redactor) any(path []string, v any) any {
+	if err, ok := v.(error); ok {
+		return r.string(path, err.Error())
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return r.string(path, fmt.Sprint(v))
+	}
+	decoded, err := decodeJSON(b)
+	if err != nil {
+		return r.string(path, string(b))
+	}
+	return r.value(path, decoded)
+}
+
+// value masks a decoded JSON value
+func (r *redactor) value(path []string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, val := range v {
+			valPath := append(path[:len(path):len(path)], key)
+			if r.keys[strings.ToLower(key)] || r.matchPath(valPath) {
+				v[key] = RedactedValue
+				continue
+			}
+			v[key] = r.value(valPath, val)
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = r.value(path, v[i])
+		}
+		return v
+	case string:
+		return r.string(path, v)
+	default:
+		return v
+	}
+}
+
+// string masks a string value, a JSON document under a configured path is decoded to follow the path
+func (r *redactor) string(path []string, s string) string {
+	if r.parents[strings.ToLower(strings.Join(path, "."))] {
+		if trimmed := strings.TrimSpace(s); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			if decoded, err := decodeJSON([]byte(trimmed)); err == nil {
+				if b, err := json.Marshal(r.value(path, decoded)); err == nil {
+					return string(b)
+				}
+			}
+		}
+	}
+
+	if r.jsonKeys != nil {
+		s = r.jsonKeys.ReplaceAllString(s, `${1}"`+RedactedValue+`"`)
+	}
+	return r.message(s)
+}
+
+// message masks the patterns only, attribute keys and paths do not apply
+func (r *redactor) message(s string) string {
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllString(s, RedactedValue)
+	}
+	return s
+}
+
+func (r *redactor) matchPath(path []string) bool {
+	return len(r.paths) > 0 && r.paths[strings.ToLower(strings.Join(path, "."))]
+}
+
+// decodeJSON keeps numbers as json.Number so large integers survive the round trip
+func decodeJSON(b []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// RedactHandler masks sensitive values of every record before handing it to the next handler
+type RedactHandler struct {
+	next     slog.Handler
+	redactor *redactor
+	groups   []string
+}
+
+// NewRedactHandler wraps next with the redaction of opts
+func NewRedactHandler(next slog.Handler, opts RedactOptions) *RedactHandler {
+	return &RedactHandler{
+		next:     next,
+		redactor: newRedactor(opts),
+	}
+}
+
+// Handler returns the wrapped handler
+func (h *RedactHandler) Handler() slog.Handler {
+	return h.next
+}
+
+func (h *RedactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, h.redactor.message(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactor.attr(h.groups, a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		redacted = append(redacted, h.redactor.attr(h.groups, a))
+	}
+	return &RedactHandler{
+		next:     h.next.WithAttrs(redacted),
+		redactor: h.redactor,
+		groups:   h.groups,
+	}
+}
+
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &RedactHandler{
+		next:     h.next.WithGroup(name),
+		redactor: h.redactor,
+		groups:   append(h.groups[:len(h.groups):len(h.groups)], name),
+	}
+}
@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+type redactUser struct {
+	Name      string `json:"name"`
+	CreatedBy string `json:"created_by"`
+	Password  string `json:"password"`
+}
+
+// redactedOutput logs through a RedactHandler over a JSON handler and returns the decoded record attributes
+func redactedOutput(t *testing.T, opts RedactOptions, log func(l *slog.Logger)) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	next := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	log(slog.New(NewRedactHandler(next, opts)))
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid output %q: %v", buf.String(), err)
+	}
+	return got
+}
+
+func TestRedactHandler(t *testing.T) {
+	opts := DefaultRedactOptions.Merge(RedactOptions{
+		Keys:     []string{"ssn"},
+		Paths:    []string{"user.phone", "request_body.user.phone", "request.headers.x-api-key"},
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`\b\d{4}-\d{4}-\d{4}-\d{4}\b`)},
+	})
+
+	tests := []struct {
+		name string
+		log  func(l *slog.Logger)
+		want map[string]any
+	}{
+		{
+			name: "keys are masked case-insensitively",
+			log: func(l *slog.Logger) {
+				l.Info("login", slog.String("Password", "hunter2"), slog.String("SSN", "123"), slog.Int("attempt", 1))
+			},
+			want: map[string]any{"msg": "login", "Password": RedactedValue, "SSN": RedactedValue, "attempt": float64(1)},
+		},
+		{
+			name: "patterns are masked in messages and values",
+			log: func(l *slog.Logger) {
+				l.Info("created by john@example.com",
+					slog.String("auth", "Bearer abc.def-123"),
+					slog.String("card", "paid with 4111-1111-1111-1111"),
+				)
+			},
+			want: map[string]any{
+				"msg":  "created by " + RedactedValue,
+				"auth": RedactedValue,
+				"card": "paid with " + RedactedValue,
+			},
+		},
+		{
+			name: "nested groups",
+			log: func(l *slog.Logger) {
+				l.Info("nested",
+					slog.Group("user",
+						slog.String("name", "john"),
+						slog.String("phone", "0812"),
+						slog.Group("auth", slog.String("token", "t0k3n"), slog.String("email", "john@example.com")),
+					),
+					slog.Group("other", slog.String("phone", "0812")),
+				)
+			},
+			want: map[string]any{
+				"msg": "nested",
+				"user": map[string]any{
+					"name":  "john",
+					"phone": RedactedValue,
+					"auth":  map[string]any{"token": RedactedValue, "email": RedactedValue},
+				},
+				"other": map[string]any{"phone": "0812"},
+			},
+		},
+		{
+			name: "WithGroup and WithAttrs keep the path",
+			log: func(l *slog.Logger) {
+				l.WithGroup("request").With(slog.String("password", "hunter2")).
+					WithGroup("headers").Info("grouped", slog.String("x-api-key", "key"), slog.String("accept", "*/*"))
+			},
+			want: map[string]any{
+				"msg": "grouped",
+				"request": map[string]any{
+					"password": RedactedValue,
+					"headers":  map[string]any{"x-api-key": RedactedValue, "accept": "*/*"},
+				},
+			},
+		},
+		{
+			name: "JSON string values follow paths and keys",
+			log: func(l *slog.Logger) {
+				l.Info("body",
+					slog.String("request_body", `{"user":{"name":"john","phone":"0812"},"id":12345678901234567890}`),
+					slog.String("response_body", `{"name":"john","password":"hun\"ter2","nested":{"token":123}`),
+				)
+			},
+			want: map[string]any{
+				"msg":           "body",
+				"request_body":  `{"id":12345678901234567890,"user":{"name":"john","phone":"[REDACTED]"}}`,
+				"response_body": `{"name":"john","password":"[REDACTED]","nested":{"token":"[REDACTED]"}`,
+			},
+		},
+		{
+			name: "structs and errors",
+			log: func(l *slog.Logger) {
+				l.Info("model",
+					slog.Any("user", redactUser{Name: "john", CreatedBy: "admin@example.com", Password: "hunter2"}),
+					slog.Any("users", []redactUser{{Name: "jane", CreatedBy: "jane@example.com"}}),
+					ErrAttr(errors.New("duplicate email john@example.com")),
+				)
+			},
+			want: map[string]any{
+				"msg":   "model",
+				"user":  map[string]any{"name": "john", "created_by": RedactedValue, "password": RedactedValue},
+				"users": []any{map[string]any{"name": "jane", "created_by": RedactedValue, "password": RedactedValue}},
+				"error": "duplicate email " + RedactedValue,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactedOutput(t, opts, tt.log)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("output = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHandler_Enabled(t *testing.T) {
+	next := slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn})
+	h := NewRedactHandler(next, DefaultRedactOptions)
+
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Errorf("Enabled(Info) = true, want false")
+	}
+	if !h.Enabled(context.Background(), slog.LevelError) {
+		t.Errorf("Enabled(Error) = false, want true")
+	}
+}
+
+func TestRedactOptions_Merge(t *testing.T) {
+	base := RedactOptions{Keys: []string{"password"}}
+	merged := base.Merge(RedactOptions{Keys: []string{"ssn"}, Paths: []string{"user.email"}})
+
+	if !reflect.DeepEqual(merged.Keys, []string{"password", "ssn"}) || !reflect.DeepEqual(merged.Paths, []string{"user.email"}) {
+		t.Errorf("Merge() = %+v", merged)
+	}
+	if !reflect.DeepEqual(base.Keys, []string{"password"}) {
+		t.Errorf("Merge() modified the receiver: %+v", base)
+	}
+}
@@ -0,0 +1,341 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// backupTimeFormat sorts lexically in time order
+const backupTimeFormat = "20060102T150405.000"
+
+var (
+	timeNow = time.Now
+)
+
+// RotateOptions configures when a RotatingFile starts a new file and which rotated files are kept
+type RotateOptions struct {
+	MaxSize    int64         // bytes per file, 0 never rotates by size
+	MaxAge     time.Duration // since the file was started, 0 never rotates by age
+	MaxBackups int           // rotated files kept, 0 keeps all
+	Compress   bool          // gzip rotated files
+}
+
+// RotatingFile is an io.WriteCloser appending to path, the full file is renamed
+// to name-<time>.ext, or name-<time>-<n>.ext when that name is taken,
+// optionally compressed, and a new file is started
+type RotatingFile struct {
+	path string
+	opts RotateOptions
+
+	mu        sync.Mutex
+	file      *os.File
+	size      int64
+	startedAt time.Time // when writing to the current file started, the age of MaxAge
+
+	cleanMu sync.Mutex     // serializes compression and removal of rotated files
+	wg      sync.WaitGroup // pending compression and removal
+}
+
+// NewRotatingFile opens path for appending, creating it and its directory when missing
+func NewRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrap(err, "os.MkdirAll")
+	}
+
+	f := &RotatingFile{path: path, opts: opts}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	if err := f.resumeAge(); err != nil {
+		f.file.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p, rotating first when p does not fit the size limit or the file is too old.
+// A single write larger than MaxSize goes to a file of its own.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate starts a new file, e.g. on an external request
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// Close closes the file and waits for the pending compression and removal of rotated files
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.wg.Wait()
+	return err
+}
+
+func (f *RotatingFile) shouldRotate(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.opts.MaxSize > 0 && f.size+n > f.opts.MaxSize {
+		return true
+	}
+	return f.opts.MaxAge > 0 && timeNow().Sub(f.startedAt) >= f.opts.MaxAge
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "os.OpenFile")
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.Wrap(err, "File.Stat")
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.startedAt = timeNow()
+	return nil
+}
+
+// resumeAge keeps the age of a file written before a restart: it was started
+// when the newest backup was rotated, or at its last write without backups
+func (f *RotatingFile) resumeAge() error {
+	if f.size == 0 {
+		return nil
+	}
+
+	backups, err := f.backups()
+	if err != nil {
+		return err
+	}
+	if len(backups) > 0 {
+		f.startedAt = backups[len(backups)-1].rotatedAt
+		return nil
+	}
+
+	info, err := f.file.Stat()
+	if err != nil {
+		return errors.Wrap(err, "File.Stat")
+	}
+	f.startedAt = info.ModTime()
+	return nil
+}
+
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return errors.Wrap(err, "File.Close")
+	}
+	f.file = nil
+
+	backup, err := f.backupName(timeNow())
+	if err != nil {
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+	if err := os.Rename(f.path, backup); err != nil {
+		// keep writing to the current file
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		return errors.Wrap(err, "os.Rename")
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.cleanMu.Lock()
+		defer f.cleanMu.Unlock()
+
+		if f.opts.Compress {
+			if err := compressFile(backup); err != nil {
+				// the logger can not log its own output failures
+				fmt.Fprintf(os.Stderr, "logger: failed to compress %s: %v\n", backup, err)
+			}
+		}
+		if err := f.removeOldBackups(); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: failed to remove old log files: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// backupName returns dir/name-<time>.ext for path dir/name.ext, adding a counter
+// as dir/name-<time>-<n>.ext while a backup with that name, compressed or not, exists
+func (f *RotatingFile) backupName(t time.Time) (string, error) {
+	ext := filepath.Ext(f.path)
+	base := fmt.Sprintf("%s-%s", strings.TrimSuffix(f.path, ext), t.Format(backupTimeFormat))
+
+	name := base + ext
+	for n := 1; ; n++ {
+		taken, err := exists(name)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			if taken, err = exists(name + ".gz"); err != nil {
+				return "", err
+			}
+		}
+		if !taken {
+			return name, nil
+		}
+		name = fmt.Sprintf("%s-%d%s", base, n, ext)
+	}
+}
+
+func exists(path string) (bool, error) {
+	_, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "os.Lstat")
+	}
+	return true, nil
+}
+
+type backup struct {
+	path      string
+	rotatedAt time.Time
+	n         int // counter of names rotated in the same millisecond
+}
+
+// backups returns the rotated files of path from the oldest
+func (f *RotatingFile) backups() ([]backup, error) {
+	dir := filepath.Dir(f.path)
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "os.ReadDir")
+	}
+
+	backups := []backup{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz"), ext)
+		stamp, counter, hasCounter := strings.Cut(stamp, "-")
+		// names are formatted in the local time of timeNow
+		rotatedAt, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		n := 0
+		if hasCounter {
+			if n, err = strconv.Atoi(counter); err != nil || n < 1 {
+				continue
+			}
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), rotatedAt: rotatedAt, n: n})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].rotatedAt.Equal(backups[j].rotatedAt) {
+			return backups[i].rotatedAt.Before(backups[j].rotatedAt)
+		}
+		return backups[i].n < backups[j].n
+	})
+	return backups, nil
+}
+
+func (f *RotatingFile) removeOldBackups() error {
+	if f.opts.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := f.backups()
+	if err != nil {
+		return err
+	}
+	for len(backups) > f.opts.MaxBackups {
+		if err := os.Remove(backups[0].path); err != nil {
+			return errors.Wrap(err, "os.Remove")
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// compressFile replaces path with path.gz
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "os.Open")
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "os.OpenFile")
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(path + ".gz")
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err != nil {
+		dst.Close()
+		return errors.Wrap(err, "io.Copy")
+	}
+	if err = zw.Close(); err != nil {
+		dst.Close()
+		return errors.Wrap(err, "gzip.Writer.Close")
+	}
+	if err = dst.Close(); err != nil {
+		return errors.Wrap(err, "File.Close")
+	}
+
+	src.Close()
+	return errors.Wrap(os.Remove(path), "os.Remove")
+}
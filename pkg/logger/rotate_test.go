@@ -0,0 +1,242 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// fakeClock replaces timeNow, every call advances it by a millisecond so backup names differ
+func fakeClock(t *testing.T) *time.Time {
+	t.Helper()
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	original := timeNow
+	timeNow = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+	t.Cleanup(func() { timeNow = original })
+	return &now
+}
+
+func readDir(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRotatingFile_Size(t *testing.T) {
+	fakeClock(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "app.log")
+
+	f, err := NewRotatingFile(path, RotateOptions{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewRotatingFile() error = %v", err)
+	}
+
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n", "eeee\n", "0123456789abc\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	got := readDir(t, filepath.Join(dir, "logs"))
+	want := []string{"app-20240501T100000.004.log", "app-20240501T100000.006.log", "app.log"}
+	if len(got) != len(want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("files = %v, want %v", got, want)
+		}
+	}
+
+	current, _ := os.ReadFile(path)
+	if string(current) != "0123456789abc\n" {
+		t.Errorf("current file = %q, want the oversized line alone", current)
+	}
+	previous, _ := os.ReadFile(filepath.Join(dir, "logs", want[1]))
+	if string(previous) != "eeee\n" {
+		t.Errorf("last backup = %q, want %q", previous, "eeee\n")
+	}
+
+	if _, err := f.Write([]byte("late\n")); err != os.ErrClosed {
+		t.Errorf("Write() after Close error = %v, want %v", err, os.ErrClosed)
+	}
+}
+
+func TestRotatingFile_AgeAndCompress(t *testing.T) {
+	now := fakeClock(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	if err := os.WriteFile(path, []byte("existing\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, *now, *now); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewRotatingFile(path, RotateOptions{MaxAge: time.Hour, Compress: true})
+	if err != nil {
+		t.Fatalf("NewRotatingFile() error = %v", err)
+	}
+
+	f.Write([]byte("first\n"))
+	*now = now.Add(time.Hour)
+	f.Write([]byte("second\n"))
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	got := readDir(t, dir)
+	if len(got) != 2 || got[1] != "app.log" || filepath.Ext(got[0]) != ".gz" {
+		t.Fatalf("files = %v, want a compressed backup and app.log", got)
+	}
+
+	gz, err := os.Open(filepath.Join(dir, got[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gz.Close()
+	zr, err := gzip.NewReader(gz)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	backup, _ := io.ReadAll(zr)
+	if string(backup) != "existing\nfirst\n" {
+		t.Errorf("backup = %q, want %q", backup, "existing\nfirst\n")
+	}
+
+	current, _ := os.ReadFile(path)
+	if string(current) != "second\n" {
+		t.Errorf("current file = %q, want %q", current, "second\n")
+	}
+}
+
+func TestRotatingFile_AgeAfterRestart(t *testing.T) {
+	tests := []struct {
+		name     string
+		backups  []string
+		modTime  time.Duration // last write of app.log before now
+		wantKept bool
+	}{
+		{
+			name:     "rotates a file started by a rotation over max age ago",
+			backups:  []string{"app-20240501T080000.000.log.gz", "app-20240501T085000.000.log"},
+			modTime:  -time.Minute,
+			wantKept: false,
+		},
+		{
+			name:     "keeps a file started by a recent rotation",
+			backups:  []string{"app-20240501T080000.000.log", "app-20240501T093000.000.log"},
+			modTime:  -time.Minute,
+			wantKept: true,
+		},
+		{
+			name:     "rotates a file without backups last written over max age ago",
+			modTime:  -2 * time.Hour,
+			wantKept: false,
+		},
+		{
+			name:     "keeps a file without backups written recently",
+			modTime:  -time.Minute,
+			wantKept: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := fakeClock(t)
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.log")
+
+			for _, name := range tt.backups {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("old\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := os.WriteFile(path, []byte("existing\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			modTime := now.Add(tt.modTime)
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewRotatingFile(path, RotateOptions{MaxAge: time.Hour})
+			if err != nil {
+				t.Fatalf("NewRotatingFile() error = %v", err)
+			}
+			f.Write([]byte("new\n"))
+			if err := f.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			current, _ := os.ReadFile(path)
+			kept := string(current) == "existing\nnew\n"
+			if kept != tt.wantKept {
+				t.Errorf("current file = %q, kept = %t, want %t", current, kept, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestRotatingFile_BackupNameCollision(t *testing.T) {
+	now := fakeClock(t)
+	original := timeNow
+	timeNow = func() time.Time { return *now } // every rotation in the same millisecond
+	t.Cleanup(func() { timeNow = original })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := NewRotatingFile(path, RotateOptions{MaxSize: 5, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewRotatingFile() error = %v", err)
+	}
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	got := readDir(t, dir)
+	want := []string{"app-20240501T100000.000-1.log", "app-20240501T100000.000-2.log", "app.log"}
+	if len(got) != len(want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("files = %v, want %v", got, want)
+		}
+	}
+
+	for name, content := range map[string]string{want[0]: "bbbb\n", want[1]: "cccc\n", "app.log": "dddd\n"} {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", name, data, content)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultSampleInterval is the counting window used when SampleOptions.Interval is zero
+const DefaultSampleInterval = time.Second
+
+// SampleRule writes the First records of a message per interval, then every Thereafter-th one.
+// A zero Thereafter drops the rest.
+type SampleRule struct {
+	First      int
+	Thereafter int
+}
+
+// SampleOptions configures the sampling of records with the same level and message.
+// Error records and above are never sampled.
+type SampleOptions struct {
+	Interval time.Duration
+	Default  SampleRule
+	Levels   map[Level]SampleRule // replaces Default for a level, e.g. fewer Debug records
+	OnDrop   func(level Level)    // called for every dropped record, e.g. to count them in metrics
+	Exempt   []string             // names of the loggers created by Named never sampled, with their dotted children
+}
+
+type sampleKey struct {
+	level Level
+	msg   string
+}
+
+// sampler holds the counters shared by a SamplingHandler and the handlers derived from it
+type sampler struct {
+	opts SampleOptions
+	root slog.Handler // receives the drop summaries outside of any group
+
+	mu            sync.Mutex
+	counts        map[sampleKey]int
+	windowStart   time.Time
+	windowDropped int
+
+	dropped atomic.Uint64
+}
+
+// SamplingHandler drops repeated records over the sampling rules and reports
+// the dropped count of each interval with a Warn record
+type SamplingHandler struct {
+	next    slog.Handler
+	sampler *sampler
+	exempt  bool // the logger name is exempt
+	grouped bool // later attributes cannot name the logger
+}
+
+// NewSamplingHandler wraps next with the sampling of opts
+func NewSamplingHandler(next slog.Handler, opts SampleOptions) *SamplingHandler {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultSampleInterval
+	}
+	return &SamplingHandler{
+		next: next,
+		sampler: &sampler{
+			opts:   opts,
+			root:   next,
+			counts: map[sampleKey]int{},
+		},
+	}
+}
+
+// Dropped returns the number of records dropped since the handler was created
+func (h *SamplingHandler) Dropped() uint64 {
+	return h.sampler.dropped.Load()
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.exempt {
+		return h.next.Handle(ctx, r)
+	}
+
+	keep, summary := h.sampler.sample(r)
+	if summary != nil {
+		// the summary is best effort, the record itself is what the caller asked for
+		_ = h.sampler.root.Handle(ctx, *summary)
+	}
+	if !keep {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	exempt := h.exempt
+	if !h.grouped {
+		for _, attr := range attrs {
+			if attr.Key == LoggerKey {
+				exempt = h.sampler.exempt(attr.Value.String())
+			}
+		}
+	}
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), sampler: h.sampler, exempt: exempt, grouped: h.grouped}
+}
+
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), sampler: h.sampler, exempt: h.exempt, grouped: true}
+}
+
+// exempt reports whether the logger name or one of its dotted parents is exempt from sampling
+func (s *sampler) exempt(name string) bool {
+	for _, exempt := range s.opts.Exempt {
+		if name == exempt || strings.HasPrefix(name, exempt+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// sample reports whether r is kept and, when a new interval starts after drops, the summary of the last one
+func (s *sampler) sample(r slog.Record) (keep bool, summary *slog.Record) {
+	if r.Level >= LevelError {
+		return true, nil
+	}
+
+	now := timeNow()
+
+	s.mu.Lock()
+	if now.Sub(s.windowStart) >= s.opts.Interval {
+		if s.windowDropped > 0 {
+			rec := slog.NewRecord(now, LevelWarn, "log records dropped by sampling", 0)
+			rec.AddAttrs(
+				slog.Int("dropped", s.windowDropped),
+				slog.Duration("interval", s.opts.Interval),
+			)
+			summary = &rec
+		}
+		clear(s.counts)
+		s.windowStart = now
+		s.windowDropped = 0
+	}
+
+	key := sampleKey{level: r.Level, msg: r.Message}
+	s.counts[key]++
+	keep = s.rule(r.Level).keep(s.counts[key])
+	if !keep {
+		s.windowDropped++
+	}
+	s.mu.Unlock()
+
+	if !keep {
+		s.dropped.Add(1)
+		if s.opts.OnDrop != nil {
+			s.opts.OnDrop(r.Level)
+		}
+	}
+	return keep, summary
+}
+
+func (s *sampler) rule(level Level) SampleRule {
+	if rule, ok := s.opts.Levels[level]; ok {
+		return rule
+	}
+	return s.opts.Default
+}
+
+// keep reports whether the n-th record of an interval, counting from 1, is written
+func (r SampleRule) keep(n int) bool {
+	if n <= r.First {
+		return true
+	}
+	return r.Thereafter > 0 && (n-r.First)%r.Thereafter == 0
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSampleRule_keep(t *testing.T) {
+	tests := []struct {
+		name string
+		rule SampleRule
+		want string // kept records out of the first 10
+	}{
+		{name: "first then every third", rule: SampleRule{First: 2, Thereafter: 3}, want: "1 2 5 8"},
+		{name: "first only", rule: SampleRule{First: 3}, want: "1 2 3"},
+		{name: "every other", rule: SampleRule{Thereafter: 2}, want: "2 4 6 8 10"},
+		{name: "drop all", rule: SampleRule{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept := []string{}
+			for n := 1; n <= 10; n++ {
+				if tt.rule.keep(n) {
+					kept = append(kept, strconv.Itoa(n))
+				}
+			}
+			if got := strings.Join(kept, " "); got != tt.want {
+				t.Errorf("kept = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamplingHandler(t *testing.T) {
+	now := fakeClock(t)
+
+	var buf bytes.Buffer
+	dropped := map[Level]int{}
+	h := NewSamplingHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelDebug}), SampleOptions{
+		Interval: time.Minute,
+		Default:  SampleRule{First: 2, Thereafter: 5},
+		Levels:   map[Level]SampleRule{LevelDebug: {First: 1}},
+		OnDrop:   func(level Level) { dropped[level]++ },
+	})
+	log := slog.New(h).WithGroup("request")
+
+	for i := 0; i < 10; i++ {
+		log.Info("http request")
+		log.Debug("cache miss")
+		log.Error("query failed")
+	}
+	log.Info("other message")
+
+	output := buf.String()
+	counts := map[string]int{
+		`msg="http request"`:  3, // the 1st, 2nd and 7th
+		`msg="cache miss"`:    1,
+		`msg="query failed"`:  10,
+		`msg="other message"`: 1,
+	}
+	for msg, want := range counts {
+		if got := strings.Count(output, msg); got != want {
+			t.Errorf("count of %s = %d, want %d", msg, got, want)
+		}
+	}
+
+	if h.Dropped() != 16 || dropped[LevelInfo] != 7 || dropped[LevelDebug] != 9 {
+		t.Errorf("Dropped() = %d, OnDrop = %v, want 16 split 7 info and 9 debug", h.Dropped(), dropped)
+	}
+
+	// a new interval resets the counters and reports the drops of the last one at the root
+	buf.Reset()
+	*now = now.Add(time.Minute)
+	log.Info("http request")
+
+	output = buf.String()
+	if !strings.Contains(output, `level=WARN msg="log records dropped by sampling" dropped=16 interval=1m0s`) {
+		t.Errorf("output = %q, want the drop summary outside the group", output)
+	}
+	if !strings.Contains(output, `msg="http request"`) {
+		t.Errorf("output = %q, want the first record of the new interval", output)
+	}
+}
+
+func TestNewLogger_WithSampling(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(WithSinks(Sink{Writer: &buf}), WithSampling(SampleOptions{Default: SampleRule{First: 1}}))
+
+	log.Info("repeated")
+	log.Info("repeated")
+
+	if got := strings.Count(buf.String(), "repeated"); got != 1 {
+		t.Errorf("output = %q, want the record once", buf.String())
+	}
+}
+
+func TestNewLogger_WithSamplingExempt(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(WithSinks(Sink{Writer: &buf}), WithSampling(SampleOptions{Default: SampleRule{First: 1}, Exempt: []string{"access"}}))
+	accessLog := Named(log, "access")
+	childLog := Named(log, "access.admin")
+	groupedLog := log.WithGroup("request").With(LoggerKey, "access")
+
+	for i := 0; i < 3; i++ {
+		accessLog.Info("http request")
+		childLog.Info("admin request")
+		groupedLog.Info("grouped request")
+		log.Info("repeated")
+	}
+
+	counts := map[string]int{
+		"http request":    3,
+		"admin request":   3,
+		"grouped request": 1, // a grouped attribute does not name the logger
+		"repeated":        1,
+	}
+	for msg, want := range counts {
+		if got := strings.Count(buf.String(), msg); got != want {
+			t.Errorf("count of %q = %d, want %d", msg, got, want)
+		}
+	}
+}
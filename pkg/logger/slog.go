@@ -10,26 +10,50 @@ func NewLogger(opts ...Option) *slog.Logger {
 	config := &Options{
 		Level:     slog.LevelInfo,
 		AddSource: true,
+		Redact:    DefaultRedactOptions,
 	}
 
 	for _, opt := range opts {
 		opt(config)
 	}
 
+	levels := config.Levels
+	if levels == nil {
+		levels = NewLevels(config.Level)
+	}
+
 	options := &slog.HandlerOptions{
 		AddSource: config.AddSource,
-		Level:     config.Level,
+		Level:     levels,
 	}
 
 	h := NewPrettyHandler(os.Stdout, PrettyHandlerOptions{SlogOpts: *options})
-	switch config.Env {
-	case "production":
+	switch {
+	case len(config.Sinks) > 0:
+		handlers := make([]slog.Handler, 0, len(config.Sinks))
+		for _, sink := range config.Sinks {
+			handlers = append(handlers, NewSinkHandler(sink, config.AddSource))
+		}
+		h = NewFanoutHandler(handlers...)
+	case config.Env == "production":
 		h = NewContextHandler(os.Stdout, ContextHandlerOptions{SlogOpts: *options})
-	case "test":
+	case config.Env == "test":
 		h = NewContextHandler(io.Discard, ContextHandlerOptions{SlogOpts: *options})
 	}
 
-	logger := slog.New(h)
+	h = NewRedactHandler(h, config.Redact)
+	if config.Sampling != nil {
+		h = NewSamplingHandler(h, *config.Sampling)
+	}
+
+	levelHandler := NewLevelHandler(h, levels)
+	for _, sink := range config.Sinks {
+		if sink.Level != nil {
+			levelHandler.floors = append(levelHandler.floors, sink.Level)
+		}
+	}
+
+	logger := slog.New(levelHandler)
 	slog.SetDefault(logger)
 
 	return logger
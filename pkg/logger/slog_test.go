@@ -51,7 +51,17 @@ func TestNewLogger(t *testing.T) {
 				t.Fatal("Expected handler to be non-nil")
 			}
 
-			handlerType := fmt.Sprintf("%T", handler)
+			levelHandler, ok := handler.(*LevelHandler)
+			if !ok {
+				t.Fatalf("Expected handler type *logger.LevelHandler, got %T", handler)
+			}
+
+			redactHandler, ok := levelHandler.Handler().(*RedactHandler)
+			if !ok {
+				t.Fatalf("Expected handler type *logger.RedactHandler, got %T", levelHandler.Handler())
+			}
+
+			handlerType := fmt.Sprintf("%T", redactHandler.Handler())
 			if handlerType != tt.expectedHandlerType {
 				t.Errorf("Expected handler type %s, got %s", tt.expectedHandlerType, handlerType)
 			}
@@ -0,0 +1,51 @@
+package metrics
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	value atomicFloat
+}
+
+// Inc increments the counter by 1
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Add increments the counter by delta, negative values are ignored
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.value.Add(delta)
+}
+
+// Value returns the current value
+func (c *Counter) Value() float64 {
+	return c.value.Load()
+}
+
+type CounterVec struct {
+	vec *metricVec
+}
+
+// NewCounterVec creates a counter partitioned by the given labels
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		vec: newMetricVec(name, help, labelNames, func() any { return &Counter{} }),
+	}
+}
+
+// WithLabelValues returns the counter for the label values, in the order of the label names
+func (c *CounterVec) WithLabelValues(labelValues ...string) *Counter {
+	return c.vec.child(labelValues).(*Counter)
+}
+
+func (c *CounterVec) Collect() []Family {
+	family := Family{Name: c.vec.name, Help: c.vec.help, Type: TypeCounter}
+	for _, child := range c.vec.sortedChildren() {
+		family.Samples = append(family.Samples, Sample{
+			Labels: child.labels,
+			Value:  child.metric.(*Counter).Value(),
+		})
+	}
+	return []Family{family}
+}
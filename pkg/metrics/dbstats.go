@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"database/sql"
+)
+
+// StatsGetter is implemented by *sql.DB and *sqlx.DB
+type StatsGetter interface {
+	Stats() sql.DBStats
+}
+
+type dbStatsCollector struct {
+	db     StatsGetter
+	labels []Label
+}
+
+// NewDBStatsCollector exposes the connection pool statistics of db labeled with db_name
+func NewDBStatsCollector(db StatsGetter, dbName string) Collector {
+	return &dbStatsCollector{
+		db:     db,
+		labels: []Label{{Name: "db_name", Value: dbName}},
+	}
+}
+
+func (c *dbStatsCollector) Collect() []Family {
+	stats := c.db.Stats()
+
+	family := func(name, help, typ string, value float64) Family {
+		return Family{
+			Name:    name,
+			Help:    help,
+			Type:    typ,
+			Samples: []Sample{{Labels: c.labels, Value: value}},
+		}
+	}
+
+	return []Family{
+		family("go_sql_max_open_connections", "Maximum number of open connections to the database.", TypeGauge, float64(stats.MaxOpenConnections)),
+		family("go_sql_open_connections", "The number of established connections both in use and idle.", TypeGauge, float64(stats.OpenConnections)),
+		family("go_sql_in_use_connections", "The number of connections currently in use.", TypeGauge, float64(stats.InUse)),
+		family("go_sql_idle_connections", "The number of idle connections.", TypeGauge, float64(stats.Idle)),
+		family("go_sql_wait_count_total", "The total number of connections waited for.", TypeCounter, float64(stats.WaitCount)),
+		family("go_sql_wait_duration_seconds_total", "The total time blocked waiting for a new connection.", TypeCounter, stats.WaitDuration.Seconds()),
+		family("go_sql_max_idle_closed_total", "The total number of connections closed due to SetMaxIdleConns.", TypeCounter, float64(stats.MaxIdleClosed)),
+		family("go_sql_max_idle_time_closed_total", "The total number of connections closed due to SetConnMaxIdleTime.", TypeCounter, float64(stats.MaxIdleTimeClosed)),
+		family("go_sql_max_lifetime_closed_total", "The total number of connections closed due to SetConnMaxLifetime.", TypeCounter, float64(stats.MaxLifetimeClosed)),
+	}
+}
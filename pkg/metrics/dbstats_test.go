@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStatsGetter struct {
+	stats sql.DBStats
+}
+
+func (m mockStatsGetter) Stats() sql.DBStats {
+	return m.stats
+}
+
+func TestDBStatsCollector(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(NewDBStatsCollector(mockStatsGetter{stats: sql.DBStats{
+		MaxOpenConnections: 10,
+		OpenConnections:    4,
+		InUse:              3,
+		Idle:               1,
+		WaitCount:          2,
+		WaitDuration:       1500 * time.Millisecond,
+	}}, "go-rest-api-starter"))
+
+	var sb strings.Builder
+	require.NoError(t, r.WriteText(&sb))
+	output := sb.String()
+
+	for _, want := range []string{
+		"# TYPE go_sql_open_connections gauge\n",
+		`go_sql_max_open_connections{db_name="go-rest-api-starter"} 10`,
+		`go_sql_open_connections{db_name="go-rest-api-starter"} 4`,
+		`go_sql_in_use_connections{db_name="go-rest-api-starter"} 3`,
+		`go_sql_idle_connections{db_name="go-rest-api-starter"} 1`,
+		"# TYPE go_sql_wait_count_total counter\n",
+		`go_sql_wait_count_total{db_name="go-rest-api-starter"} 2`,
+		`go_sql_wait_duration_seconds_total{db_name="go-rest-api-starter"} 1.5`,
+		`go_sql_max_lifetime_closed_total{db_name="go-rest-api-starter"} 0`,
+	} {
+		assert.Contains(t, output, want)
+	}
+}
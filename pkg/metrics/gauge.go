@@ -0,0 +1,54 @@
+package metrics
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	value atomicFloat
+}
+
+func (g *Gauge) Set(value float64) {
+	g.value.Set(value)
+}
+
+func (g *Gauge) Inc() {
+	g.value.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.value.Add(-1)
+}
+
+func (g *Gauge) Add(delta float64) {
+	g.value.Add(delta)
+}
+
+// Value returns the current value
+func (g *Gauge) Value() float64 {
+	return g.value.Load()
+}
+
+type GaugeVec struct {
+	vec *metricVec
+}
+
+// NewGaugeVec creates a gauge partitioned by the given labels
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{
+		vec: newMetricVec(name, help, labelNames, func() any { return &Gauge{} }),
+	}
+}
+
+// WithLabelValues returns the gauge for the label values, in the order of the label names
+func (g *GaugeVec) WithLabelValues(labelValues ...string) *Gauge {
+	return g.vec.child(labelValues).(*Gauge)
+}
+
+func (g *GaugeVec) Collect() []Family {
+	family := Family{Name: g.vec.name, Help: g.vec.help, Type: TypeGauge}
+	for _, child := range g.vec.sortedChildren() {
+		family.Samples = append(family.Samples, Sample{
+			Labels: child.labels,
+			Value:  child.metric.(*Gauge).Value(),
+		})
+	}
+	return []Family{family}
+}
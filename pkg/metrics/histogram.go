@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+var (
+	// DefBuckets are suited to HTTP and database latencies in seconds
+	DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+// Histogram counts observations in cumulative buckets
+type Histogram struct {
+	upperBounds []float64
+
+	mu     sync.Mutex
+	counts []uint64 // non cumulative, the last one is +Inf
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(upperBounds []float64) *Histogram {
+	return &Histogram{
+		upperBounds: upperBounds,
+		counts:      make([]uint64, len(upperBounds)+1),
+	}
+}
+
+// Observe adds a single observation
+func (h *Histogram) Observe(value float64) {
+	i := sort.SearchFloat64s(h.upperBounds, value)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[i]++
+	h.sum += value
+	h.count++
+}
+
+// samples returns the cumulative buckets, sum and count
+func (h *Histogram) samples(labels []Label) []Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := make([]Sample, 0, len(h.counts)+2)
+	var cumulative uint64
+	for i, count := range h.counts {
+		cumulative += count
+		upperBound := math.Inf(1)
+		if i < len(h.upperBounds) {
+			upperBound = h.upperBounds[i]
+		}
+		samples = append(samples, Sample{
+			Suffix: "_bucket",
+			Labels: withLabel(labels, Label{Name: "le", Value: formatFloat(upperBound)}),
+			Value:  float64(cumulative),
+		})
+	}
+
+	samples = append(samples,
+		Sample{Suffix: "_sum", Labels: labels, Value: h.sum},
+		Sample{Suffix: "_count", Labels: labels, Value: float64(h.count)},
+	)
+	return samples
+}
+
+type HistogramVec struct {
+	vec *metricVec
+}
+
+// NewHistogramVec creates a histogram partitioned by the given labels, nil buckets means DefBuckets
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	upperBounds := make([]float64, len(buckets))
+	copy(upperBounds, buckets)
+	sort.Float64s(upperBounds)
+
+	return &HistogramVec{
+		vec: newMetricVec(name, help, labelNames, func() any { return newHistogram(upperBounds) }),
+	}
+}
+
+// WithLabelValues returns the histogram for the label values, in the order of the label names
+func (h *HistogramVec) WithLabelValues(labelValues ...string) *Histogram {
+	return h.vec.child(labelValues).(*Histogram)
+}
+
+func (h *HistogramVec) Collect() []Family {
+	family := Family{Name: h.vec.name, Help: h.vec.help, Type: TypeHistogram}
+	for _, child := range h.vec.sortedChildren() {
+		family.Samples = append(family.Samples, child.metric.(*Histogram).samples(child.labels)...)
+	}
+	return []Family{family}
+}
+
+func withLabel(labels []Label, label Label) []Label {
+	result := make([]Label, 0, len(labels)+1)
+	result = append(result, labels...)
+	return append(result, label)
+}
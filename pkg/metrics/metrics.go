@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+
+	// ContentType is the Prometheus text exposition format
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var (
+	ErrAlreadyRegistered = errors.New("metric already registered")
+
+	// Default is the registry served on /metrics
+	Default = NewRegistry()
+)
+
+// Label is a metric dimension
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single value of a metric family. Suffix is appended to the family name, e.g. "_bucket".
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+// Family groups the samples of a metric sharing the same name, help and type
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Collector produces metric families when the registry is gathered
+type Collector interface {
+	Collect() []Family
+}
+
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+	names      map[string]struct{}
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		names: make(map[string]struct{}),
+	}
+}
+
+// Register adds a collector, its family names must not be registered yet
+func (r *Registry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	families := c.Collect()
+	for _, family := range families {
+		if _, ok := r.names[family.Name]; ok {
+			return errors.Wrapf(ErrAlreadyRegistered, "metric %s", family.Name)
+		}
+	}
+	for _, family := range families {
+		r.names[family.Name] = struct{}{}
+	}
+
+	r.collectors = append(r.collectors, c)
+	return nil
+}
+
+// MustRegister registers collectors and panics on error, it is meant for initialization
+func (r *Registry) MustRegister(cs ...Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Gather collects every family sorted by name, families without samples are skipped
+func (r *Registry) Gather() []Family {
+	r.mu.RLock()
+	collectors := make([]Collector, len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mu.RUnlock()
+
+	families := []Family{}
+	for _, c := range collectors {
+		for _, family := range c.Collect() {
+			if len(family.Samples) > 0 {
+				families = append(families, family)
+			}
+		}
+	}
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].Name < families[j].Name
+	})
+	return families
+}
+
+// WriteText writes every family in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, family := range r.Gather() {
+		writeFamily(bw, family)
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registry in the Prometheus text exposition format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		if err := r.WriteText(w); err != nil {
+			http.Error(w, fmt.Sprintf("failed to write metrics: %v", err), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+
+	requests := NewCounterVec("http_requests_total", "Total requests.", "method", "route")
+	inFlight := NewGaugeVec("http_requests_in_flight", "Requests in flight.", "route")
+	duration := NewHistogramVec("http_request_duration_seconds", "Request latency.", []float64{0.5, 0.1}, "route")
+	empty := NewCounterVec("empty_total", "Never incremented.", "label")
+	r.MustRegister(requests, inFlight, duration, empty)
+
+	requests.WithLabelValues("GET", "/users/:id").Inc()
+	requests.WithLabelValues("GET", "/users/:id").Add(2)
+	requests.WithLabelValues("GET", "/users/:id").Add(-5)
+	requests.WithLabelValues("DELETE", `/quote"back\slash`).Inc()
+	inFlight.WithLabelValues("/users").Inc()
+	inFlight.WithLabelValues("/users").Inc()
+	inFlight.WithLabelValues("/users").Dec()
+	duration.WithLabelValues("/users").Observe(0.05)
+	duration.WithLabelValues("/users").Observe(0.1)
+	duration.WithLabelValues("/users").Observe(3)
+
+	var sb strings.Builder
+	require.NoError(t, r.WriteText(&sb))
+
+	want := `# HELP http_request_duration_seconds Request latency.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{route="/users",le="0.1"} 2
+http_request_duration_seconds_bucket{route="/users",le="0.5"} 2
+http_request_duration_seconds_bucket{route="/users",le="+Inf"} 3
+http_request_duration_seconds_sum{route="/users"} 3.15
+http_request_duration_seconds_count{route="/users"} 3
+# HELP http_requests_in_flight Requests in flight.
+# TYPE http_requests_in_flight gauge
+http_requests_in_flight{route="/users"} 1
+# HELP http_requests_total Total requests.
+# TYPE http_requests_total counter
+http_requests_total{method="DELETE",route="/quote\"back\\slash"} 1
+http_requests_total{method="GET",route="/users/:id"} 3
+`
+	assert.Equal(t, want, sb.String())
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Register(NewCounterVec("requests_total", "Total requests.")))
+
+	err := r.Register(NewGaugeVec("requests_total", "Duplicate."))
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+
+	assert.Panics(t, func() {
+		r.MustRegister(NewCounterVec("requests_total", "Duplicate."))
+	})
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	gauge := NewGaugeVec("up", "Whether the service is up.")
+	r.MustRegister(gauge)
+	gauge.WithLabelValues().Set(1)
+
+	recorder := httptest.NewRecorder()
+	r.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, ContentType, recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "# HELP up Whether the service is up.\n# TYPE up gauge\nup 1\n", recorder.Body.String())
+}
+
+func TestMetricVec_WrongLabelCount(t *testing.T) {
+	counter := NewCounterVec("requests_total", "Total requests.", "method")
+
+	assert.Panics(t, func() {
+		counter.WithLabelValues("GET", "extra")
+	})
+}
+
+func TestFormatFloat(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{1, "1"},
+		{0.25, "0.25"},
+		{1e21, "1e+21"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatFloat(tt.value))
+		})
+	}
+}
+
+func TestCounter_Concurrent(t *testing.T) {
+	counter := NewCounterVec("requests_total", "Total requests.", "method")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				counter.WithLabelValues("GET").Inc()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, float64(5000), counter.WithLabelValues("GET").Value())
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"bufio"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// writeFamily writes the HELP and TYPE lines followed by every sample
+func writeFamily(w *bufio.Writer, family Family) {
+	w.WriteString("# HELP ")
+	w.WriteString(family.Name)
+	w.WriteByte(' ')
+	w.WriteString(helpEscaper.Replace(family.Help))
+	w.WriteString("\n# TYPE ")
+	w.WriteString(family.Name)
+	w.WriteByte(' ')
+	w.WriteString(family.Type)
+	w.WriteByte('\n')
+
+	for _, sample := range family.Samples {
+		w.WriteString(family.Name)
+		w.WriteString(sample.Suffix)
+		writeLabels(w, sample.Labels)
+		w.WriteByte(' ')
+		w.WriteString(formatFloat(sample.Value))
+		w.WriteByte('\n')
+	}
+}
+
+func writeLabels(w *bufio.Writer, labels []Label) {
+	if len(labels) == 0 {
+		return
+	}
+
+	w.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		w.WriteString(label.Name)
+		w.WriteString(`="`)
+		w.WriteString(labelValueEscaper.Replace(label.Value))
+		w.WriteByte('"')
+	}
+	w.WriteByte('}')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
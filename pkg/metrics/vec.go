@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// labelSeparator cannot appear in valid UTF-8 label values
+const labelSeparator = "\xff"
+
+// metricVec holds one child metric per distinct combination of label values
+type metricVec struct {
+	name       string
+	help       string
+	labelNames []string
+	newChild   func() any
+
+	mu       sync.RWMutex
+	children map[string]*vecChild
+}
+
+type vecChild struct {
+	labels []Label
+	metric any
+}
+
+func newMetricVec(name, help string, labelNames []string, newChild func() any) *metricVec {
+	return &metricVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		newChild:   newChild,
+		children:   make(map[string]*vecChild),
+	}
+}
+
+// child returns the metric for the label values, creating it on first use
+func (v *metricVec) child(labelValues []string) any {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metric %s: got %d label values, want %d", v.name, len(labelValues), len(v.labelNames)))
+	}
+
+	key := strings.Join(labelValues, labelSeparator)
+	v.mu.RLock()
+	c, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return c.metric
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if c, ok := v.children[key]; ok {
+		return c.metric
+	}
+
+	labels := make([]Label, len(labelValues))
+	for i, value := range labelValues {
+		labels[i] = Label{Name: v.labelNames[i], Value: value}
+	}
+	c = &vecChild{labels: labels, metric: v.newChild()}
+	v.children[key] = c
+	return c.metric
+}
+
+// sortedChildren returns the children sorted by label values for a stable output
+func (v *metricVec) sortedChildren() []*vecChild {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	children := make([]*vecChild, 0, len(keys))
+	for _, key := range keys {
+		children = append(children, v.children[key])
+	}
+	return children
+}
+
+// atomicFloat is a float64 safe for concurrent updates
+type atomicFloat struct {
+	bits uint64
+}
+
+func (f *atomicFloat) Add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&f.bits)
+		updated := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&f.bits, old, updated) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) Set(value float64) {
+	atomic.StoreUint64(&f.bits, math.Float64bits(value))
+}
+
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&f.bits))
+}
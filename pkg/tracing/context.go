@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+)
+
+type spanKey struct{}
+
+type remoteSpanContextKey struct{}
+
+func init() {
+	logger.RegisterContextFunc("trace_id", func(ctx context.Context) any {
+		if sc := SpanContextFromContext(ctx); sc.IsValid() {
+			return sc.TraceID.String()
+		}
+		return nil
+	})
+	logger.RegisterContextFunc("span_id", func(ctx context.Context) any {
+		if sc := SpanContextFromContext(ctx); sc.IsValid() {
+			return sc.SpanID.String()
+		}
+		return nil
+	})
+}
+
+// ContextWithSpan returns a new context carrying span as the current span
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the current span, or nil if there is none
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteSpanContext returns a new context carrying a parent received from another process
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	sc.Remote = true
+	return context.WithValue(ctx, remoteSpanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the current span IDs, falling back to the remote parent
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.SpanContext()
+	}
+	sc, _ := ctx.Value(remoteSpanContextKey{}).(SpanContext)
+	return sc
+}
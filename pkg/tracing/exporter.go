@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Exporter sends ended spans to a backend
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+// StdoutExporter writes every span as a JSON line, e.g. for local development
+type StdoutExporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// stdoutSpan is the JSON line written by StdoutExporter
+type stdoutSpan struct {
+	ServiceName   string         `json:"service_name,omitempty"`
+	Name          string         `json:"name"`
+	Kind          string         `json:"kind"`
+	TraceID       string         `json:"trace_id"`
+	SpanID        string         `json:"span_id"`
+	ParentSpanID  string         `json:"parent_span_id,omitempty"`
+	StartTime     time.Time      `json:"start_time"`
+	DurationMs    float64        `json:"duration_ms"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
+	Status        string         `json:"status"`
+	StatusMessage string         `json:"status_message,omitempty"`
+}
+
+// NewStdoutExporter creates an exporter writing to w
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{enc: json.NewEncoder(w)}
+}
+
+func (e *StdoutExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, span := range spans {
+		line := stdoutSpan{
+			ServiceName:   span.ServiceName,
+			Name:          span.Name,
+			Kind:          span.Kind.String(),
+			TraceID:       span.TraceID.String(),
+			SpanID:        span.SpanID.String(),
+			StartTime:     span.StartTime,
+			DurationMs:    float64(span.EndTime.Sub(span.StartTime).Microseconds()) / 1000,
+			Status:        span.Status.String(),
+			StatusMessage: span.StatusMessage,
+		}
+		if span.ParentSpanID.IsValid() {
+			line.ParentSpanID = span.ParentSpanID.String()
+		}
+		if len(span.Attributes) > 0 {
+			line.Attributes = make(map[string]any, len(span.Attributes))
+			for _, attr := range span.Attributes {
+				line.Attributes[attr.Key] = attr.Value
+			}
+		}
+
+		if err := e.enc.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *StdoutExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mockSpan = SpanData{
+		ServiceName:  "test-service",
+		Name:         "GET /users/:id",
+		Kind:         SpanKindServer,
+		TraceID:      mockTraceID,
+		SpanID:       SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		ParentSpanID: mockSpanID,
+		StartTime:    time.Date(2024, 7, 30, 0, 0, 0, 0, time.UTC),
+		EndTime:      time.Date(2024, 7, 30, 0, 0, 0, 1500000, time.UTC),
+		Attributes: []Attribute{
+			String("http.route", "/users/:id"),
+			Int64("http.response.status_code", 500),
+			Bool("retry", false),
+			Float64("ratio", 0.5),
+		},
+		Status:        StatusError,
+		StatusMessage: "Internal Server Error",
+	}
+)
+
+func TestStdoutExporter(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := NewStdoutExporter(&buf)
+
+	require.NoError(t, exporter.Export(context.Background(), []SpanData{mockSpan}))
+	require.NoError(t, exporter.Shutdown(context.Background()))
+
+	got := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "GET /users/:id", got["name"])
+	assert.Equal(t, "server", got["kind"])
+	assert.Equal(t, mockTraceID.String(), got["trace_id"])
+	assert.Equal(t, mockSpanID.String(), got["parent_span_id"])
+	assert.Equal(t, 1.5, got["duration_ms"])
+	assert.Equal(t, "error", got["status"])
+	assert.Equal(t, map[string]any{
+		"http.route":                "/users/:id",
+		"http.response.status_code": float64(500),
+		"retry":                     false,
+		"ratio":                     0.5,
+	}, got["attributes"])
+}
+
+func TestOTLPExporter_RoundTrip(t *testing.T) {
+	var gotHeader http.Header
+	var got []SpanData
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(r.Body)
+
+		var err error
+		got, err = DecodeOTLP(buf.Bytes())
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.URL, WithHeaders(map[string]string{"Authorization": "Bearer token"}))
+	root := mockSpan
+	root.ParentSpanID = SpanID{}
+
+	require.NoError(t, exporter.Export(context.Background(), []SpanData{mockSpan, root}))
+	require.NoError(t, exporter.Shutdown(context.Background()))
+
+	assert.Equal(t, "application/json", gotHeader.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", gotHeader.Get("Authorization"))
+	require.Len(t, got, 2)
+	assert.True(t, mockSpan.StartTime.Equal(got[0].StartTime))
+	assert.True(t, mockSpan.EndTime.Equal(got[0].EndTime))
+	got[0].StartTime, got[0].EndTime = mockSpan.StartTime, mockSpan.EndTime
+	assert.Equal(t, mockSpan, got[0])
+	assert.False(t, got[1].ParentSpanID.IsValid())
+}
+
+func TestOTLPExporter_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewOTLPExporter(server.URL).Export(context.Background(), []SpanData{mockSpan})
+	assert.ErrorContains(t, err, "503")
+}
+
+func TestDecodeOTLP_Invalid(t *testing.T) {
+	_, err := DecodeOTLP([]byte(`{"resourceSpans":[{"scopeSpans":[{"spans":[{"traceId":"zz"}]}]}]}`))
+	assert.Error(t, err)
+
+	_, err = DecodeOTLP([]byte(`not json`))
+	assert.Error(t, err)
+}
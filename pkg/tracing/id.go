@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// TraceID identifies a trace, it is shared by every span of a request
+type TraceID [16]byte
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+var (
+	generateTraceID = newTraceID
+	generateSpanID  = newSpanID
+)
+
+// IsValid reports whether the trace ID is not all zeros
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// IsValid reports whether the span ID is not all zeros
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// TraceIDFromHex parses a 32 characters lowercase hex trace ID
+func TraceIDFromHex(s string) (TraceID, error) {
+	id := TraceID{}
+	if err := decodeHex(id[:], s); err != nil {
+		return TraceID{}, err
+	}
+	if !id.IsValid() {
+		return TraceID{}, fmt.Errorf("trace id %q is all zeros", s)
+	}
+	return id, nil
+}
+
+// SpanIDFromHex parses a 16 characters lowercase hex span ID
+func SpanIDFromHex(s string) (SpanID, error) {
+	id := SpanID{}
+	if err := decodeHex(id[:], s); err != nil {
+		return SpanID{}, err
+	}
+	if !id.IsValid() {
+		return SpanID{}, fmt.Errorf("span id %q is all zeros", s)
+	}
+	return id, nil
+}
+
+// decodeHex only accepts lowercase hex as required by W3C trace context
+func decodeHex(dst []byte, s string) error {
+	if len(s) != hex.EncodedLen(len(dst)) {
+		return fmt.Errorf("id %q must be %d hex characters", s, hex.EncodedLen(len(dst)))
+	}
+	if !isLowerHex(s) {
+		return fmt.Errorf("id %q must be lowercase hex", s)
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'a' || s[i] > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func newTraceID() TraceID {
+	id := TraceID{}
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	id := SpanID{}
+	_, _ = rand.Read(id[:])
+	return id
+}
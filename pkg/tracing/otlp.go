@@ -0,0 +1,285 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// OTLPExporter sends spans to an OpenTelemetry collector using OTLP/HTTP with JSON encoding
+type OTLPExporter struct {
+	endpoint string
+	client   *http.Client
+	headers  map[string]string
+}
+
+// OTLPOption configures an OTLPExporter
+type OTLPOption func(e *OTLPExporter)
+
+// WithHTTPClient replaces the HTTP client, e.g. to configure TLS
+func WithHTTPClient(client *http.Client) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.client = client
+	}
+}
+
+// WithHeaders adds headers to every export request, e.g. for authentication
+func WithHeaders(headers map[string]string) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.headers = headers
+	}
+}
+
+// NewOTLPExporter creates an exporter posting to endpoint, e.g. http://localhost:4318/v1/traces
+func NewOTLPExporter(endpoint string, opts ...OTLPOption) *OTLPExporter {
+	e := &OTLPExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: exportTimeout},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+func (e *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := json.Marshal(encodeOTLP(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, val := range e.headers {
+		req.Header.Set(key, val)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("otlp export to %s failed with status %d", e.endpoint, resp.StatusCode)
+	}
+	return nil
+}
+
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// OTLP/JSON payload, see https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    StatusCode `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+// otlpAnyValue sets exactly one field, 64-bit integers are encoded as strings
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// otlpScopeName is the instrumentation scope reported with every span
+const otlpScopeName = "github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
+
+// encodeOTLP groups spans by service name into resource spans
+func encodeOTLP(spans []SpanData) otlpRequest {
+	req := otlpRequest{}
+	index := map[string]int{}
+
+	for _, span := range spans {
+		i, ok := index[span.ServiceName]
+		if !ok {
+			i = len(req.ResourceSpans)
+			index[span.ServiceName] = i
+			req.ResourceSpans = append(req.ResourceSpans, otlpResourceSpans{
+				Resource:   otlpResource{Attributes: []otlpKeyValue{toOTLPKeyValue(String("service.name", span.ServiceName))}},
+				ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: otlpScopeName}}},
+			})
+		}
+
+		s := otlpSpan{
+			TraceID:           span.TraceID.String(),
+			SpanID:            span.SpanID.String(),
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Status:            otlpStatus{Code: span.Status, Message: span.StatusMessage},
+		}
+		if span.ParentSpanID.IsValid() {
+			s.ParentSpanID = span.ParentSpanID.String()
+		}
+		for _, attr := range span.Attributes {
+			s.Attributes = append(s.Attributes, toOTLPKeyValue(attr))
+		}
+
+		scope := &req.ResourceSpans[i].ScopeSpans[0]
+		scope.Spans = append(scope.Spans, s)
+	}
+	return req
+}
+
+func toOTLPKeyValue(attr Attribute) otlpKeyValue {
+	value := otlpAnyValue{}
+	switch v := attr.Value.(type) {
+	case string:
+		value.StringValue = &v
+	case bool:
+		value.BoolValue = &v
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		value.IntValue = &s
+	case float64:
+		value.DoubleValue = &v
+	default:
+		s := fmt.Sprint(v)
+		value.StringValue = &s
+	}
+	return otlpKeyValue{Key: attr.Key, Value: value}
+}
+
+// DecodeOTLP parses an OTLP/JSON export request back into spans, e.g. for a collector stand-in in tests
+func DecodeOTLP(data []byte) ([]SpanData, error) {
+	req := otlpRequest{}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+
+	spans := []SpanData{}
+	for _, rs := range req.ResourceSpans {
+		serviceName := ""
+		for _, attr := range rs.Resource.Attributes {
+			if attr.Key == "service.name" && attr.Value.StringValue != nil {
+				serviceName = *attr.Value.StringValue
+			}
+		}
+
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				span, err := fromOTLPSpan(s)
+				if err != nil {
+					return nil, err
+				}
+				span.ServiceName = serviceName
+				spans = append(spans, span)
+			}
+		}
+	}
+	return spans, nil
+}
+
+func fromOTLPSpan(s otlpSpan) (SpanData, error) {
+	traceID, err := TraceIDFromHex(s.TraceID)
+	if err != nil {
+		return SpanData{}, err
+	}
+	spanID, err := SpanIDFromHex(s.SpanID)
+	if err != nil {
+		return SpanData{}, err
+	}
+	parentSpanID := SpanID{}
+	if s.ParentSpanID != "" {
+		if parentSpanID, err = SpanIDFromHex(s.ParentSpanID); err != nil {
+			return SpanData{}, err
+		}
+	}
+	start, err := strconv.ParseInt(s.StartTimeUnixNano, 10, 64)
+	if err != nil {
+		return SpanData{}, err
+	}
+	end, err := strconv.ParseInt(s.EndTimeUnixNano, 10, 64)
+	if err != nil {
+		return SpanData{}, err
+	}
+
+	span := SpanData{
+		Name:          s.Name,
+		Kind:          s.Kind,
+		TraceID:       traceID,
+		SpanID:        spanID,
+		ParentSpanID:  parentSpanID,
+		StartTime:     time.Unix(0, start),
+		EndTime:       time.Unix(0, end),
+		Status:        s.Status.Code,
+		StatusMessage: s.Status.Message,
+	}
+	for _, kv := range s.Attributes {
+		span.Attributes = append(span.Attributes, fromOTLPKeyValue(kv))
+	}
+	return span, nil
+}
+
+func fromOTLPKeyValue(kv otlpKeyValue) Attribute {
+	v := kv.Value
+	switch {
+	case v.StringValue != nil:
+		return String(kv.Key, *v.StringValue)
+	case v.BoolValue != nil:
+		return Bool(kv.Key, *v.BoolValue)
+	case v.IntValue != nil:
+		n, err := strconv.ParseInt(*v.IntValue, 10, 64)
+		if err != nil {
+			return String(kv.Key, *v.IntValue)
+		}
+		return Int64(kv.Key, n)
+	case v.DoubleValue != nil:
+		return Float64(kv.Key, *v.DoubleValue)
+	default:
+		return Attribute{Key: kv.Key}
+	}
+}
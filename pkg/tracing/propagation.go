@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// HeaderTraceparent is the W3C trace context header, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+const HeaderTraceparent = "traceparent"
+
+const (
+	traceparentVersion = "00"
+	flagSampled        = 0x01
+)
+
+var ErrInvalidTraceparent = errors.New("invalid traceparent")
+
+// ParseTraceparent parses a W3C traceparent header value into a remote span context
+func ParseTraceparent(value string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	version := parts[0]
+	if len(version) != 2 || version == "ff" || !isLowerHex(version) {
+		return SpanContext{}, fmt.Errorf("%w: version %q", ErrInvalidTraceparent, version)
+	}
+	// version 00 has exactly four fields, future versions may append more
+	if version == traceparentVersion && len(parts) != 4 {
+		return SpanContext{}, fmt.Errorf("%w: unexpected fields", ErrInvalidTraceparent)
+	}
+
+	traceID, err := TraceIDFromHex(parts[1])
+	if err != nil {
+		return SpanContext{}, fmt.Errorf("%w: %v", ErrInvalidTraceparent, err)
+	}
+	spanID, err := SpanIDFromHex(parts[2])
+	if err != nil {
+		return SpanContext{}, fmt.Errorf("%w: %v", ErrInvalidTraceparent, err)
+	}
+
+	flags := parts[3]
+	if len(flags) != 2 || !isLowerHex(flags) {
+		return SpanContext{}, fmt.Errorf("%w: flags %q", ErrInvalidTraceparent, flags)
+	}
+	flagByte, _ := strconv.ParseUint(flags, 16, 8)
+
+	return SpanContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: flagByte&flagSampled != 0,
+		Remote:  true,
+	}, nil
+}
+
+// FormatTraceparent formats sc as a version 00 traceparent header value
+func FormatTraceparent(sc SpanContext) string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("%s-%s-%s-%s", traceparentVersion, sc.TraceID, sc.SpanID, flags)
+}
+
+// Extract returns ctx carrying the remote parent of the traceparent header, an invalid header is ignored
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, err := ParseTraceparent(header.Get(HeaderTraceparent))
+	if err != nil {
+		return ctx
+	}
+	return ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Inject sets the traceparent header of the current span, e.g. on outgoing requests
+func Inject(ctx context.Context, header http.Header) {
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		header.Set(HeaderTraceparent, FormatTraceparent(sc))
+	}
+}
@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mockTraceID = TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	mockSpanID  = SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    SpanContext
+		wantErr bool
+	}{
+		{
+			name:  "success sampled",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want:  SpanContext{TraceID: mockTraceID, SpanID: mockSpanID, Sampled: true, Remote: true},
+		},
+		{
+			name:  "success not sampled",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			want:  SpanContext{TraceID: mockTraceID, SpanID: mockSpanID, Remote: true},
+		},
+		{
+			name:  "success future version with extra fields",
+			value: "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-09-extra",
+			want:  SpanContext{TraceID: mockTraceID, SpanID: mockSpanID, Sampled: true, Remote: true},
+		},
+		{name: "failed due to empty value", value: "", wantErr: true},
+		{name: "failed due to forbidden version", value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "failed due to extra fields in version 00", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", wantErr: true},
+		{name: "failed due to zero trace id", value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{name: "failed due to zero span id", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", wantErr: true},
+		{name: "failed due to uppercase hex", value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "failed due to short trace id", value: "00-4bf92f35-00f067aa0ba902b7-01", wantErr: true},
+		{name: "failed due to invalid flags", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-x1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTraceparent(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTraceparent)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatTraceparent(t *testing.T) {
+	sc := SpanContext{TraceID: mockTraceID, SpanID: mockSpanID, Sampled: true}
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", FormatTraceparent(sc))
+
+	sc.Sampled = false
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", FormatTraceparent(sc))
+}
+
+func TestExtractInject(t *testing.T) {
+	header := http.Header{}
+	header.Set(HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx := Extract(context.Background(), header)
+	assert.Equal(t, SpanContext{TraceID: mockTraceID, SpanID: mockSpanID, Sampled: true, Remote: true}, SpanContextFromContext(ctx))
+
+	out := http.Header{}
+	Inject(ctx, out)
+	assert.Equal(t, header.Get(HeaderTraceparent), out.Get(HeaderTraceparent))
+
+	header.Set(HeaderTraceparent, "invalid")
+	ctx = Extract(context.Background(), header)
+	assert.False(t, SpanContextFromContext(ctx).IsValid())
+
+	out = http.Header{}
+	Inject(ctx, out)
+	assert.Empty(t, out.Get(HeaderTraceparent))
+}
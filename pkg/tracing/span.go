@@ -0,0 +1,203 @@
+package tracing
+
+import (
+	"sync"
+	"time"
+)
+
+// SpanKind describes the relationship of the span with its caller, the values match OTLP
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota + 1
+	SpanKindServer
+	SpanKindClient
+)
+
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	default:
+		return "internal"
+	}
+}
+
+// StatusCode is the outcome of a span, the values match OTLP
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+func (c StatusCode) String() string {
+	switch c {
+	case StatusOK:
+		return "ok"
+	case StatusError:
+		return "error"
+	default:
+		return "unset"
+	}
+}
+
+// Attribute is a key value pair describing a span
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String creates a string attribute
+func String(key, val string) Attribute {
+	return Attribute{Key: key, Value: val}
+}
+
+// Int creates an integer attribute
+func Int(key string, val int) Attribute {
+	return Attribute{Key: key, Value: int64(val)}
+}
+
+// Int64 creates an integer attribute
+func Int64(key string, val int64) Attribute {
+	return Attribute{Key: key, Value: val}
+}
+
+// Bool creates a boolean attribute
+func Bool(key string, val bool) Attribute {
+	return Attribute{Key: key, Value: val}
+}
+
+// Float64 creates a floating point attribute
+func Float64(key string, val float64) Attribute {
+	return Attribute{Key: key, Value: val}
+}
+
+// SpanContext is the part of a span propagated across process boundaries
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	Remote  bool
+}
+
+// IsValid reports whether both IDs are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// SpanData is the immutable snapshot of an ended span handed to exporters
+type SpanData struct {
+	ServiceName   string
+	Name          string
+	Kind          SpanKind
+	TraceID       TraceID
+	SpanID        SpanID
+	ParentSpanID  SpanID
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    []Attribute
+	Status        StatusCode
+	StatusMessage string
+}
+
+// Span is a timed operation within a trace, it is safe for concurrent use
+type Span struct {
+	tracer      *Tracer
+	spanContext SpanContext
+	parent      SpanID
+
+	mu            sync.Mutex
+	name          string
+	kind          SpanKind
+	start         time.Time
+	attributes    []Attribute
+	status        StatusCode
+	statusMessage string
+	ended         bool
+}
+
+// SpanContext returns the IDs of the span
+func (s *Span) SpanContext() SpanContext {
+	return s.spanContext
+}
+
+// SetName replaces the span name, e.g. once the route pattern is known
+func (s *Span) SetName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.name = name
+}
+
+// SetAttributes adds attributes to the span, an existing key is overwritten
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, attr := range attrs {
+		replaced := false
+		for i := range s.attributes {
+			if s.attributes[i].Key == attr.Key {
+				s.attributes[i] = attr
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.attributes = append(s.attributes, attr)
+		}
+	}
+}
+
+// SetStatus sets the span status, an error status is never downgraded
+func (s *Span) SetStatus(code StatusCode, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.status == StatusError && code != StatusError {
+		return
+	}
+	s.status = code
+	s.statusMessage = message
+}
+
+// RecordError marks the span as failed with err, a nil error is ignored
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.SetStatus(StatusError, err.Error())
+}
+
+// End finishes the span and hands it to the tracer exporter, only the first call has an effect
+func (s *Span) End() {
+	end := timeNow()
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	data := SpanData{
+		ServiceName:   s.tracer.serviceName,
+		Name:          s.name,
+		Kind:          s.kind,
+		TraceID:       s.spanContext.TraceID,
+		SpanID:        s.spanContext.SpanID,
+		ParentSpanID:  s.parent,
+		StartTime:     s.start,
+		EndTime:       end,
+		Attributes:    append([]Attribute(nil), s.attributes...),
+		Status:        s.status,
+		StatusMessage: s.statusMessage,
+	}
+	s.mu.Unlock()
+
+	if s.spanContext.Sampled {
+		s.tracer.enqueue(data)
+	}
+}
@@ -0,0 +1,251 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+)
+
+const (
+	DefaultBatchSize     = 512
+	DefaultFlushInterval = 5 * time.Second
+
+	// exportTimeout bounds a single export call
+	exportTimeout = 10 * time.Second
+
+	// queueSize bounds the spans waiting for export, newer spans are dropped once it is full
+	queueSize = 2048
+)
+
+var (
+	timeNow = time.Now
+
+	defaultTracer atomic.Pointer[Tracer]
+)
+
+func init() {
+	defaultTracer.Store(NewTracer("", nil, nil))
+}
+
+// Tracer creates spans and exports the sampled ones in batches
+type Tracer struct {
+	serviceName   string
+	exporter      Exporter
+	appLogger     *logger.Logger
+	batchSize     int
+	flushInterval time.Duration
+
+	queue    chan SpanData
+	dropped  atomic.Int64
+	done     chan struct{}
+	stopped  chan struct{}
+	shutdown sync.Once
+}
+
+// Option configures a Tracer
+type Option func(t *Tracer)
+
+// WithBatchSize sets the number of spans sent per export
+func WithBatchSize(size int) Option {
+	return func(t *Tracer) {
+		if size > 0 {
+			t.batchSize = size
+		}
+	}
+}
+
+// WithFlushInterval sets the maximum delay before an ended span is exported
+func WithFlushInterval(interval time.Duration) Option {
+	return func(t *Tracer) {
+		if interval > 0 {
+			t.flushInterval = interval
+		}
+	}
+}
+
+// NewTracer creates a tracer for serviceName, a nil exporter creates spans without exporting them
+func NewTracer(serviceName string, exporter Exporter, log *logger.Logger, opts ...Option) *Tracer {
+	t := &Tracer{
+		serviceName:   serviceName,
+		exporter:      exporter,
+		appLogger:     log,
+		batchSize:     DefaultBatchSize,
+		flushInterval: DefaultFlushInterval,
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	if exporter == nil {
+		close(t.stopped)
+		return t
+	}
+
+	t.queue = make(chan SpanData, queueSize)
+	go t.run()
+	return t
+}
+
+// Default returns the tracer used by Start
+func Default() *Tracer {
+	return defaultTracer.Load()
+}
+
+// SetDefault replaces the tracer used by Start
+func SetDefault(t *Tracer) {
+	defaultTracer.Store(t)
+}
+
+// Start creates a span from the default tracer, or from the tracer of the current span
+func Start(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.tracer.Start(ctx, name, opts...)
+	}
+	return Default().Start(ctx, name, opts...)
+}
+
+// End records the error pointed by errp, if any, and ends span.
+// It is meant to be deferred with a named error result: defer tracing.End(span, &err)
+func End(span *Span, errp *error) {
+	if errp != nil {
+		span.RecordError(*errp)
+	}
+	span.End()
+}
+
+// SpanOption configures a span created by Start
+type SpanOption func(s *Span)
+
+// WithSpanKind sets the span kind, spans are internal by default
+func WithSpanKind(kind SpanKind) SpanOption {
+	return func(s *Span) {
+		s.kind = kind
+	}
+}
+
+// WithAttributes sets the initial span attributes
+func WithAttributes(attrs ...Attribute) SpanOption {
+	return func(s *Span) {
+		s.attributes = append(s.attributes, attrs...)
+	}
+}
+
+// Start creates a span as a child of the span or remote parent in ctx and returns a context carrying it.
+// A span without parent starts a new sampled trace, a child follows the parent sampling decision.
+func (t *Tracer) Start(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	parent := SpanContextFromContext(ctx)
+
+	span := &Span{
+		tracer: t,
+		name:   name,
+		kind:   SpanKindInternal,
+		start:  timeNow(),
+	}
+	for _, opt := range opts {
+		opt(span)
+	}
+
+	if parent.IsValid() {
+		span.spanContext = SpanContext{TraceID: parent.TraceID, Sampled: parent.Sampled}
+		span.parent = parent.SpanID
+	} else {
+		span.spanContext = SpanContext{TraceID: generateTraceID(), Sampled: true}
+	}
+	span.spanContext.SpanID = generateSpanID()
+
+	return ContextWithSpan(ctx, span), span
+}
+
+// Shutdown exports the queued spans and shuts the exporter down.
+// Spans ended afterwards are dropped.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t.exporter == nil {
+		return nil
+	}
+
+	t.shutdown.Do(func() {
+		close(t.done)
+	})
+
+	select {
+	case <-t.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return t.exporter.Shutdown(ctx)
+}
+
+// enqueue hands an ended span to the export loop without blocking the caller
+func (t *Tracer) enqueue(data SpanData) {
+	if t.exporter == nil {
+		return
+	}
+
+	select {
+	case <-t.done:
+		t.dropped.Add(1)
+	case t.queue <- data:
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+// run exports the queued spans once a batch is full or the flush interval elapses
+func (t *Tracer) run() {
+	defer close(t.stopped)
+
+	ticker := time.NewTicker(t.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		t.export(batch)
+		batch = make([]SpanData, 0, t.batchSize)
+	}
+
+	for {
+		select {
+		case data := <-t.queue:
+			batch = append(batch, data)
+			if len(batch) >= t.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-t.done:
+			for {
+				select {
+				case data := <-t.queue:
+					batch = append(batch, data)
+					if len(batch) >= t.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (t *Tracer) export(batch []SpanData) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	err := t.exporter.Export(ctx, batch)
+	if dropped := t.dropped.Swap(0); dropped > 0 && t.appLogger != nil {
+		t.appLogger.Warn("tracing queue is full, spans were dropped", logger.Int64Attr("dropped", dropped))
+	}
+	if err != nil && t.appLogger != nil {
+		t.appLogger.Error("failed to export spans", logger.ErrAttr(err), logger.Int64Attr("spans", int64(len(batch))))
+	}
+}
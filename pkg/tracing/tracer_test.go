@@ -0,0 +1,208 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mockErr = errors.New("mock error")
+)
+
+// memoryExporter keeps exported batches, tracetest.Recorder cannot be used due to the import cycle
+type memoryExporter struct {
+	mu      sync.Mutex
+	batches [][]SpanData
+	err     error
+}
+
+func (e *memoryExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.batches = append(e.batches, spans)
+	return e.err
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (e *memoryExporter) spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	spans := []SpanData{}
+	for _, batch := range e.batches {
+		spans = append(spans, batch...)
+	}
+	return spans
+}
+
+func TestTracer_Start(t *testing.T) {
+	exporter := &memoryExporter{}
+	tracer := NewTracer("test-service", exporter, nil)
+
+	ctx, root := tracer.Start(context.Background(), "root", WithSpanKind(SpanKindServer), WithAttributes(String("http.request.method", "GET")))
+	_, child := Start(ctx, "child")
+	child.SetAttributes(Int("rows", 1), Int("rows", 2), Bool("cached", false))
+	child.RecordError(mockErr)
+	child.SetStatus(StatusOK, "")
+	child.End()
+	child.End()
+	root.SetName("GET /users")
+	root.End()
+
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	spans := exporter.spans()
+	require.Len(t, spans, 2)
+
+	gotChild, gotRoot := spans[0], spans[1]
+	assert.Equal(t, "GET /users", gotRoot.Name)
+	assert.Equal(t, "test-service", gotRoot.ServiceName)
+	assert.Equal(t, SpanKindServer, gotRoot.Kind)
+	assert.False(t, gotRoot.ParentSpanID.IsValid())
+	assert.Equal(t, []Attribute{String("http.request.method", "GET")}, gotRoot.Attributes)
+
+	assert.Equal(t, "child", gotChild.Name)
+	assert.Equal(t, SpanKindInternal, gotChild.Kind)
+	assert.Equal(t, gotRoot.TraceID, gotChild.TraceID)
+	assert.Equal(t, gotRoot.SpanID, gotChild.ParentSpanID)
+	assert.Equal(t, []Attribute{Int("rows", 2), Bool("cached", false)}, gotChild.Attributes)
+	assert.Equal(t, StatusError, gotChild.Status, "error status must not be downgraded")
+	assert.Equal(t, mockErr.Error(), gotChild.StatusMessage)
+}
+
+func TestTracer_RemoteParent(t *testing.T) {
+	tests := []struct {
+		name       string
+		sampled    bool
+		wantSpans  int
+		wantParent SpanID
+	}{
+		{name: "sampled parent is exported", sampled: true, wantSpans: 1, wantParent: mockSpanID},
+		{name: "not sampled parent is dropped", sampled: false, wantSpans: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := &memoryExporter{}
+			tracer := NewTracer("test-service", exporter, nil)
+
+			ctx := ContextWithRemoteSpanContext(context.Background(), SpanContext{TraceID: mockTraceID, SpanID: mockSpanID, Sampled: tt.sampled})
+			_, span := tracer.Start(ctx, "server")
+			assert.Equal(t, mockTraceID, span.SpanContext().TraceID)
+			span.End()
+
+			require.NoError(t, tracer.Shutdown(context.Background()))
+
+			spans := exporter.spans()
+			require.Len(t, spans, tt.wantSpans)
+			if tt.wantSpans > 0 {
+				assert.Equal(t, tt.wantParent, spans[0].ParentSpanID)
+			}
+		})
+	}
+}
+
+func TestTracer_Batching(t *testing.T) {
+	exporter := &memoryExporter{}
+	tracer := NewTracer("test-service", exporter, nil, WithBatchSize(2), WithFlushInterval(time.Hour))
+
+	for i := 0; i < 5; i++ {
+		_, span := tracer.Start(context.Background(), "span")
+		span.End()
+	}
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	require.Len(t, exporter.batches, 3)
+	assert.Len(t, exporter.batches[0], 2)
+	assert.Len(t, exporter.batches[1], 2)
+	assert.Len(t, exporter.batches[2], 1)
+}
+
+func TestTracer_FlushInterval(t *testing.T) {
+	exporter := &memoryExporter{}
+	tracer := NewTracer("test-service", exporter, nil, WithFlushInterval(10*time.Millisecond))
+	defer tracer.Shutdown(context.Background())
+
+	_, span := tracer.Start(context.Background(), "span")
+	span.End()
+
+	assert.Eventually(t, func() bool { return len(exporter.spans()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestTracer_ExportError(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+	exporter := &memoryExporter{err: mockErr}
+	tracer := NewTracer("test-service", exporter, log)
+
+	_, span := tracer.Start(context.Background(), "span")
+	span.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	assert.Contains(t, buf.String(), "failed to export spans")
+
+	_, span = tracer.Start(context.Background(), "after shutdown")
+	span.End()
+	assert.Len(t, exporter.spans(), 1, "spans ended after shutdown are dropped")
+}
+
+func TestTracer_WithoutExporter(t *testing.T) {
+	tracer := NewTracer("test-service", nil, nil)
+
+	ctx, span := tracer.Start(context.Background(), "span")
+	span.End()
+
+	assert.True(t, SpanContextFromContext(ctx).IsValid())
+	assert.NoError(t, tracer.Shutdown(context.Background()))
+}
+
+func TestSetDefault(t *testing.T) {
+	tmpTracer := Default()
+	defer SetDefault(tmpTracer)
+
+	exporter := &memoryExporter{}
+	tracer := NewTracer("test-service", exporter, nil)
+	SetDefault(tracer)
+
+	ctx, span := Start(context.Background(), "root")
+	func() (err error) {
+		_, child := Start(ctx, "child")
+		defer End(child, &err)
+		return mockErr
+	}()
+	span.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	spans := exporter.spans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, StatusError, spans[0].Status)
+	assert.Equal(t, StatusUnset, spans[1].Status)
+}
+
+func TestLoggerContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(logger.NewContextHandler(&buf, logger.ContextHandlerOptions{}))
+
+	ctx, span := NewTracer("test-service", nil, nil).Start(context.Background(), "span")
+	log.InfoContext(ctx, "traced")
+
+	assert.Contains(t, buf.String(), `"trace_id":"`+span.SpanContext().TraceID.String()+`"`)
+	assert.Contains(t, buf.String(), `"span_id":"`+span.SpanContext().SpanID.String()+`"`)
+
+	buf.Reset()
+	log.InfoContext(context.Background(), "untraced")
+	assert.NotContains(t, buf.String(), "trace_id")
+}
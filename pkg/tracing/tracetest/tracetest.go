@@ -0,0 +1,109 @@
+// Package tracetest provides in-process stand-ins to assert on exported spans
+package tracetest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
+)
+
+// Recorder is an exporter keeping every exported span in memory
+type Recorder struct {
+	mu    sync.Mutex
+	spans []tracing.SpanData
+}
+
+// NewRecorder creates an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Export(ctx context.Context, spans []tracing.SpanData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func (r *Recorder) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Spans returns the exported spans in export order
+func (r *Recorder) Spans() []tracing.SpanData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]tracing.SpanData(nil), r.spans...)
+}
+
+// Collector is a local OTLP/HTTP collector, point tracing.NewOTLPExporter at Endpoint
+type Collector struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	spans   []tracing.SpanData
+	headers []http.Header
+}
+
+// NewCollector starts a collector accepting OTLP/JSON on /v1/traces
+func NewCollector() *Collector {
+	c := &Collector{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/traces", c.handle)
+	c.server = httptest.NewServer(mux)
+	return c
+}
+
+// Endpoint returns the URL to export spans to
+func (c *Collector) Endpoint() string {
+	return c.server.URL + "/v1/traces"
+}
+
+// Spans returns the received spans in arrival order
+func (c *Collector) Spans() []tracing.SpanData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]tracing.SpanData(nil), c.spans...)
+}
+
+// Headers returns the headers of every received export request
+func (c *Collector) Headers() []http.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]http.Header(nil), c.headers...)
+}
+
+// Close shuts the collector down
+func (c *Collector) Close() {
+	c.server.Close()
+}
+
+func (c *Collector) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	spans, err := tracing.DecodeOTLP(body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	c.spans = append(c.spans, spans...)
+	c.headers = append(c.headers, r.Header.Clone())
+	c.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+}
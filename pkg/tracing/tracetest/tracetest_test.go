@@ -0,0 +1,43 @@
+package tracetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raflynagachi/go-rest-api-starter/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector()
+	defer collector.Close()
+
+	tracer := tracing.NewTracer("test-service", tracing.NewOTLPExporter(collector.Endpoint()), nil)
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, child := tracing.Start(ctx, "child", tracing.WithAttributes(tracing.String("db.system", "postgresql")))
+	child.End()
+	root.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	spans := collector.Spans()
+	require.Len(t, spans, 2)
+	require.Len(t, collector.Headers(), 1)
+	assert.Equal(t, "application/json", collector.Headers()[0].Get("Content-Type"))
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, "test-service", spans[0].ServiceName)
+	assert.Equal(t, root.SpanContext().SpanID, spans[0].ParentSpanID)
+	assert.Equal(t, []tracing.Attribute{tracing.String("db.system", "postgresql")}, spans[0].Attributes)
+}
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+	tracer := tracing.NewTracer("test-service", recorder, nil)
+
+	_, span := tracer.Start(context.Background(), "span")
+	span.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	require.Len(t, recorder.Spans(), 1)
+	assert.Equal(t, "span", recorder.Spans()[0].Name)
+}